@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"errors"
+	"expvar"
 	"log/slog"
 	"net/http"
 	"os"
@@ -12,21 +13,41 @@ import (
 	"time"
 
 	"github.com/Deymos01/pr-review-manager/internal/config"
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/admin/export"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/admin/import_org"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/admin/restore"
+	retentionhandler "github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/admin/retention"
 	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/pull_requests/create"
+	prget "github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/pull_requests/get"
+	prlist "github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/pull_requests/list"
 	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/pull_requests/merge"
 	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/pull_requests/reassign"
+	prupdate "github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/pull_requests/update"
 	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/teams/add"
 	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/teams/deactivate"
 	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/teams/get"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/teams/list"
 	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/users/get_review"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/users/offboard"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/users/search"
 	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/users/set_is_active"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/users/stats"
 	mw "github.com/Deymos01/pr-review-manager/internal/httpserver/middlewares"
+	"github.com/Deymos01/pr-review-manager/internal/lib/tenant"
+	"github.com/Deymos01/pr-review-manager/internal/repository"
+	"github.com/Deymos01/pr-review-manager/internal/repository/cache"
+	"github.com/Deymos01/pr-review-manager/internal/repository/memory"
 	"github.com/Deymos01/pr-review-manager/internal/repository/postgres"
+	"github.com/Deymos01/pr-review-manager/internal/repository/sqlite"
+	"github.com/Deymos01/pr-review-manager/internal/usecase/org"
 	pr "github.com/Deymos01/pr-review-manager/internal/usecase/pull_request"
+	"github.com/Deymos01/pr-review-manager/internal/usecase/retention"
 	"github.com/Deymos01/pr-review-manager/internal/usecase/team"
 	"github.com/Deymos01/pr-review-manager/internal/usecase/user"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/redis/go-redis/v9"
 )
 
 const (
@@ -42,7 +63,7 @@ func main() {
 
 	log.Info("starting application", slog.String("env", cfg.Env))
 
-	storage, err := postgres.New(cfg.PostgresConfig)
+	storage, err := setupStorage(cfg)
 	if err != nil {
 		slog.Error("failed to initialize storage",
 			slog.String("env", cfg.Env),
@@ -50,9 +71,28 @@ func main() {
 		os.Exit(1)
 	}
 
-	teamService := team.New(log, storage)
-	userService := user.New(log, storage)
-	prService := pr.New(log, storage, storage)
+	log.Info("storage initialized", slog.String("storage", cfg.Storage))
+
+	storage, err = setupCache(cfg, storage)
+	if err != nil {
+		slog.Error("failed to initialize cache",
+			slog.String("env", cfg.Env),
+			slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	teamService := team.New(log, storage, storage)
+	userService := user.New(log, storage, storage, storage)
+	prService := pr.New(log, storage, storage, storage)
+	orgService := org.New(log, storage)
+	retentionService := retention.New(log, storage, domains.RetentionPolicy{
+		MaxAge:    time.Duration(cfg.RetentionConfig.Days) * 24 * time.Hour,
+		Mode:      domains.RetentionMode(cfg.RetentionConfig.Mode),
+		BatchSize: cfg.RetentionConfig.BatchSize,
+	})
+
+	idempotency := mw.IdempotencyMiddleware(log, storage, cfg.IdempotencyConfig.KeyTTL, cfg.IdempotencyConfig.LockTimeout)
+	adminTokens := cfg.AdminTokens()
 
 	router := chi.NewRouter()
 
@@ -62,29 +102,45 @@ func main() {
 	router.Use(middleware.URLFormat)
 
 	router.Route("/team", func(r chi.Router) {
-		r.Post("/add", add.New(log, teamService))
-
-		r.Group(func(r chi.Router) {
-			r.Use(mw.AdminAuthMiddleware(cfg.AdminToken))
-
-			r.Get("/get", get.New(log, teamService))
-			r.Post("/deactivate", deactivate.New(log, teamService))
-		})
+		r.With(mw.AdminAuthMiddleware(adminTokens)).
+			Post("/add", add.New(log, teamService))
+		r.With(mw.AdminAuthMiddleware(adminTokens)).
+			Get("/get", get.New(log, teamService))
+		r.With(mw.AdminAuthMiddleware(adminTokens)).
+			Get("/list", list.New(log, teamService))
+		r.With(mw.AdminOrUserAuthMiddleware(adminTokens, []byte(cfg.UserTokenSecret)), idempotency).
+			Post("/deactivate", deactivate.New(log, teamService))
 	})
 
 	router.Route("/users", func(r chi.Router) {
-		r.Use(mw.AdminAuthMiddleware(cfg.AdminToken))
+		r.Use(mw.AdminAuthMiddleware(adminTokens))
 
 		r.Post("/setIsActive", set_is_active.New(log, userService))
 		r.Get("/getReview", get_review.New(log, userService))
+		r.Get("/search", search.New(log, userService))
+		r.Get("/stats", stats.New(log, userService))
+		r.With(idempotency).Post("/offboard", offboard.New(log, userService))
 	})
 
 	router.Route("/pullRequest", func(r chi.Router) {
-		r.Use(mw.AdminAuthMiddleware(cfg.AdminToken))
+		r.Use(mw.AdminAuthMiddleware(adminTokens))
 
-		r.Post("/create", create.New(log, prService))
-		r.Post("/merge", merge.New(log, prService))
-		r.Post("/reassign", reassign.New(log, prService))
+		r.With(idempotency).Post("/create", create.New(log, prService))
+		r.With(idempotency).Post("/merge", merge.New(log, prService))
+		r.With(idempotency).Post("/reassign", reassign.New(log, prService))
+		r.Post("/update", prupdate.New(log, prService))
+		r.Get("/get", prget.New(log, prService))
+		r.Get("/list", prlist.New(log, prService))
+	})
+
+	router.Route("/admin", func(r chi.Router) {
+		r.Use(mw.AdminAuthMiddleware(adminTokens))
+
+		r.Post("/import", import_org.New(log, orgService))
+		r.Get("/export", export.New(log, orgService))
+		r.Post("/restore", restore.New(log, orgService))
+		r.Post("/retention", retentionhandler.New(log, retentionService))
+		r.Handle("/metrics", expvar.Handler())
 	})
 
 	addr := cfg.HTTPServerConfig.Host + ":" + strconv.Itoa(cfg.HTTPServerConfig.Port)
@@ -105,9 +161,126 @@ func main() {
 		}
 	}()
 
+	ctx, stopPurge := context.WithCancel(context.Background())
+	defer stopPurge()
+	go purgeIdempotencyKeys(ctx, log, storage, cfg.IdempotencyConfig.PurgeInterval)
+	if retentionService.Enabled() {
+		go runRetention(ctx, log, retentionService, cfg.Orgs(), cfg.RetentionConfig.Interval)
+	}
+
 	gracefulShutdown(context.Background(), srv, log)
 }
 
+// storage is everything the server needs from a repository backend.
+type storage interface {
+	team.TeamRepository
+	user.UserRepository
+	pr.UserRepository
+	pr.PullRequestRepository
+	org.OrgRepository
+	retention.RetentionRepository
+	repository.TxManager
+	mw.IdempotencyStore
+	PurgeIdempotencyKeys(ctx context.Context) (int64, error)
+}
+
+// repository.Storage lists the methods of the usecase repositories itself, so that
+// decorators do not depend on the usecases; this check keeps the two in step.
+var _ storage = repository.Storage(nil)
+
+func setupStorage(cfg *config.Config) (storage, error) {
+	switch cfg.Storage {
+	case config.StorageMemory:
+		return memory.New(), nil
+	case config.StorageSQLite:
+		return sqlite.New(cfg.SQLiteConfig)
+	}
+
+	pg, err := postgres.New(cfg.PostgresConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	expvar.Publish("postgres_tx", expvar.Func(func() any { return pg.TxStats() }))
+	expvar.Publish("postgres_pool", expvar.Func(func() any { return pg.PoolStats() }))
+
+	return pg, nil
+}
+
+// setupCache puts a read-through cache in front of storage unless it is disabled.
+func setupCache(cfg *config.Config, storage storage) (storage, error) {
+	var backend cache.Backend
+
+	switch cfg.CacheConfig.Backend {
+	case config.CacheNone:
+		return storage, nil
+	case config.CacheMemory:
+		backend = cache.NewLRU(cfg.CacheConfig.Size)
+	case config.CacheRedis:
+		client := redis.NewClient(&redis.Options{
+			Addr:     cfg.CacheConfig.RedisAddr,
+			Password: cfg.CacheConfig.RedisPassword,
+			DB:       cfg.CacheConfig.RedisDB,
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := client.Ping(ctx).Err(); err != nil {
+			return nil, err
+		}
+
+		backend = cache.NewRedis(client)
+	}
+
+	cached := cache.New(storage, backend, cfg.CacheConfig.TTL)
+	expvar.Publish("cache", expvar.Func(func() any { return cached.Stats() }))
+
+	return cached, nil
+}
+
+func purgeIdempotencyKeys(ctx context.Context, log *slog.Logger, storage storage, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := storage.PurgeIdempotencyKeys(ctx)
+			if err != nil {
+				log.Error("failed to purge idempotency keys", slog.Any("err", err))
+				continue
+			}
+			log.Debug("expired idempotency keys purged", slog.Int64("count", n))
+		}
+	}
+}
+
+// runRetention applies the retention policy to every organization on each tick.
+func runRetention(
+	ctx context.Context,
+	log *slog.Logger,
+	service *retention.Service,
+	orgs []string,
+	interval time.Duration,
+) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, org := range orgs {
+				// the service logs the outcome of every run
+				_, _ = service.Run(tenant.WithOrg(ctx, org), domains.RetentionPolicy{})
+			}
+		}
+	}
+}
+
 func setupLogger(env string) *slog.Logger {
 	var log *slog.Logger
 
@@ -2,13 +2,13 @@ package main
 
 import (
 	"errors"
-	"fmt"
 	"log"
 	"os"
 
 	"github.com/Deymos01/pr-review-manager/internal/config"
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 )
 
@@ -20,15 +20,20 @@ func main() {
 	direction := os.Args[1]
 	cfg := config.Load()
 
-	dsn := fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s",
-		cfg.PostgresConfig.User,
-		cfg.PostgresConfig.Password,
-		cfg.PostgresConfig.Host,
-		cfg.PostgresConfig.Port,
-		cfg.PostgresConfig.DBName,
-		cfg.PostgresConfig.SSLMode)
+	if cfg.Storage == config.StorageMemory {
+		log.Println("memory storage has no migrations to apply")
+		return
+	}
+
+	migrationsPath := cfg.MigrationsPath
+	dsn := cfg.PostgresConfig.URL()
+
+	if cfg.Storage == config.StorageSQLite {
+		migrationsPath = cfg.SQLiteConfig.MigrationsPath
+		dsn = "sqlite://" + cfg.SQLiteConfig.Path
+	}
 
-	m, err := migrate.New(cfg.MigrationsPath, dsn)
+	m, err := migrate.New(migrationsPath, dsn)
 	if err != nil {
 		log.Fatal(err)
 	}
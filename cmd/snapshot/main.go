@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+
+	"github.com/Deymos01/pr-review-manager/internal/config"
+	"github.com/Deymos01/pr-review-manager/internal/lib/snapshot"
+	"github.com/Deymos01/pr-review-manager/internal/repository/postgres"
+	"github.com/Deymos01/pr-review-manager/internal/repository/sqlite"
+	"github.com/Deymos01/pr-review-manager/internal/usecase/org"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal("Please provide a command: 'export [file]' or 'restore [file]'")
+	}
+
+	command := os.Args[1]
+	path := ""
+	if len(os.Args) > 2 {
+		path = os.Args[2]
+	}
+
+	cfg := config.Load()
+
+	// Memory storage lives inside the server process, use the /admin endpoints instead
+	if cfg.Storage == config.StorageMemory {
+		log.Fatal("snapshot tool requires postgres or sqlite storage")
+	}
+
+	var (
+		storage org.OrgRepository
+		err     error
+	)
+	if cfg.Storage == config.StorageSQLite {
+		storage, err = sqlite.New(cfg.SQLiteConfig)
+	} else {
+		storage, err = postgres.New(cfg.PostgresConfig)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Progress goes to stderr so that the snapshot itself can be piped through stdout
+	service := org.New(slog.New(slog.NewTextHandler(os.Stderr, nil)), storage)
+	ctx := context.Background()
+
+	switch command {
+	case "export":
+		var out io.Writer = os.Stdout
+		if path != "" {
+			f, err := os.Create(path)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer func() { _ = f.Close() }()
+			out = f
+		}
+
+		snap, err := service.Export(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := snapshot.Encode(out, snap); err != nil {
+			log.Fatal(err)
+		}
+		log.Println("Snapshot exported successfully.")
+	case "restore":
+		var in io.Reader = os.Stdin
+		if path != "" {
+			f, err := os.Open(path)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer func() { _ = f.Close() }()
+			in = f
+		}
+
+		snap, err := snapshot.Decode(in)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := service.Restore(ctx, snap); err != nil {
+			log.Fatal(err)
+		}
+		log.Println("Snapshot restored successfully.")
+	default:
+		log.Fatal("Invalid command. Use 'export' or 'restore'.")
+	}
+}
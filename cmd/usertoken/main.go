@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/config"
+	"github.com/Deymos01/pr-review-manager/internal/lib/usertoken"
+)
+
+// defaultTTL is how long an issued token stays valid unless another lifetime is given.
+const defaultTTL = 30 * 24 * time.Hour
+
+func main() {
+	if len(os.Args) < 3 {
+		log.Fatal("Please provide an organization and a user id: '<org> <user_id> [ttl]'")
+	}
+
+	org, userID := os.Args[1], os.Args[2]
+	ttl := defaultTTL
+	if len(os.Args) > 3 {
+		var err error
+		ttl, err = time.ParseDuration(os.Args[3])
+		if err != nil || ttl <= 0 {
+			log.Fatalf("invalid ttl %q", os.Args[3])
+		}
+	}
+
+	cfg := config.Load()
+	if cfg.UserTokenSecret == "" {
+		log.Fatal("http_server.user_token_secret is not set")
+	}
+
+	fmt.Println(usertoken.Sign([]byte(cfg.UserTokenSecret), org, userID, time.Now().Add(ttl)))
+}
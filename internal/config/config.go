@@ -2,17 +2,47 @@ package config
 
 import (
 	"log"
+	"net"
+	"net/url"
 	"os"
+	"strconv"
 	"time"
 
+	"github.com/Deymos01/pr-review-manager/internal/lib/tenant"
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
+// Storage backends selectable with the storage option.
+const (
+	StoragePostgres = "postgres"
+	// StorageSQLite keeps all data in a single database file next to the binary
+	StorageSQLite = "sqlite"
+	// StorageMemory keeps all data in process memory; it is meant for demos and tests
+	StorageMemory = "memory"
+)
+
+// Cache backends selectable with the cache.backend option.
+const (
+	CacheNone = "none"
+	// CacheMemory keeps entries in the memory of the process and only serves a single
+	// instance, since other instances would not see its invalidations
+	CacheMemory = "memory"
+	// CacheRedis shares entries and invalidations between instances
+	CacheRedis = "redis"
+)
+
 type Config struct {
-	Env              string `yaml:"env" env:"ENV" env-default:"local"`
-	HTTPServerConfig `yaml:"http_server"`
-	PostgresConfig   `yaml:"postgres"`
-	MigrationsPath   string `yaml:"migrations_path" env-default:"file://./migrations"`
+	Env               string `yaml:"env" env:"ENV" env-default:"local"`
+	Storage           string `yaml:"storage" env:"STORAGE" env-default:"postgres"`
+	HTTPServerConfig  `yaml:"http_server"`
+	PostgresConfig    `yaml:"postgres"`
+	SQLiteConfig      `yaml:"sqlite"`
+	IdempotencyConfig `yaml:"idempotency"`
+	RetentionConfig   `yaml:"retention"`
+	CacheConfig       `yaml:"cache"`
+	// Tenants are the organizations served next to the default one, which owns AdminToken
+	Tenants        []TenantConfig `yaml:"tenants"`
+	MigrationsPath string         `yaml:"migrations_path" env-default:"file://./migrations"`
 }
 
 type HTTPServerConfig struct {
@@ -21,15 +51,106 @@ type HTTPServerConfig struct {
 	Timeout     time.Duration `yaml:"timeout" env-default:"4s"`
 	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"60s"`
 	AdminToken  string        `yaml:"admin_token" env-required:"true"`
+	// UserTokenSecret signs the X-User-Token credentials of team members, see cmd/usertoken;
+	// when empty only admins are authenticated
+	UserTokenSecret string `yaml:"user_token_secret" env:"USER_TOKEN_SECRET"`
 }
 
 type PostgresConfig struct {
 	Host     string `yaml:"host" env-default:"localhost"`
 	Port     int    `yaml:"port" env-default:"5432"`
-	User     string `yaml:"user" env-required:"true"`
-	Password string `yaml:"password" env-required:"true"`
-	DBName   string `yaml:"dbname" env-required:"true"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	DBName   string `yaml:"dbname"`
 	SSLMode  string `yaml:"ssl_mode" env-default:"disable"`
+
+	// MaxConns and MinConns bound the size of the connection pool
+	MaxConns int32 `yaml:"max_conns" env-default:"10"`
+	MinConns int32 `yaml:"min_conns" env-default:"2"`
+	// MaxConnLifetime is how long a connection is reused before it is replaced
+	MaxConnLifetime time.Duration `yaml:"max_conn_lifetime" env-default:"1h"`
+	ConnectTimeout  time.Duration `yaml:"connect_timeout" env-default:"5s"`
+	// StatementTimeout aborts any statement running longer; zero disables the limit
+	StatementTimeout time.Duration `yaml:"statement_timeout" env-default:"30s"`
+	ApplicationName  string        `yaml:"application_name" env-default:"pr-review-manager"`
+}
+
+// URL returns the connection URL of the database. Credentials are escaped, so they may
+// contain any characters.
+func (c PostgresConfig) URL() string {
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(c.User, c.Password),
+		Host:     net.JoinHostPort(c.Host, strconv.Itoa(c.Port)),
+		Path:     "/" + c.DBName,
+		RawQuery: url.Values{"sslmode": {c.SSLMode}}.Encode(),
+	}
+
+	return u.String()
+}
+
+type SQLiteConfig struct {
+	Path           string `yaml:"path" env-default:"./pr_manager.db"`
+	MigrationsPath string `yaml:"migrations_path" env-default:"file://./migrations/sqlite"`
+}
+
+type IdempotencyConfig struct {
+	// KeyTTL is how long a stored response is replayed for the same Idempotency-Key
+	KeyTTL time.Duration `yaml:"key_ttl" env-default:"24h"`
+	// LockTimeout is how long a request holds its key while running. A retry may take over a
+	// key whose request never completed, e.g. because the instance crashed, once it passes
+	LockTimeout time.Duration `yaml:"lock_timeout" env-default:"30s"`
+	// PurgeInterval is how often expired keys are deleted from storage
+	PurgeInterval time.Duration `yaml:"purge_interval" env-default:"1h"`
+}
+
+// TenantConfig is an organization and the admin token that acts for it.
+type TenantConfig struct {
+	Org        string `yaml:"org"`
+	AdminToken string `yaml:"admin_token"`
+}
+
+// AdminTokens maps every admin token to the organization it acts for.
+func (c *Config) AdminTokens() map[string]string {
+	tokens := map[string]string{c.AdminToken: tenant.Default}
+	for _, t := range c.Tenants {
+		tokens[t.AdminToken] = t.Org
+	}
+	return tokens
+}
+
+// Orgs lists every organization served by the deployment.
+func (c *Config) Orgs() []string {
+	orgs := []string{tenant.Default}
+	for _, t := range c.Tenants {
+		orgs = append(orgs, t.Org)
+	}
+	return orgs
+}
+
+type RetentionConfig struct {
+	// Days is how long merged pull requests stay in the live tables; zero disables the job
+	Days int `yaml:"days" env:"RETENTION_DAYS" env-default:"0"`
+	// Mode is archive (move into the archive tables) or delete
+	Mode string `yaml:"mode" env-default:"archive"`
+	// Interval is how often the job runs
+	Interval time.Duration `yaml:"interval" env-default:"24h"`
+	// BatchSize is how many pull requests are removed per transaction
+	BatchSize int `yaml:"batch_size" env-default:"500"`
+}
+
+type CacheConfig struct {
+	Backend string `yaml:"backend" env:"CACHE_BACKEND" env-default:"none"`
+	// TTL bounds how long an entry is served
+	TTL time.Duration `yaml:"ttl" env-default:"30s"`
+	// Size is how many entries the memory backend holds
+	Size          int    `yaml:"size" env-default:"10000"`
+	RedisAddr     string `yaml:"redis_addr" env-default:"localhost:6379"`
+	RedisPassword string `yaml:"redis_password"`
+	RedisDB       int    `yaml:"redis_db" env-default:"0"`
+	// Instances is how many instances of the service share the storage. The memory backend
+	// cannot tell other instances about invalidations, so it is refused when there are several
+	Instances int `yaml:"instances" env:"CACHE_INSTANCES" env-default:"1"`
 }
 
 func Load() *Config {
@@ -48,5 +169,50 @@ func Load() *Config {
 		log.Fatalf("cannot read config: %s", err)
 	}
 
+	switch cfg.Storage {
+	case StoragePostgres:
+		// Postgres credentials are only required when the database is actually used
+		if cfg.PostgresConfig.User == "" || cfg.PostgresConfig.Password == "" || cfg.PostgresConfig.DBName == "" {
+			log.Fatal("cannot read config: postgres user, password and dbname are required")
+		}
+	case StorageSQLite, StorageMemory:
+	default:
+		log.Fatalf("cannot read config: unknown storage %q", cfg.Storage)
+	}
+
+	orgs := map[string]bool{tenant.Default: true}
+	tokens := map[string]bool{cfg.AdminToken: true}
+	for _, t := range cfg.Tenants {
+		if t.Org == "" || t.AdminToken == "" {
+			log.Fatal("cannot read config: every tenant needs an org and an admin_token")
+		}
+		if orgs[t.Org] || tokens[t.AdminToken] {
+			log.Fatalf("cannot read config: tenant %q repeats an org or an admin token", t.Org)
+		}
+		orgs[t.Org] = true
+		tokens[t.AdminToken] = true
+	}
+
+	switch cfg.RetentionConfig.Mode {
+	case "archive", "delete":
+	default:
+		log.Fatalf("cannot read config: unknown retention mode %q", cfg.RetentionConfig.Mode)
+	}
+	if cfg.RetentionConfig.Days < 0 || cfg.RetentionConfig.BatchSize <= 0 {
+		log.Fatal("cannot read config: retention days must not be negative and batch_size must be positive")
+	}
+
+	switch cfg.CacheConfig.Backend {
+	case CacheNone, CacheMemory, CacheRedis:
+	default:
+		log.Fatalf("cannot read config: unknown cache backend %q", cfg.CacheConfig.Backend)
+	}
+	if cfg.CacheConfig.Backend != CacheNone && (cfg.CacheConfig.TTL <= 0 || cfg.CacheConfig.Size <= 0) {
+		log.Fatal("cannot read config: cache ttl and size must be positive")
+	}
+	if cfg.CacheConfig.Backend == CacheMemory && cfg.CacheConfig.Instances > 1 {
+		log.Fatal("cannot read config: the memory cache serves a single instance, use redis for several")
+	}
+
 	return &cfg
 }
@@ -0,0 +1,12 @@
+package domains
+
+// IdempotencyRecord is the stored outcome of a request sent with an Idempotency-Key.
+type IdempotencyRecord struct {
+	RequestHash string
+	// Completed is false while the first request with the key is still being processed.
+	Completed  bool
+	StatusCode int
+	// Header holds the response headers that are replayed along with the body.
+	Header map[string]string
+	Body   []byte
+}
@@ -0,0 +1,17 @@
+package domains
+
+type UserChange struct {
+	Before *User
+	After  *User
+}
+
+type ImportReport struct {
+	DryRun       bool
+	CreatedTeams []string
+	Created      []*User
+	Updated      []*UserChange
+	Moved        []*UserChange
+	Unchanged    int
+	// Reassigned lists the open reviews released by members the import deactivated
+	Reassigned []*ReassignedPR
+}
@@ -0,0 +1,96 @@
+package domains
+
+import "time"
+
+// Cursor points at the last row of a page in keyset order: Key is the value of the
+// sort column and ID breaks ties between rows sharing it.
+type Cursor struct {
+	Key string `json:"k"`
+	ID  string `json:"id"`
+}
+
+type UserSort string
+
+const (
+	UserSortByID   UserSort = "id"
+	UserSortByName UserSort = "name"
+)
+
+func (s UserSort) Valid() bool {
+	switch s {
+	case UserSortByID, UserSortByName:
+		return true
+	}
+	return false
+}
+
+type UserFilter struct {
+	IsActive   *bool
+	TeamName   *string
+	NamePrefix string
+	SortBy     UserSort
+	Desc       bool
+	After      *Cursor
+	Limit      int
+}
+
+type UserPage struct {
+	Users []*User
+	Next  *Cursor
+}
+
+type TeamFilter struct {
+	// HasActive keeps teams with (true) or without (false) active members.
+	HasActive  *bool
+	NamePrefix string
+	Desc       bool
+	After      *Cursor
+	Limit      int
+}
+
+type TeamSummary struct {
+	Name          string
+	MembersCount  int
+	ActiveMembers int
+}
+
+type TeamPage struct {
+	Teams []*TeamSummary
+	Next  *Cursor
+}
+
+type ReviewFilter struct {
+	Status   PRStatus
+	Since    *time.Time
+	AuthorID string
+	Desc     bool
+	After    *Cursor
+	Limit    int
+}
+
+type ReviewPage struct {
+	Reviews []*Review
+	Next    *Cursor
+}
+
+// PullRequestFilter narrows a pull request listing. Time ranges include the lower
+// bound and exclude the upper one; TeamName matches the author's team.
+type PullRequestFilter struct {
+	Status            PRStatus
+	TeamName          string
+	AuthorID          string
+	ReviewerID        string
+	CreatedFrom       *time.Time
+	CreatedTo         *time.Time
+	MergedFrom        *time.Time
+	MergedTo          *time.Time
+	NeedMoreReviewers *bool
+	Desc              bool
+	After             *Cursor
+	Limit             int
+}
+
+type PullRequestPage struct {
+	PullRequests []*PullRequest
+	Next         *Cursor
+}
@@ -4,15 +4,48 @@ import (
 	"time"
 )
 
+// PRStatus is the lifecycle state of a pull request.
+type PRStatus string
+
+const (
+	PRStatusOpen   PRStatus = "OPEN"
+	PRStatusMerged PRStatus = "MERGED"
+)
+
+// PRStatuses lists every status a pull request may have.
+var PRStatuses = []PRStatus{PRStatusOpen, PRStatusMerged}
+
+func (s PRStatus) Valid() bool {
+	switch s {
+	case PRStatusOpen, PRStatusMerged:
+		return true
+	}
+	return false
+}
+
 type PullRequest struct {
 	ID                string
 	Name              string
+	Description       string
+	Labels            []string
 	Author            *User
 	Reviewers         []*Reviewer
-	Status            string
+	Status            PRStatus
 	NeedMoreReviewers bool
 	CreatedAt         time.Time
 	MergedAt          *time.Time
+	// Version grows with every change of the pull request and guards concurrent updates.
+	Version int64
+}
+
+// PullRequestUpdate lists the fields to change; nil fields are left as they are.
+// Version must match the stored one for the update to apply.
+type PullRequestUpdate struct {
+	Name        *string
+	Description *string
+	Labels      *[]string
+	AuthorID    *string
+	Version     int64
 }
 
 type ReassignedPR struct {
@@ -0,0 +1,48 @@
+package domains
+
+import "time"
+
+// RetentionMode says what happens to merged pull requests once they outlive the retention period.
+type RetentionMode string
+
+const (
+	// RetentionArchive moves pull requests and their reviewers into the archive tables
+	RetentionArchive RetentionMode = "archive"
+	// RetentionDelete drops them for good
+	RetentionDelete RetentionMode = "delete"
+)
+
+func (m RetentionMode) Valid() bool {
+	switch m {
+	case RetentionArchive, RetentionDelete:
+		return true
+	}
+	return false
+}
+
+// RetentionPolicy selects the merged pull requests removed from the live tables.
+type RetentionPolicy struct {
+	// MaxAge is how long a pull request stays live after it was merged
+	MaxAge    time.Duration
+	Mode      RetentionMode
+	BatchSize int
+}
+
+// RetentionReport describes what a retention run removed from the live tables.
+type RetentionReport struct {
+	Mode         RetentionMode
+	MergedBefore time.Time
+	PullRequests int
+	Reviews      int
+	Batches      int
+	StartedAt    time.Time
+	FinishedAt   time.Time
+}
+
+// ReviewStats are the all-time totals of a user. Pull requests removed by retention
+// still count.
+type ReviewStats struct {
+	UserID   string
+	Authored int64
+	Reviewed int64
+}
@@ -8,3 +8,9 @@ type Reviewer struct {
 	User       *User
 	AssignedAt time.Time
 }
+
+// Review is a pull request seen from the side of one of its reviewers.
+type Review struct {
+	PullRequest *PullRequest
+	AssignedAt  time.Time
+}
@@ -0,0 +1,25 @@
+package domains
+
+import (
+	"time"
+)
+
+// Snapshot is the full state of the service: every team, user, pull request and its reviewers,
+// along with what retention moved out of the live tables.
+type Snapshot struct {
+	CreatedAt    time.Time
+	Statuses     []PRStatus
+	Teams        []string
+	Users        []*User
+	PullRequests []*PullRequest
+	// ArchivedPullRequests are the merged pull requests retention moved to the archive
+	ArchivedPullRequests []*ArchivedPullRequest
+	// ReviewStats hold the counts retention kept for the pull requests it removed
+	ReviewStats []*ReviewStats
+}
+
+// ArchivedPullRequest is a merged pull request with its reviewers as retention archived it.
+type ArchivedPullRequest struct {
+	PullRequest *PullRequest
+	ArchivedAt  time.Time
+}
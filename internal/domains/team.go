@@ -3,4 +3,6 @@ package domains
 type Team struct {
 	Name    string
 	Members []*User
+	// Version grows on every change of the team or its members' activity
+	Version int64
 }
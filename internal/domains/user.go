@@ -1,8 +1,41 @@
 package domains
 
+import "time"
+
+// DeletedUserName replaces the name of an offboarded user.
+const DeletedUserName = "deleted user"
+
+type Role string
+
+const (
+	RoleLead     Role = "lead"
+	RoleMember   Role = "member"
+	RoleObserver Role = "observer"
+)
+
+func (r Role) Valid() bool {
+	switch r {
+	case RoleLead, RoleMember, RoleObserver:
+		return true
+	}
+	return false
+}
+
 type User struct {
 	ID       string
 	Name     string
 	TeamName *string
 	IsActive bool
+	Role     Role
+	// DeletedAt is set once the user is offboarded. Deleted users are kept only for the
+	// history of their pull requests and reviews.
+	DeletedAt *time.Time
+}
+
+// StatusOptions tune the side effects of changing user activity.
+type StatusOptions struct {
+	// Rebalance moves a fair share of open reviews back to a reactivated user.
+	Rebalance bool
+	// KeepReviews leaves a deactivated user assigned to their open reviews.
+	KeepReviews bool
 }
@@ -0,0 +1,46 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/response"
+	"github.com/Deymos01/pr-review-manager/internal/lib/snapshot"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.53.5 --name=OrgService
+type OrgService interface {
+	Export(ctx context.Context) (*domains.Snapshot, error)
+}
+
+func New(
+	log *slog.Logger,
+	service OrgService,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.admin.export.New"
+		log = log.With(slog.String("op", op))
+
+		snap, err := service.Export(r.Context())
+		if err != nil {
+			log.Error("failed to export snapshot", slog.Any("error", err))
+
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).
+				Encode(response.NewErrorResponse(handlers.InternalError, "internal server error"))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition",
+			`attachment; filename="snapshot-`+snap.CreatedAt.UTC().Format("20060102T150405Z")+`.json"`)
+		w.WriteHeader(http.StatusOK)
+		if err := snapshot.Encode(w, snap); err != nil {
+			log.Error("failed to encode snapshot", slog.Any("error", err))
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domains "github.com/Deymos01/pr-review-manager/internal/domains"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OrgService is an autogenerated mock type for the OrgService type
+type OrgService struct {
+	mock.Mock
+}
+
+// Export provides a mock function with given fields: ctx
+func (_m *OrgService) Export(ctx context.Context) (*domains.Snapshot, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Export")
+	}
+
+	var r0 *domains.Snapshot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*domains.Snapshot, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *domains.Snapshot); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.Snapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewOrgService creates a new instance of OrgService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOrgService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OrgService {
+	mock := &OrgService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
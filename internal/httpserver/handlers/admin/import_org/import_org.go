@@ -0,0 +1,181 @@
+package import_org
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/response"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
+)
+
+const maxBodySize = 10 << 20
+
+//go:generate go run github.com/vektra/mockery/v2@v2.53.5 --name=OrgService
+type OrgService interface {
+	Import(ctx context.Context, teams []*domains.Team, dryRun bool) (*domains.ImportReport, error)
+}
+
+type Member struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	TeamName string `json:"team_name"`
+	IsActive bool   `json:"is_active"`
+	Role     string `json:"role"`
+}
+
+type Change struct {
+	UserID string `json:"user_id"`
+	Before Member `json:"before"`
+	After  Member `json:"after"`
+}
+
+type ReassignedPR struct {
+	PrID      string `json:"pull_request_id"`
+	OldUserID string `json:"old_reviewer_id"`
+	NewUserID string `json:"replaced_by"`
+}
+
+type Response struct {
+	DryRun       bool     `json:"dry_run"`
+	TeamsCreated []string `json:"teams_created"`
+	Users        struct {
+		Created   []Member `json:"created"`
+		Updated   []Change `json:"updated"`
+		Moved     []Change `json:"moved"`
+		Unchanged int      `json:"unchanged"`
+	} `json:"users"`
+	// PRs lists the open reviews handed over by members the import deactivated
+	PRs []ReassignedPR `json:"pull_requests"`
+}
+
+func New(
+	log *slog.Logger,
+	service OrgService,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.admin.import_org.New"
+		log = log.With(slog.String("op", op))
+
+		dryRun := false
+		if v := r.URL.Query().Get("dry_run"); v != "" {
+			var err error
+			if dryRun, err = strconv.ParseBool(v); err != nil {
+				log.Warn("invalid dry_run parameter", slog.String("dry_run", v))
+
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.InvalidRequest, "dry_run must be a boolean"))
+				return
+			}
+		}
+
+		body := http.MaxBytesReader(w, r.Body, maxBodySize)
+
+		var (
+			teams []*domains.Team
+			err   error
+		)
+		switch detectFormat(r.URL.Query().Get("format"), r.Header.Get("Content-Type")) {
+		case formatYAML:
+			teams, err = parseYAML(body)
+		case formatCSV:
+			teams, err = parseCSV(body)
+		default:
+			log.Warn("unsupported import format", slog.String("content_type", r.Header.Get("Content-Type")))
+
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).
+				Encode(response.NewErrorResponse(handlers.InvalidRequest, "format must be yaml or csv"))
+			return
+		}
+		if err != nil {
+			log.Warn("invalid org chart", slog.Any("error", err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).
+				Encode(response.NewErrorResponse(handlers.InvalidRequest, "invalid org chart: "+err.Error()))
+			return
+		}
+
+		report, err := service.Import(r.Context(), teams, dryRun)
+		if err != nil {
+			if errors.Is(err, usecase.ErrInvalidImport) {
+				log.Warn("org chart validation failed", slog.Any("error", err))
+
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.InvalidRequest, err.Error()))
+				return
+			}
+			if errors.Is(err, usecase.ErrUserDeleted) {
+				log.Warn("org chart references deleted user", slog.Any("error", err))
+
+				w.WriteHeader(http.StatusConflict)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.UserDeleted, "org chart references a deleted user"))
+				return
+			}
+			log.Error("failed to import org chart", slog.Any("error", err))
+
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).
+				Encode(response.NewErrorResponse(handlers.InternalError, "internal server error"))
+			return
+		}
+
+		var resp Response
+		resp.DryRun = report.DryRun
+		resp.TeamsCreated = append([]string{}, report.CreatedTeams...)
+		resp.Users.Created = make([]Member, 0, len(report.Created))
+		for _, u := range report.Created {
+			resp.Users.Created = append(resp.Users.Created, toMember(u))
+		}
+		resp.Users.Updated = toChanges(report.Updated)
+		resp.Users.Moved = toChanges(report.Moved)
+		resp.Users.Unchanged = report.Unchanged
+		resp.PRs = make([]ReassignedPR, 0, len(report.Reassigned))
+		for _, r := range report.Reassigned {
+			resp.PRs = append(resp.PRs, ReassignedPR{
+				PrID:      r.PrID,
+				OldUserID: r.OldUserID,
+				NewUserID: r.NewUserID,
+			})
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.Error("failed to encode response", slog.Any("error", err))
+		}
+	}
+}
+
+func toMember(u *domains.User) Member {
+	m := Member{
+		UserID:   u.ID,
+		Username: u.Name,
+		IsActive: u.IsActive,
+		Role:     string(u.Role),
+	}
+	if u.TeamName != nil {
+		m.TeamName = *u.TeamName
+	}
+	return m
+}
+
+func toChanges(changes []*domains.UserChange) []Change {
+	out := make([]Change, 0, len(changes))
+	for _, c := range changes {
+		out = append(out, Change{
+			UserID: c.After.ID,
+			Before: toMember(c.Before),
+			After:  toMember(c.After),
+		})
+	}
+	return out
+}
@@ -0,0 +1,219 @@
+package import_org_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/admin/import_org"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/admin/import_org/mocks"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+const yamlChart = `
+teams:
+  - team_name: backend
+    members:
+      - user_id: u1
+        username: Alice
+        role: lead
+      - user_id: u2
+        username: Bob
+        is_active: false
+`
+
+const csvChart = `team_name,user_id,username,is_active,role
+backend,u1,Alice,,lead
+backend,u2,Bob,false,
+`
+
+func TestImportHandler(t *testing.T) {
+	type testCase struct {
+		name        string
+		body        string
+		contentType string
+		query       string
+
+		callService bool
+		dryRun      bool
+		mockReport  *domains.ImportReport
+		mockError   error
+
+		expectedStatus int
+		expectedErr    string
+	}
+
+	report := &domains.ImportReport{
+		CreatedTeams: []string{"backend"},
+		Created: []*domains.User{
+			{ID: "u1", Name: "Alice", TeamName: ptr("backend"), IsActive: true, Role: domains.RoleLead},
+		},
+		Moved: []*domains.UserChange{
+			{
+				Before: &domains.User{ID: "u2", Name: "Bob", TeamName: ptr("frontend"), IsActive: true, Role: domains.RoleMember},
+				After:  &domains.User{ID: "u2", Name: "Bob", TeamName: ptr("backend"), IsActive: false, Role: domains.RoleMember},
+			},
+		},
+		Reassigned: []*domains.ReassignedPR{{PrID: "pr1", OldUserID: "u2", NewUserID: "u1"}},
+	}
+
+	cases := []testCase{
+		{
+			name:           "YAML",
+			body:           yamlChart,
+			contentType:    "application/yaml",
+			callService:    true,
+			mockReport:     report,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "CSV dry run",
+			body:           csvChart,
+			contentType:    "text/csv",
+			query:          "?dry_run=true",
+			callService:    true,
+			dryRun:         true,
+			mockReport:     report,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Format from query",
+			body:           csvChart,
+			contentType:    "text/plain",
+			query:          "?format=csv",
+			callService:    true,
+			mockReport:     report,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Unsupported format",
+			body:           `{}`,
+			contentType:    "application/json",
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "format must be yaml or csv",
+		},
+		{
+			name:           "Invalid dry_run",
+			body:           yamlChart,
+			contentType:    "application/yaml",
+			query:          "?dry_run=maybe",
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "dry_run must be a boolean",
+		},
+		{
+			name:           "Malformed CSV",
+			body:           "team_name,username\nbackend,Alice\n",
+			contentType:    "text/csv",
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    `invalid org chart: missing column "user_id"`,
+		},
+		{
+			name:           "Unknown YAML field",
+			body:           "teams:\n  - name: backend\n",
+			contentType:    "application/yaml",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Validation failed",
+			body:           yamlChart,
+			contentType:    "application/yaml",
+			callService:    true,
+			mockError:      fmt.Errorf("%w: user %q: username is empty", usecase.ErrInvalidImport, "u1"),
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    `invalid import: user "u1": username is empty`,
+		},
+		{
+			name:           "Deleted user",
+			body:           yamlChart,
+			contentType:    "application/yaml",
+			callService:    true,
+			mockError:      usecase.ErrUserDeleted,
+			expectedStatus: http.StatusConflict,
+			expectedErr:    "org chart references a deleted user",
+		},
+		{
+			name:           "Unknown error",
+			body:           yamlChart,
+			contentType:    "application/yaml",
+			callService:    true,
+			mockError:      errors.New("unexpected"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedErr:    "internal server error",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := mocks.NewOrgService(t)
+
+			if tc.callService {
+				svc.On("Import", mock.Anything, mock.MatchedBy(func(teams []*domains.Team) bool {
+					return len(teams) == 1 &&
+						teams[0].Name == "backend" &&
+						len(teams[0].Members) == 2 &&
+						teams[0].Members[0].IsActive &&
+						teams[0].Members[0].Role == domains.RoleLead &&
+						!teams[0].Members[1].IsActive
+				}), tc.dryRun).Return(tc.mockReport, tc.mockError).Once()
+			}
+
+			handler := import_org.New(discardLogger(), svc)
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/import"+tc.query, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", tc.contentType)
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			if tc.expectedStatus != http.StatusOK {
+				errResp := resp["error"].(map[string]any)
+				if tc.expectedErr != "" {
+					require.Equal(t, tc.expectedErr, errResp["message"])
+				}
+				return
+			}
+
+			require.Equal(t, []any{"backend"}, resp["teams_created"])
+
+			users := resp["users"].(map[string]any)
+			require.Len(t, users["created"].([]any), 1)
+			require.Len(t, users["updated"].([]any), 0)
+
+			moved := users["moved"].([]any)
+			require.Len(t, moved, 1)
+			change := moved[0].(map[string]any)
+			require.Equal(t, "u2", change["user_id"])
+			require.Equal(t, "frontend", change["before"].(map[string]any)["team_name"])
+			require.Equal(t, "backend", change["after"].(map[string]any)["team_name"])
+
+			prs := resp["pull_requests"].([]any)
+			require.Len(t, prs, 1)
+			require.Equal(t, "u2", prs[0].(map[string]any)["old_reviewer_id"])
+			require.Equal(t, "u1", prs[0].(map[string]any)["replaced_by"])
+		})
+	}
+}
+
+func ptr(s string) *string { return &s }
@@ -0,0 +1,60 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domains "github.com/Deymos01/pr-review-manager/internal/domains"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OrgService is an autogenerated mock type for the OrgService type
+type OrgService struct {
+	mock.Mock
+}
+
+// Import provides a mock function with given fields: ctx, teams, dryRun
+func (_m *OrgService) Import(ctx context.Context, teams []*domains.Team, dryRun bool) (*domains.ImportReport, error) {
+	ret := _m.Called(ctx, teams, dryRun)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Import")
+	}
+
+	var r0 *domains.ImportReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*domains.Team, bool) (*domains.ImportReport, error)); ok {
+		return rf(ctx, teams, dryRun)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []*domains.Team, bool) *domains.ImportReport); ok {
+		r0 = rf(ctx, teams, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.ImportReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []*domains.Team, bool) error); ok {
+		r1 = rf(ctx, teams, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewOrgService creates a new instance of OrgService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOrgService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OrgService {
+	mock := &OrgService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
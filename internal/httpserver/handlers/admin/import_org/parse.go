@@ -0,0 +1,154 @@
+package import_org
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	formatYAML = "yaml"
+	formatCSV  = "csv"
+)
+
+type yamlChart struct {
+	Teams []struct {
+		TeamName string `yaml:"team_name"`
+		Members  []struct {
+			UserID   string `yaml:"user_id"`
+			Username string `yaml:"username"`
+			IsActive *bool  `yaml:"is_active"`
+			Role     string `yaml:"role"`
+		} `yaml:"members"`
+	} `yaml:"teams"`
+}
+
+// parseYAML reads an org chart of the form teams: [{team_name, members: [{user_id, username, is_active, role}]}].
+// Members are active unless is_active is set explicitly.
+func parseYAML(r io.Reader) ([]*domains.Team, error) {
+	var chart yamlChart
+
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&chart); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	teams := make([]*domains.Team, 0, len(chart.Teams))
+	for _, t := range chart.Teams {
+		team := &domains.Team{Name: t.TeamName}
+		for _, m := range t.Members {
+			isActive := true
+			if m.IsActive != nil {
+				isActive = *m.IsActive
+			}
+			team.Members = append(team.Members, &domains.User{
+				ID:       m.UserID,
+				Name:     m.Username,
+				TeamName: &team.Name,
+				IsActive: isActive,
+				Role:     domains.Role(m.Role),
+			})
+		}
+		teams = append(teams, team)
+	}
+
+	return teams, nil
+}
+
+// parseCSV reads one member per row with a header naming the columns
+// team_name, user_id, username and optionally is_active and role.
+func parseCSV(r io.Reader) ([]*domains.Team, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.ToLower(name))] = i
+	}
+	for _, required := range []string{"team_name", "user_id", "username"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing column %q", required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var teams []*domains.Team
+	byName := make(map[string]*domains.Team)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		isActive := true
+		if v := field(record, "is_active"); v != "" {
+			isActive, err = strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid is_active %q", line, v)
+			}
+		}
+
+		teamName := field(record, "team_name")
+		team, ok := byName[teamName]
+		if !ok {
+			team = &domains.Team{Name: teamName}
+			byName[teamName] = team
+			teams = append(teams, team)
+		}
+
+		team.Members = append(team.Members, &domains.User{
+			ID:       field(record, "user_id"),
+			Name:     field(record, "username"),
+			TeamName: &team.Name,
+			IsActive: isActive,
+			Role:     domains.Role(field(record, "role")),
+		})
+	}
+
+	return teams, nil
+}
+
+// detectFormat prefers the explicit format query parameter and falls back to Content-Type.
+func detectFormat(format, contentType string) string {
+	switch strings.ToLower(format) {
+	case formatYAML, "yml":
+		return formatYAML
+	case formatCSV:
+		return formatCSV
+	case "":
+	default:
+		return ""
+	}
+
+	contentType = strings.ToLower(contentType)
+	switch {
+	case strings.Contains(contentType, "csv"):
+		return formatCSV
+	case strings.Contains(contentType, "yaml"), strings.Contains(contentType, "yml"):
+		return formatYAML
+	}
+
+	return ""
+}
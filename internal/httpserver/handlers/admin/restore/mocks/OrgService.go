@@ -0,0 +1,47 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domains "github.com/Deymos01/pr-review-manager/internal/domains"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OrgService is an autogenerated mock type for the OrgService type
+type OrgService struct {
+	mock.Mock
+}
+
+// Restore provides a mock function with given fields: ctx, snap
+func (_m *OrgService) Restore(ctx context.Context, snap *domains.Snapshot) error {
+	ret := _m.Called(ctx, snap)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domains.Snapshot) error); ok {
+		r0 = rf(ctx, snap)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewOrgService creates a new instance of OrgService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOrgService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OrgService {
+	mock := &OrgService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
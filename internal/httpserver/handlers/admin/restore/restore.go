@@ -0,0 +1,83 @@
+package restore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/response"
+	"github.com/Deymos01/pr-review-manager/internal/lib/snapshot"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
+)
+
+const maxBodySize = 256 << 20
+
+//go:generate go run github.com/vektra/mockery/v2@v2.53.5 --name=OrgService
+type OrgService interface {
+	Restore(ctx context.Context, snap *domains.Snapshot) error
+}
+
+type Response struct {
+	Teams        int `json:"teams"`
+	Users        int `json:"users"`
+	PullRequests int `json:"pull_requests"`
+}
+
+func New(
+	log *slog.Logger,
+	service OrgService,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.admin.restore.New"
+		log = log.With(slog.String("op", op))
+
+		snap, err := snapshot.Decode(http.MaxBytesReader(w, r.Body, maxBodySize))
+		if err != nil {
+			log.Warn("invalid snapshot document", slog.Any("error", err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).
+				Encode(response.NewErrorResponse(handlers.InvalidRequest, "invalid snapshot: "+err.Error()))
+			return
+		}
+
+		if err := service.Restore(r.Context(), snap); err != nil {
+			switch {
+			case errors.Is(err, usecase.ErrInvalidSnapshot):
+				log.Warn("snapshot validation failed", slog.Any("error", err))
+
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.InvalidRequest, err.Error()))
+			case errors.Is(err, usecase.ErrStorageNotEmpty):
+				log.Warn("storage is not empty")
+
+				w.WriteHeader(http.StatusConflict)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.StorageNotEmpty, "restore requires an empty database"))
+			default:
+				log.Error("failed to restore snapshot", slog.Any("error", err))
+
+				w.WriteHeader(http.StatusInternalServerError)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.InternalError, "internal server error"))
+			}
+			return
+		}
+
+		resp := Response{
+			Teams:        len(snap.Teams),
+			Users:        len(snap.Users),
+			PullRequests: len(snap.PullRequests),
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.Error("failed to encode response", slog.Any("error", err))
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package restore_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/admin/restore"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/admin/restore/mocks"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+const document = `{
+	"version": 1,
+	"created_at": "2025-10-24T12:00:00Z",
+	"statuses": ["OPEN", "MERGED"],
+	"teams": ["backend"],
+	"users": [
+		{"user_id": "u1", "username": "Alice", "team_name": "backend", "is_active": true, "role": "lead"},
+		{"user_id": "u2", "username": "Bob", "team_name": "backend", "is_active": true, "role": "member"}
+	],
+	"pull_requests": [
+		{
+			"pull_request_id": "pr1",
+			"pull_request_name": "Add search",
+			"author_id": "u1",
+			"status": "OPEN",
+			"need_more_reviewers": false,
+			"created_at": "2025-10-24T12:00:00Z",
+			"merged_at": null,
+			"reviewers": [{"user_id": "u2", "assigned_at": "2025-10-24T12:00:00Z"}]
+		}
+	]
+}`
+
+func TestRestoreHandler(t *testing.T) {
+	type testCase struct {
+		name           string
+		body           string
+		callService    bool
+		mockError      error
+		expectedStatus int
+		expectedErr    string
+	}
+
+	cases := []testCase{
+		{
+			name:           "Success",
+			body:           document,
+			callService:    true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Unsupported version",
+			body:           `{"version": 99}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "invalid snapshot: unsupported snapshot version 99, expected 1 to 2",
+		},
+		{
+			name:           "Invalid JSON",
+			body:           `{"version":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid references",
+			body:           document,
+			callService:    true,
+			mockError:      fmt.Errorf("%w: user %q: unknown team %q", usecase.ErrInvalidSnapshot, "u1", "x"),
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    `invalid snapshot: user "u1": unknown team "x"`,
+		},
+		{
+			name:           "Storage not empty",
+			body:           document,
+			callService:    true,
+			mockError:      usecase.ErrStorageNotEmpty,
+			expectedStatus: http.StatusConflict,
+			expectedErr:    "restore requires an empty database",
+		},
+		{
+			name:           "Unknown error",
+			body:           document,
+			callService:    true,
+			mockError:      errors.New("unexpected"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedErr:    "internal server error",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := mocks.NewOrgService(t)
+
+			if tc.callService {
+				svc.On("Restore", mock.Anything, mock.AnythingOfType("*domains.Snapshot")).
+					Return(tc.mockError).
+					Once()
+			}
+
+			handler := restore.New(discardLogger(), svc)
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/restore", strings.NewReader(tc.body))
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			if tc.expectedStatus != http.StatusOK {
+				errResp := resp["error"].(map[string]any)
+				if tc.expectedErr != "" {
+					require.Equal(t, tc.expectedErr, errResp["message"])
+				}
+				return
+			}
+
+			require.EqualValues(t, 1, resp["teams"])
+			require.EqualValues(t, 2, resp["users"])
+			require.EqualValues(t, 1, resp["pull_requests"])
+		})
+	}
+}
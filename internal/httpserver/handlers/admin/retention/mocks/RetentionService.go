@@ -0,0 +1,59 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domains "github.com/Deymos01/pr-review-manager/internal/domains"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RetentionService is an autogenerated mock type for the RetentionService type
+type RetentionService struct {
+	mock.Mock
+}
+
+// Run provides a mock function with given fields: ctx, override
+func (_m *RetentionService) Run(ctx context.Context, override domains.RetentionPolicy) (*domains.RetentionReport, error) {
+	ret := _m.Called(ctx, override)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Run")
+	}
+
+	var r0 *domains.RetentionReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domains.RetentionPolicy) (*domains.RetentionReport, error)); ok {
+		return rf(ctx, override)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domains.RetentionPolicy) *domains.RetentionReport); ok {
+		r0 = rf(ctx, override)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.RetentionReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domains.RetentionPolicy) error); ok {
+		r1 = rf(ctx, override)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRetentionService creates a new instance of RetentionService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRetentionService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RetentionService {
+	mock := &RetentionService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,91 @@
+package retention
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/response"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.53.5 --name=RetentionService
+type RetentionService interface {
+	Run(ctx context.Context, override domains.RetentionPolicy) (*domains.RetentionReport, error)
+}
+
+type Response struct {
+	Mode         string    `json:"mode"`
+	MergedBefore time.Time `json:"merged_before"`
+	PullRequests int       `json:"pull_requests"`
+	Reviews      int       `json:"reviews"`
+	Batches      int       `json:"batches"`
+	StartedAt    time.Time `json:"started_at"`
+	FinishedAt   time.Time `json:"finished_at"`
+}
+
+// New runs retention immediately. The days and mode query parameters override the
+// configured policy for this run only.
+func New(
+	log *slog.Logger,
+	service RetentionService,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.admin.retention.New"
+		log = log.With(slog.String("op", op))
+
+		var override domains.RetentionPolicy
+		if raw := r.URL.Query().Get("days"); raw != "" {
+			days, err := strconv.Atoi(raw)
+			if err != nil || days <= 0 {
+				log.Warn("invalid days", slog.String("days", raw))
+
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.InvalidRequest, "days must be a positive integer"))
+				return
+			}
+			override.MaxAge = time.Duration(days) * 24 * time.Hour
+		}
+		override.Mode = domains.RetentionMode(r.URL.Query().Get("mode"))
+
+		report, err := service.Run(r.Context(), override)
+		if err != nil {
+			if errors.Is(err, usecase.ErrInvalidRetention) {
+				log.Warn("invalid retention policy", slog.Any("error", err))
+
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.InvalidRequest, err.Error()))
+				return
+			}
+			log.Error("failed to run retention", slog.Any("error", err))
+
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).
+				Encode(response.NewErrorResponse(handlers.InternalError, "internal server error"))
+			return
+		}
+
+		resp := Response{
+			Mode:         string(report.Mode),
+			MergedBefore: report.MergedBefore,
+			PullRequests: report.PullRequests,
+			Reviews:      report.Reviews,
+			Batches:      report.Batches,
+			StartedAt:    report.StartedAt,
+			FinishedAt:   report.FinishedAt,
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.Error("failed to encode response", slog.Any("error", err))
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package retention_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/admin/retention"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/admin/retention/mocks"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestRetentionHandler(t *testing.T) {
+	startedAt := time.Date(2025, 11, 30, 12, 0, 0, 0, time.UTC)
+	report := &domains.RetentionReport{
+		Mode:         domains.RetentionArchive,
+		MergedBefore: startedAt.Add(-30 * 24 * time.Hour),
+		PullRequests: 3,
+		Reviews:      5,
+		Batches:      1,
+		StartedAt:    startedAt,
+		FinishedAt:   startedAt.Add(time.Second),
+	}
+
+	type testCase struct {
+		name  string
+		query string
+
+		callService bool
+		override    domains.RetentionPolicy
+		mockReport  *domains.RetentionReport
+		mockError   error
+
+		expectedStatus int
+		expectedErr    string
+	}
+
+	cases := []testCase{
+		{
+			name:           "Configured policy",
+			callService:    true,
+			mockReport:     report,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Overridden policy",
+			query:          "?days=30&mode=delete",
+			callService:    true,
+			override:       domains.RetentionPolicy{MaxAge: 30 * 24 * time.Hour, Mode: domains.RetentionDelete},
+			mockReport:     report,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid days",
+			query:          "?days=-1",
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "days must be a positive integer",
+		},
+		{
+			name:           "Invalid policy",
+			query:          "?mode=compress",
+			callService:    true,
+			override:       domains.RetentionPolicy{Mode: "compress"},
+			mockError:      fmt.Errorf("%w: mode must be archive or delete", usecase.ErrInvalidRetention),
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "invalid retention policy: mode must be archive or delete",
+		},
+		{
+			name:           "Unknown error",
+			callService:    true,
+			mockError:      errors.New("unexpected"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedErr:    "internal server error",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := mocks.NewRetentionService(t)
+			if tc.callService {
+				svc.On("Run", mock.Anything, tc.override).Return(tc.mockReport, tc.mockError).Once()
+			}
+
+			handler := retention.New(discardLogger(), svc)
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/retention"+tc.query, nil)
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+
+			if tc.expectedErr != "" {
+				var resp map[string]any
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				errResp := resp["error"].(map[string]any)
+				require.Equal(t, tc.expectedErr, errResp["message"])
+				return
+			}
+
+			var resp retention.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, string(report.Mode), resp.Mode)
+			require.True(t, report.MergedBefore.Equal(resp.MergedBefore))
+			require.Equal(t, report.PullRequests, resp.PullRequests)
+			require.Equal(t, report.Reviews, resp.Reviews)
+			require.Equal(t, report.Batches, resp.Batches)
+		})
+	}
+}
@@ -1,13 +1,20 @@
 package handlers
 
 const (
-	NotFound               = "NOT_FOUND"
-	InternalError          = "INTERNAL_ERROR"
-	InvalidRequest         = "INVALID_REQUEST"
-	TeamExists             = "TEAM_EXISTS"
-	PrExists               = "PR_EXISTS"
-	PrMerged               = "PR_MERGED"
-	NotAssigned            = "NOT_ASSIGNED"
-	NoCandidate            = "NO_CANDIDATE"
-	TeamCompatibilityError = "TEAM_COMPATIBILITY_ERROR"
+	NotFound                 = "NOT_FOUND"
+	InternalError            = "INTERNAL_ERROR"
+	InvalidRequest           = "INVALID_REQUEST"
+	TeamExists               = "TEAM_EXISTS"
+	PrExists                 = "PR_EXISTS"
+	PrMerged                 = "PR_MERGED"
+	NotAssigned              = "NOT_ASSIGNED"
+	NoCandidate              = "NO_CANDIDATE"
+	TeamCompatibilityError   = "TEAM_COMPATIBILITY_ERROR"
+	Forbidden                = "FORBIDDEN"
+	StorageNotEmpty          = "STORAGE_NOT_EMPTY"
+	VersionConflict          = "VERSION_CONFLICT"
+	PreconditionFailed       = "PRECONDITION_FAILED"
+	IdempotencyKeyMismatch   = "IDEMPOTENCY_KEY_MISMATCH"
+	IdempotencyKeyInProgress = "IDEMPOTENCY_KEY_IN_PROGRESS"
+	UserDeleted              = "USER_DELETED"
 )
@@ -7,30 +7,34 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/Deymos01/pr-review-manager/internal/domains"
 	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/pull_requests"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/etag"
 	"github.com/Deymos01/pr-review-manager/internal/lib/api/response"
 	"github.com/Deymos01/pr-review-manager/internal/usecase"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@v2.53.5 --name=PRService
 type PRService interface {
-	CreatePullRequest(ctx context.Context, prID, prName, authorID string) (assignedReviewers []string, err error)
+	CreatePullRequest(
+		ctx context.Context,
+		prID, prName, authorID string,
+		requireLead bool,
+	) (*domains.PullRequest, error)
 }
 
 type Request struct {
 	PrID     string `json:"pull_request_id"`
 	PrName   string `json:"pull_request_name"`
 	AuthorID string `json:"author_id"`
+	// RequireLead makes one of the assigned reviewers a team lead. It only affects the
+	// assignment: merging does not check for the lead's approval
+	RequireLead bool `json:"require_lead"`
 }
 
 type Response struct {
-	PR struct {
-		PrID              string   `json:"pull_request_id"`
-		PrName            string   `json:"pull_request_name"`
-		AuthorID          string   `json:"author_id"`
-		Status            string   `json:"status"`
-		AssignedReviewers []string `json:"assigned_reviewers"`
-	} `json:"pr"`
+	PR pull_requests.PullRequest `json:"pr"`
 }
 
 func New(
@@ -51,7 +55,7 @@ func New(
 			return
 		}
 
-		assignedReviewers, err := prService.CreatePullRequest(r.Context(), req.PrID, req.PrName, req.AuthorID)
+		pr, err := prService.CreatePullRequest(r.Context(), req.PrID, req.PrName, req.AuthorID, req.RequireLead)
 		if err != nil {
 			log.Warn("failed to create pull request", slog.Any("error", err))
 
@@ -64,6 +68,10 @@ func New(
 				w.WriteHeader(http.StatusConflict)
 				_ = json.NewEncoder(w).
 					Encode(response.NewErrorResponse(handlers.PrExists, "pull request already exists"))
+			case errors.Is(err, usecase.ErrNoAvailableReviewer):
+				w.WriteHeader(http.StatusConflict)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.NoCandidate, "no active team lead available to review"))
 			default:
 				w.WriteHeader(http.StatusInternalServerError)
 				_ = json.NewEncoder(w).
@@ -73,13 +81,9 @@ func New(
 			return
 		}
 
-		var resp Response
-		resp.PR.PrID = req.PrID
-		resp.PR.PrName = req.PrName
-		resp.PR.AuthorID = req.AuthorID
-		resp.PR.Status = "OPEN"
-		resp.PR.AssignedReviewers = assignedReviewers
+		resp := Response{PR: pull_requests.NewPullRequest(pr)}
 
+		w.Header().Set("ETag", etag.Format(pr.Version))
 		w.WriteHeader(http.StatusCreated)
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
 			log.Error("failed to encode response", slog.Any("error", err))
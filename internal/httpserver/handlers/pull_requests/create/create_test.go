@@ -10,6 +10,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/Deymos01/pr-review-manager/internal/domains"
 	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/pull_requests/create"
 	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/pull_requests/create/mocks"
 	"github.com/Deymos01/pr-review-manager/internal/usecase"
@@ -25,7 +26,7 @@ func TestCreateHandler(t *testing.T) {
 	type testCase struct {
 		name           string
 		body           string
-		mockReturnIDs  []string
+		mockReturnPR   *domains.PullRequest
 		mockError      error
 		expectedStatus int
 		expectedErr    string
@@ -33,9 +34,18 @@ func TestCreateHandler(t *testing.T) {
 
 	cases := []testCase{
 		{
-			name:           "Success",
-			body:           `{"pull_request_id":"1","pull_request_name":"test","author_id":"1"}`,
-			mockReturnIDs:  []string{"u1", "u2"},
+			name: "Success",
+			body: `{"pull_request_id":"1","pull_request_name":"test","author_id":"1"}`,
+			mockReturnPR: &domains.PullRequest{
+				ID:     "1",
+				Name:   "test",
+				Author: &domains.User{ID: "1", Name: "Alice"},
+				Status: "OPEN",
+				Reviewers: []*domains.Reviewer{
+					{User: &domains.User{ID: "u1", Name: "Bob", IsActive: true}},
+					{User: &domains.User{ID: "u2", Name: "Carol", IsActive: true}},
+				},
+			},
 			expectedStatus: http.StatusCreated,
 		},
 		{
@@ -65,6 +75,13 @@ func TestCreateHandler(t *testing.T) {
 			expectedStatus: http.StatusConflict,
 			expectedErr:    "pull request already exists",
 		},
+		{
+			name:           "No lead available",
+			body:           `{"pull_request_id":"1","pull_request_name":"test","author_id":"1"}`,
+			mockError:      usecase.ErrNoAvailableReviewer,
+			expectedStatus: http.StatusConflict,
+			expectedErr:    "no active team lead available to review",
+		},
 		{
 			name:           "Unknown error",
 			body:           `{"pull_request_id":"1","pull_request_name":"test","author_id":"1"}`,
@@ -85,8 +102,8 @@ func TestCreateHandler(t *testing.T) {
 			if tc.expectedStatus != http.StatusBadRequest {
 				svc.On(
 					"CreatePullRequest",
-					mock.Anything, "1", "test", "1").
-					Return(tc.mockReturnIDs, tc.mockError).
+					mock.Anything, "1", "test", "1", false).
+					Return(tc.mockReturnPR, tc.mockError).
 					Once()
 			}
 
@@ -114,6 +131,13 @@ func TestCreateHandler(t *testing.T) {
 				require.Equal(t, "test", pr["pull_request_name"])
 				require.Equal(t, "1", pr["author_id"])
 				require.Equal(t, "OPEN", pr["status"])
+				require.Equal(t, "Alice", pr["author_name"])
+				require.Equal(t, []any{"u1", "u2"}, pr["assigned_reviewers"])
+
+				reviewers := pr["reviewers"].([]any)
+				require.Len(t, reviewers, 2)
+				require.Equal(t, "Bob", reviewers[0].(map[string]any)["username"])
+				require.Equal(t, "Carol", reviewers[1].(map[string]any)["username"])
 			}
 		})
 	}
@@ -5,6 +5,8 @@ package mocks
 import (
 	context "context"
 
+	domains "github.com/Deymos01/pr-review-manager/internal/domains"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -13,29 +15,29 @@ type PRService struct {
 	mock.Mock
 }
 
-// CreatePullRequest provides a mock function with given fields: ctx, prID, prName, authorID
-func (_m *PRService) CreatePullRequest(ctx context.Context, prID string, prName string, authorID string) ([]string, error) {
-	ret := _m.Called(ctx, prID, prName, authorID)
+// CreatePullRequest provides a mock function with given fields: ctx, prID, prName, authorID, requireLead
+func (_m *PRService) CreatePullRequest(ctx context.Context, prID string, prName string, authorID string, requireLead bool) (*domains.PullRequest, error) {
+	ret := _m.Called(ctx, prID, prName, authorID, requireLead)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreatePullRequest")
 	}
 
-	var r0 []string
+	var r0 *domains.PullRequest
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) ([]string, error)); ok {
-		return rf(ctx, prID, prName, authorID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, bool) (*domains.PullRequest, error)); ok {
+		return rf(ctx, prID, prName, authorID, requireLead)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) []string); ok {
-		r0 = rf(ctx, prID, prName, authorID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, bool) *domains.PullRequest); ok {
+		r0 = rf(ctx, prID, prName, authorID, requireLead)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]string)
+			r0 = ret.Get(0).(*domains.PullRequest)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
-		r1 = rf(ctx, prID, prName, authorID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, bool) error); ok {
+		r1 = rf(ctx, prID, prName, authorID, requireLead)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,62 @@
+package get
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/pull_requests"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/etag"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/response"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.53.5 --name=PRService
+type PRService interface {
+	GetPullRequest(ctx context.Context, prID string) (*domains.PullRequest, error)
+}
+
+type Response struct {
+	Pr pull_requests.PullRequestDetails `json:"pr"`
+}
+
+func New(
+	log *slog.Logger,
+	prService PRService,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.pull_requests.get.New"
+		log = log.With(slog.String("op", op))
+
+		prID := r.URL.Query().Get("pull_request_id")
+
+		pr, err := prService.GetPullRequest(r.Context(), prID)
+		if err != nil {
+			log.Warn("failed to get pull request", slog.String("pr_id", prID), slog.Any("error", err))
+
+			switch {
+			case errors.Is(err, usecase.ErrPullRequestNotFound):
+				w.WriteHeader(http.StatusNotFound)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.NotFound, "resource not found"))
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.InternalError, "internal server error"))
+			}
+			return
+		}
+
+		resp := Response{Pr: pull_requests.NewPullRequestDetails(pr)}
+
+		w.Header().Set("ETag", etag.Format(pr.Version))
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.Error("failed to encode response", slog.Any("error", err))
+		}
+	}
+}
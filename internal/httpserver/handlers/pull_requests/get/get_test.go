@@ -0,0 +1,110 @@
+package get_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/pull_requests"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/pull_requests/get"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/pull_requests/get/mocks"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestGetPullRequestHandler(t *testing.T) {
+	team := "backend"
+	createdAt := time.Date(2025, 10, 20, 9, 0, 0, 0, time.UTC)
+
+	type testCase struct {
+		name           string
+		mockPR         *domains.PullRequest
+		mockError      error
+		expectedStatus int
+		expectedErr    string
+	}
+
+	cases := []testCase{
+		{
+			name: "Success",
+			mockPR: &domains.PullRequest{
+				ID:     "pr1",
+				Name:   "Add search",
+				Author: &domains.User{ID: "u1", Name: "Alice", TeamName: &team},
+				Status: "OPEN",
+				Reviewers: []*domains.Reviewer{
+					{User: &domains.User{ID: "u2", Name: "Bob", IsActive: true}, AssignedAt: createdAt},
+				},
+				NeedMoreReviewers: true,
+				CreatedAt:         createdAt,
+				Version:           2,
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Not found",
+			mockError:      usecase.ErrPullRequestNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedErr:    "resource not found",
+		},
+		{
+			name:           "Unknown error",
+			mockError:      errors.New("db down"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedErr:    "internal server error",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := mocks.NewPRService(t)
+			svc.On("GetPullRequest", mock.Anything, "pr1").
+				Return(tc.mockPR, tc.mockError).
+				Once()
+
+			handler := get.New(discardLogger(), svc)
+
+			req := httptest.NewRequest(http.MethodGet, "/pullRequest/get?pull_request_id=pr1", nil)
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+
+			if tc.expectedErr != "" {
+				var resp map[string]any
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Equal(t, tc.expectedErr, resp["error"].(map[string]any)["message"])
+				return
+			}
+
+			require.Equal(t, `"2"`, rr.Header().Get("ETag"))
+
+			var resp get.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, "pr1", resp.Pr.PrID)
+			require.Equal(t, "Alice", resp.Pr.AuthorName)
+			require.Equal(t, &team, resp.Pr.TeamName)
+			require.True(t, resp.Pr.NeedMoreReviewers)
+			require.Nil(t, resp.Pr.MergedAt)
+			require.Equal(t, []pull_requests.Reviewer{
+				{UserID: "u2", Username: "Bob", IsActive: true, AssignedAt: createdAt},
+			}, resp.Pr.Reviewers)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package list
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/pull_requests"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/pagination"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/response"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.53.5 --name=PRService
+type PRService interface {
+	ListPullRequests(ctx context.Context, filter domains.PullRequestFilter) (*domains.PullRequestPage, error)
+}
+
+type Response struct {
+	PRs        []pull_requests.PullRequestDetails `json:"pull_requests"`
+	NextCursor string                             `json:"next_cursor,omitempty"`
+}
+
+func New(
+	log *slog.Logger,
+	prService PRService,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.pull_requests.list.New"
+		log = log.With(slog.String("op", op))
+
+		filter, err := parseFilter(r.URL.Query())
+		if err != nil {
+			log.Warn("invalid pull request filter", slog.Any("error", err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).
+				Encode(response.NewErrorResponse(handlers.InvalidRequest, err.Error()))
+			return
+		}
+
+		page, err := prService.ListPullRequests(r.Context(), filter)
+		if err != nil {
+			switch {
+			case errors.Is(err, usecase.ErrInvalidCursor):
+				log.Warn("invalid pull request cursor", slog.Any("error", err))
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.InvalidRequest, "invalid cursor"))
+			default:
+				log.Error("failed to list pull requests", slog.Any("error", err))
+				w.WriteHeader(http.StatusInternalServerError)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.InternalError, "internal server error"))
+			}
+			return
+		}
+
+		resp := Response{
+			PRs:        make([]pull_requests.PullRequestDetails, 0, len(page.PullRequests)),
+			NextCursor: pagination.EncodeCursor(page.Next),
+		}
+		for _, pr := range page.PullRequests {
+			resp.PRs = append(resp.PRs, pull_requests.NewPullRequestDetails(pr))
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.Error("failed to encode response", slog.Any("error", err))
+		}
+	}
+}
+
+func parseFilter(q url.Values) (domains.PullRequestFilter, error) {
+	var (
+		filter domains.PullRequestFilter
+		err    error
+	)
+
+	if filter.Limit, err = pagination.Limit(q.Get("limit")); err != nil {
+		return filter, err
+	}
+	if filter.Desc, err = pagination.Desc(q.Get("order")); err != nil {
+		return filter, err
+	}
+	if filter.After, err = pagination.DecodeCursor(q.Get("cursor")); err != nil {
+		return filter, err
+	}
+	if filter.NeedMoreReviewers, err = pagination.Bool("need_more_reviewers", q.Get("need_more_reviewers")); err != nil {
+		return filter, err
+	}
+
+	if status := domains.PRStatus(q.Get("status")); status != "" {
+		if !status.Valid() {
+			return filter, errors.New("status must be one of: OPEN, MERGED")
+		}
+		filter.Status = status
+	}
+
+	ranges := []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"created_from", &filter.CreatedFrom},
+		{"created_to", &filter.CreatedTo},
+		{"merged_from", &filter.MergedFrom},
+		{"merged_to", &filter.MergedTo},
+	}
+	for _, rng := range ranges {
+		raw := q.Get(rng.name)
+		if raw == "" {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return filter, fmt.Errorf("%s must be an RFC3339 timestamp", rng.name)
+		}
+		ts = ts.UTC()
+		*rng.dst = &ts
+	}
+
+	filter.TeamName = q.Get("team_name")
+	filter.AuthorID = q.Get("author_id")
+	filter.ReviewerID = q.Get("reviewer_id")
+
+	return filter, nil
+}
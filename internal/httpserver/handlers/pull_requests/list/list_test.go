@@ -0,0 +1,164 @@
+package list_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/pull_requests/list"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/pull_requests/list/mocks"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/pagination"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestListPullRequestsHandler(t *testing.T) {
+	needMore := true
+	createdFrom := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	mergedTo := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2025, 10, 20, 9, 0, 0, 0, time.UTC)
+	next := &domains.Cursor{Key: createdAt.Format(time.RFC3339Nano), ID: "pr1"}
+
+	defaultFilter := domains.PullRequestFilter{Limit: pagination.DefaultLimit}
+
+	type testCase struct {
+		name           string
+		query          string
+		expectedFilter *domains.PullRequestFilter
+		mockPage       *domains.PullRequestPage
+		mockError      error
+		expectedStatus int
+		expectedErr    string
+	}
+
+	cases := []testCase{
+		{
+			name:           "Defaults",
+			expectedFilter: &defaultFilter,
+			mockPage: &domains.PullRequestPage{
+				PullRequests: []*domains.PullRequest{
+					{
+						ID:     "pr1",
+						Name:   "Add search",
+						Author: &domains.User{ID: "u1", Name: "Alice"},
+						Status: "OPEN",
+						Reviewers: []*domains.Reviewer{
+							{User: &domains.User{ID: "u2", Name: "Bob", IsActive: true}, AssignedAt: createdAt},
+						},
+						CreatedAt: createdAt,
+					},
+				},
+				Next: next,
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "All filters",
+			query: "?status=MERGED&team_name=backend&author_id=u1&reviewer_id=u2" +
+				"&created_from=2025-10-01T00:00:00Z&merged_to=2025-11-01T03:00:00%2B03:00" +
+				"&need_more_reviewers=true&order=desc&limit=10&cursor=" + pagination.EncodeCursor(next),
+			expectedFilter: &domains.PullRequestFilter{
+				Status:            "MERGED",
+				TeamName:          "backend",
+				AuthorID:          "u1",
+				ReviewerID:        "u2",
+				CreatedFrom:       &createdFrom,
+				MergedTo:          &mergedTo,
+				NeedMoreReviewers: &needMore,
+				Desc:              true,
+				After:             next,
+				Limit:             10,
+			},
+			mockPage:       &domains.PullRequestPage{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid status",
+			query:          "?status=DRAFT",
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "status must be one of: OPEN, MERGED",
+		},
+		{
+			name:           "Invalid range",
+			query:          "?merged_from=last-week",
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "merged_from must be an RFC3339 timestamp",
+		},
+		{
+			name:           "Invalid need_more_reviewers",
+			query:          "?need_more_reviewers=sometimes",
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "need_more_reviewers must be a boolean",
+		},
+		{
+			name:           "Stale cursor",
+			expectedFilter: &defaultFilter,
+			mockError:      usecase.ErrInvalidCursor,
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "invalid cursor",
+		},
+		{
+			name:           "Unknown error",
+			expectedFilter: &defaultFilter,
+			mockError:      errors.New("db down"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedErr:    "internal server error",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := mocks.NewPRService(t)
+
+			if tc.expectedFilter != nil {
+				svc.On("ListPullRequests", mock.Anything, *tc.expectedFilter).
+					Return(tc.mockPage, tc.mockError).
+					Once()
+			}
+
+			handler := list.New(discardLogger(), svc)
+
+			req := httptest.NewRequest(http.MethodGet, "/pullRequest/list"+tc.query, nil)
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+
+			if tc.expectedErr != "" {
+				var resp map[string]any
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Equal(t, tc.expectedErr, resp["error"].(map[string]any)["message"])
+				return
+			}
+
+			var resp list.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Len(t, resp.PRs, len(tc.mockPage.PullRequests))
+			require.Equal(t, pagination.EncodeCursor(tc.mockPage.Next), resp.NextCursor)
+
+			for i, pr := range tc.mockPage.PullRequests {
+				require.Equal(t, pr.ID, resp.PRs[i].PrID)
+				require.Equal(t, pr.Author.Name, resp.PRs[i].AuthorName)
+				require.Len(t, resp.PRs[i].Reviewers, len(pr.Reviewers))
+				require.Equal(t, pr.Reviewers[0].User.Name, resp.PRs[i].Reviewers[0].Username)
+				require.Equal(t, pr.Reviewers[0].AssignedAt, resp.PRs[i].Reviewers[0].AssignedAt)
+			}
+		})
+	}
+}
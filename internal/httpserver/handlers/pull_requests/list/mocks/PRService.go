@@ -0,0 +1,60 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domains "github.com/Deymos01/pr-review-manager/internal/domains"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PRService is an autogenerated mock type for the PRService type
+type PRService struct {
+	mock.Mock
+}
+
+// ListPullRequests provides a mock function with given fields: ctx, filter
+func (_m *PRService) ListPullRequests(ctx context.Context, filter domains.PullRequestFilter) (*domains.PullRequestPage, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPullRequests")
+	}
+
+	var r0 *domains.PullRequestPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domains.PullRequestFilter) (*domains.PullRequestPage, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domains.PullRequestFilter) *domains.PullRequestPage); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.PullRequestPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domains.PullRequestFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPRService creates a new instance of PRService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPRService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PRService {
+	mock := &PRService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
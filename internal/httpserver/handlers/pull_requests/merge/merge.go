@@ -6,17 +6,18 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
-	"time"
 
 	"github.com/Deymos01/pr-review-manager/internal/domains"
 	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/pull_requests"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/etag"
 	"github.com/Deymos01/pr-review-manager/internal/lib/api/response"
 	"github.com/Deymos01/pr-review-manager/internal/usecase"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@v2.53.5 --name=PRService
 type PRService interface {
-	MergePullRequest(ctx context.Context, prID string) (*domains.PullRequest, error)
+	MergePullRequest(ctx context.Context, prID string, ifVersion int64) (*domains.PullRequest, error)
 }
 
 type Request struct {
@@ -24,14 +25,7 @@ type Request struct {
 }
 
 type Response struct {
-	Pr struct {
-		PrID              string    `json:"pull_request_id"`
-		PrName            string    `json:"pull_request_name"`
-		AuthorID          string    `json:"author_id"`
-		Status            string    `json:"status"`
-		AssignedReviewers []string  `json:"assigned_reviewers"`
-		MergedAt          time.Time `json:"mergedAt"`
-	} `json:"pr"`
+	Pr pull_requests.PullRequest `json:"pr"`
 }
 
 func New(
@@ -52,7 +46,17 @@ func New(
 			return
 		}
 
-		pr, err := prService.MergePullRequest(r.Context(), req.PrID)
+		ifVersion, err := etag.IfMatch(r)
+		if err != nil {
+			log.Warn("invalid If-Match header", slog.String("if_match", r.Header.Get("If-Match")))
+
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).
+				Encode(response.NewErrorResponse(handlers.InvalidRequest, "invalid If-Match header"))
+			return
+		}
+
+		pr, err := prService.MergePullRequest(r.Context(), req.PrID, ifVersion)
 		if err != nil {
 			log.Warn("failed to merge pull request", slog.Any("error", err))
 
@@ -61,6 +65,10 @@ func New(
 				w.WriteHeader(http.StatusNotFound)
 				_ = json.NewEncoder(w).
 					Encode(response.NewErrorResponse(handlers.NotFound, "resource not found"))
+			case errors.Is(err, usecase.ErrVersionConflict):
+				w.WriteHeader(http.StatusPreconditionFailed)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.PreconditionFailed, "pull request was modified, reload and retry"))
 			default:
 				w.WriteHeader(http.StatusInternalServerError)
 				_ = json.NewEncoder(w).
@@ -69,16 +77,9 @@ func New(
 			return
 		}
 
-		var resp Response
-		resp.Pr.PrID = pr.ID
-		resp.Pr.PrName = pr.Name
-		resp.Pr.AuthorID = pr.Author.ID
-		resp.Pr.Status = pr.Status
-		for _, reviewer := range pr.Reviewers {
-			resp.Pr.AssignedReviewers = append(resp.Pr.AssignedReviewers, reviewer.User.ID)
-		}
-		resp.Pr.MergedAt = *pr.MergedAt
+		resp := Response{Pr: pull_requests.NewPullRequest(pr)}
 
+		w.Header().Set("ETag", etag.Format(pr.Version))
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
 			log.Error("failed to encode response", slog.Any("error", err))
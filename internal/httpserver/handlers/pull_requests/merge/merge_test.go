@@ -27,6 +27,8 @@ func TestMergeHandler(t *testing.T) {
 	type testCase struct {
 		name           string
 		body           string
+		ifMatch        string
+		ifVersion      int64
 		mockReturnPR   *domains.PullRequest
 		mockError      error
 		expectedStatus int
@@ -45,15 +47,32 @@ func TestMergeHandler(t *testing.T) {
 				Author: &domains.User{
 					ID: "1",
 				},
-				Status: "MERGED",
+				Status:  "MERGED",
+				Version: 4,
 				Reviewers: []*domains.Reviewer{
-					{User: &domains.User{ID: "u1"}},
-					{User: &domains.User{ID: "u2"}},
+					{User: &domains.User{ID: "u1", Name: "Bob"}, AssignedAt: now},
+					{User: &domains.User{ID: "u2", Name: "Carol"}, AssignedAt: now},
 				},
 				MergedAt: &now,
 			},
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name:           "Invalid If-Match",
+			body:           `{"pull_request_id":"1"}`,
+			ifMatch:        `W/"3"`,
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "invalid If-Match header",
+		},
+		{
+			name:           "If-Match mismatch",
+			body:           `{"pull_request_id":"1"}`,
+			ifMatch:        `"3"`,
+			ifVersion:      3,
+			mockError:      usecase.ErrVersionConflict,
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedErr:    "pull request was modified, reload and retry",
+		},
 		{
 			name:           "Invalid JSON",
 			body:           `{"pull_request_id":`,
@@ -89,12 +108,16 @@ func TestMergeHandler(t *testing.T) {
 					"MergePullRequest",
 					mock.Anything,
 					"1",
+					tc.ifVersion,
 				).Return(tc.mockReturnPR, tc.mockError).Once()
 			}
 
 			handler := merge.New(discardLogger(), svc)
 
 			req := httptest.NewRequest(http.MethodPost, "/pullRequest/merge", bytes.NewBufferString(tc.body))
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
 			rr := httptest.NewRecorder()
 
 			handler.ServeHTTP(rr, req)
@@ -111,12 +134,14 @@ func TestMergeHandler(t *testing.T) {
 			}
 
 			if rr.Code == http.StatusOK {
+				require.Equal(t, `"4"`, rr.Header().Get("ETag"))
+
 				pr := resp["pr"].(map[string]any)
 
 				require.Equal(t, tc.mockReturnPR.ID, pr["pull_request_id"])
 				require.Equal(t, tc.mockReturnPR.Name, pr["pull_request_name"])
 				require.Equal(t, tc.mockReturnPR.Author.ID, pr["author_id"])
-				require.Equal(t, tc.mockReturnPR.Status, pr["status"])
+				require.Equal(t, string(tc.mockReturnPR.Status), pr["status"])
 
 				arr := pr["assigned_reviewers"].([]any)
 				require.Len(t, arr, len(tc.mockReturnPR.Reviewers))
@@ -124,6 +149,11 @@ func TestMergeHandler(t *testing.T) {
 				require.Equal(t, "u2", arr[1])
 
 				require.NotEmpty(t, pr["mergedAt"])
+
+				reviewers := pr["reviewers"].([]any)
+				require.Len(t, reviewers, 2)
+				require.Equal(t, "Bob", reviewers[0].(map[string]any)["username"])
+				require.NotEmpty(t, reviewers[0].(map[string]any)["assigned_at"])
 			}
 		})
 	}
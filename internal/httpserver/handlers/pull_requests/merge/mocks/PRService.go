@@ -15,9 +15,9 @@ type PRService struct {
 	mock.Mock
 }
 
-// MergePullRequest provides a mock function with given fields: ctx, prID
-func (_m *PRService) MergePullRequest(ctx context.Context, prID string) (*domains.PullRequest, error) {
-	ret := _m.Called(ctx, prID)
+// MergePullRequest provides a mock function with given fields: ctx, prID, ifVersion
+func (_m *PRService) MergePullRequest(ctx context.Context, prID string, ifVersion int64) (*domains.PullRequest, error) {
+	ret := _m.Called(ctx, prID, ifVersion)
 
 	if len(ret) == 0 {
 		panic("no return value specified for MergePullRequest")
@@ -25,19 +25,19 @@ func (_m *PRService) MergePullRequest(ctx context.Context, prID string) (*domain
 
 	var r0 *domains.PullRequest
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*domains.PullRequest, error)); ok {
-		return rf(ctx, prID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) (*domains.PullRequest, error)); ok {
+		return rf(ctx, prID, ifVersion)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *domains.PullRequest); ok {
-		r0 = rf(ctx, prID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) *domains.PullRequest); ok {
+		r0 = rf(ctx, prID, ifVersion)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domains.PullRequest)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, prID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, prID, ifVersion)
 	} else {
 		r1 = ret.Error(1)
 	}
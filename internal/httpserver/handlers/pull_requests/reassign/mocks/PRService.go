@@ -14,9 +14,9 @@ type PRService struct {
 	mock.Mock
 }
 
-// ReassignReviewer provides a mock function with given fields: ctx, prID, oldUserID
-func (_m *PRService) ReassignReviewer(ctx context.Context, prID string, oldUserID string) (*domains.PullRequest, string, error) {
-	ret := _m.Called(ctx, prID, oldUserID)
+// ReassignReviewer provides a mock function with given fields: ctx, prID, oldUserID, ifVersion
+func (_m *PRService) ReassignReviewer(ctx context.Context, prID string, oldUserID string, ifVersion int64) (*domains.PullRequest, string, error) {
+	ret := _m.Called(ctx, prID, oldUserID, ifVersion)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ReassignReviewer")
@@ -25,25 +25,25 @@ func (_m *PRService) ReassignReviewer(ctx context.Context, prID string, oldUserI
 	var r0 *domains.PullRequest
 	var r1 string
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domains.PullRequest, string, error)); ok {
-		return rf(ctx, prID, oldUserID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) (*domains.PullRequest, string, error)); ok {
+		return rf(ctx, prID, oldUserID, ifVersion)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domains.PullRequest); ok {
-		r0 = rf(ctx, prID, oldUserID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) *domains.PullRequest); ok {
+		r0 = rf(ctx, prID, oldUserID, ifVersion)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domains.PullRequest)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) string); ok {
-		r1 = rf(ctx, prID, oldUserID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64) string); ok {
+		r1 = rf(ctx, prID, oldUserID, ifVersion)
 	} else {
 		r1 = ret.Get(1).(string)
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, string, string) error); ok {
-		r2 = rf(ctx, prID, oldUserID)
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, int64) error); ok {
+		r2 = rf(ctx, prID, oldUserID, ifVersion)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -9,13 +9,15 @@ import (
 
 	"github.com/Deymos01/pr-review-manager/internal/domains"
 	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/pull_requests"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/etag"
 	"github.com/Deymos01/pr-review-manager/internal/lib/api/response"
 	"github.com/Deymos01/pr-review-manager/internal/usecase"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@v2.53.5 --name=PRService
 type PRService interface {
-	ReassignReviewer(ctx context.Context, prID, oldUserID string) (*domains.PullRequest, string, error)
+	ReassignReviewer(ctx context.Context, prID, oldUserID string, ifVersion int64) (*domains.PullRequest, string, error)
 }
 
 type Request struct {
@@ -24,14 +26,8 @@ type Request struct {
 }
 
 type Response struct {
-	Pr struct {
-		PrID              string   `json:"pull_request_id"`
-		PrName            string   `json:"pull_request_name"`
-		AuthorID          string   `json:"author_id"`
-		Status            string   `json:"status"`
-		AssignedReviewers []string `json:"assigned_reviewers"`
-	} `json:"pr"`
-	NewUserID string `json:"replaced_by"`
+	Pr        pull_requests.PullRequest `json:"pr"`
+	NewUserID string                    `json:"replaced_by"`
 }
 
 func New(
@@ -52,7 +48,17 @@ func New(
 			return
 		}
 
-		pr, newUserID, err := prService.ReassignReviewer(r.Context(), req.PrID, req.OldUserID)
+		ifVersion, err := etag.IfMatch(r)
+		if err != nil {
+			log.Warn("invalid If-Match header", slog.String("if_match", r.Header.Get("If-Match")))
+
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).
+				Encode(response.NewErrorResponse(handlers.InvalidRequest, "invalid If-Match header"))
+			return
+		}
+
+		pr, newUserID, err := prService.ReassignReviewer(r.Context(), req.PrID, req.OldUserID, ifVersion)
 		if err != nil {
 			log.Warn("failed to reassign reviewer", slog.Any("error", err))
 
@@ -65,6 +71,10 @@ func New(
 				w.WriteHeader(http.StatusConflict)
 				_ = json.NewEncoder(w).
 					Encode(response.NewErrorResponse(handlers.PrMerged, "cannot reassign on merged PR"))
+			case errors.Is(err, usecase.ErrVersionConflict):
+				w.WriteHeader(http.StatusPreconditionFailed)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.PreconditionFailed, "pull request was modified, reload and retry"))
 			case errors.Is(err, usecase.ErrUserNotAssigned):
 				w.WriteHeader(http.StatusConflict)
 				_ = json.NewEncoder(w).
@@ -81,16 +91,9 @@ func New(
 			return
 		}
 
-		var resp Response
-		resp.Pr.PrID = pr.ID
-		resp.Pr.PrName = pr.Name
-		resp.Pr.AuthorID = pr.Author.ID
-		resp.Pr.Status = pr.Status
-		for _, reviewer := range pr.Reviewers {
-			resp.Pr.AssignedReviewers = append(resp.Pr.AssignedReviewers, reviewer.User.ID)
-		}
-		resp.NewUserID = newUserID
+		resp := Response{Pr: pull_requests.NewPullRequest(pr), NewUserID: newUserID}
 
+		w.Header().Set("ETag", etag.Format(pr.Version))
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
 			log.Error("failed to encode response", slog.Any("error", err))
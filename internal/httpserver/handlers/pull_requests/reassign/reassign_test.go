@@ -26,6 +26,8 @@ func TestReassignHandler(t *testing.T) {
 	type testCase struct {
 		name           string
 		body           string
+		ifMatch        string
+		ifVersion      int64
 		mockReturnPR   *domains.PullRequest
 		mockReturnID   string
 		mockError      error
@@ -43,15 +45,32 @@ func TestReassignHandler(t *testing.T) {
 				Author: &domains.User{
 					ID: "1",
 				},
-				Status: "OPEN",
+				Status:  "OPEN",
+				Version: 2,
 				Reviewers: []*domains.Reviewer{
-					{User: &domains.User{ID: "u2"}},
-					{User: &domains.User{ID: "u3"}},
+					{User: &domains.User{ID: "u2", Name: "Bob"}},
+					{User: &domains.User{ID: "u3", Name: "Dave"}},
 				},
 			},
 			mockReturnID:   "u9",
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name:           "Invalid If-Match",
+			body:           `{"pull_request_id":"1","old_reviewer_id":"u1"}`,
+			ifMatch:        "1",
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "invalid If-Match header",
+		},
+		{
+			name:           "If-Match mismatch",
+			body:           `{"pull_request_id":"1","old_reviewer_id":"u1"}`,
+			ifMatch:        `"1"`,
+			ifVersion:      1,
+			mockError:      usecase.ErrVersionConflict,
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedErr:    "pull request was modified, reload and retry",
+		},
 		{
 			name:           "Invalid JSON",
 			body:           `{"pull_request_id":`,
@@ -113,13 +132,16 @@ func TestReassignHandler(t *testing.T) {
 			if tc.expectedStatus != http.StatusBadRequest {
 				svc.On(
 					"ReassignReviewer",
-					mock.Anything, "1", "u1",
+					mock.Anything, "1", "u1", tc.ifVersion,
 				).Return(tc.mockReturnPR, tc.mockReturnID, tc.mockError).Once()
 			}
 
 			handler := reassign.New(discardLogger(), svc)
 
 			req := httptest.NewRequest(http.MethodPost, "/pullRequest/reassign", bytes.NewBufferString(tc.body))
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
 			rr := httptest.NewRecorder()
 
 			handler.ServeHTTP(rr, req)
@@ -136,17 +158,23 @@ func TestReassignHandler(t *testing.T) {
 			}
 
 			if rr.Code == http.StatusOK {
+				require.Equal(t, `"2"`, rr.Header().Get("ETag"))
+
 				pr := resp["pr"].(map[string]any)
 				require.Equal(t, tc.mockReturnPR.ID, pr["pull_request_id"])
 				require.Equal(t, tc.mockReturnPR.Name, pr["pull_request_name"])
 				require.Equal(t, tc.mockReturnPR.Author.ID, pr["author_id"])
-				require.Equal(t, tc.mockReturnPR.Status, pr["status"])
+				require.Equal(t, string(tc.mockReturnPR.Status), pr["status"])
 
 				arr := pr["assigned_reviewers"].([]any)
 				require.Len(t, arr, len(tc.mockReturnPR.Reviewers))
 				require.Equal(t, "u2", arr[0])
 				require.Equal(t, "u3", arr[1])
 
+				reviewers := pr["reviewers"].([]any)
+				require.Len(t, reviewers, 2)
+				require.Equal(t, "Dave", reviewers[1].(map[string]any)["username"])
+
 				require.Equal(t, tc.mockReturnID, resp["replaced_by"])
 			}
 		})
@@ -0,0 +1,93 @@
+// Package pull_requests holds the pull request representation shared by the /pullRequest
+// handlers, so that every endpoint returns the same fields.
+package pull_requests
+
+import (
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+)
+
+type Reviewer struct {
+	UserID     string    `json:"user_id"`
+	Username   string    `json:"username"`
+	IsActive   bool      `json:"is_active"`
+	AssignedAt time.Time `json:"assigned_at"`
+}
+
+// pullRequest holds the fields every endpoint returns.
+type pullRequest struct {
+	PrID              string     `json:"pull_request_id"`
+	PrName            string     `json:"pull_request_name"`
+	Description       string     `json:"description"`
+	Labels            []string   `json:"labels"`
+	AuthorID          string     `json:"author_id"`
+	AuthorName        string     `json:"author_name"`
+	TeamName          *string    `json:"team_name"`
+	Status            string     `json:"status"`
+	NeedMoreReviewers bool       `json:"need_more_reviewers"`
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	Reviewers         []Reviewer `json:"reviewers"`
+	Version           int64      `json:"version"`
+}
+
+// PullRequest is returned by the endpoints changing a pull request. Its timestamps keep
+// the camelCase names of the first API version.
+type PullRequest struct {
+	pullRequest
+	CreatedAt time.Time  `json:"createdAt"`
+	MergedAt  *time.Time `json:"mergedAt"`
+}
+
+// PullRequestDetails is returned by /pullRequest/get and /pullRequest/list.
+type PullRequestDetails struct {
+	pullRequest
+	CreatedAt time.Time  `json:"created_at"`
+	MergedAt  *time.Time `json:"merged_at"`
+}
+
+func NewPullRequest(pr *domains.PullRequest) PullRequest {
+	return PullRequest{
+		pullRequest: newPullRequest(pr),
+		CreatedAt:   pr.CreatedAt,
+		MergedAt:    pr.MergedAt,
+	}
+}
+
+func NewPullRequestDetails(pr *domains.PullRequest) PullRequestDetails {
+	return PullRequestDetails{
+		pullRequest: newPullRequest(pr),
+		CreatedAt:   pr.CreatedAt,
+		MergedAt:    pr.MergedAt,
+	}
+}
+
+func newPullRequest(pr *domains.PullRequest) pullRequest {
+	resp := pullRequest{
+		PrID:              pr.ID,
+		PrName:            pr.Name,
+		Description:       pr.Description,
+		Labels:            pr.Labels,
+		AuthorID:          pr.Author.ID,
+		AuthorName:        pr.Author.Name,
+		TeamName:          pr.Author.TeamName,
+		Status:            string(pr.Status),
+		NeedMoreReviewers: pr.NeedMoreReviewers,
+		AssignedReviewers: make([]string, 0, len(pr.Reviewers)),
+		Reviewers:         make([]Reviewer, 0, len(pr.Reviewers)),
+		Version:           pr.Version,
+	}
+	if resp.Labels == nil {
+		resp.Labels = []string{}
+	}
+	for _, rev := range pr.Reviewers {
+		resp.AssignedReviewers = append(resp.AssignedReviewers, rev.User.ID)
+		resp.Reviewers = append(resp.Reviewers, Reviewer{
+			UserID:     rev.User.ID,
+			Username:   rev.User.Name,
+			IsActive:   rev.User.IsActive,
+			AssignedAt: rev.AssignedAt,
+		})
+	}
+	return resp
+}
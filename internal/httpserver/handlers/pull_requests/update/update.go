@@ -0,0 +1,167 @@
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/pull_requests"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/etag"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/response"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.53.5 --name=PRService
+type PRService interface {
+	UpdatePullRequest(ctx context.Context, prID string, upd domains.PullRequestUpdate) (*domains.PullRequest, error)
+}
+
+// Request changes only the fields that are present; Version must be the one last read by the
+// client and may be sent as an If-Match header instead.
+type Request struct {
+	PrID        string    `json:"pull_request_id"`
+	Version     int64     `json:"version"`
+	PrName      *string   `json:"pull_request_name"`
+	Description *string   `json:"description"`
+	Labels      *[]string `json:"labels"`
+	AuthorID    *string   `json:"author_id"`
+}
+
+type Response struct {
+	Pr pull_requests.PullRequest `json:"pr"`
+}
+
+func New(
+	log *slog.Logger,
+	prService PRService,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.pull_requests.update.New"
+		log = log.With(slog.String("op", op))
+
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			log.Warn("invalid request body", slog.Any("error", err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).
+				Encode(response.NewErrorResponse(handlers.InvalidRequest, "invalid JSON format"))
+			return
+		}
+
+		ifVersion, err := etag.IfMatch(r)
+		if err != nil {
+			log.Warn("invalid If-Match header", slog.String("if_match", r.Header.Get("If-Match")))
+
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).
+				Encode(response.NewErrorResponse(handlers.InvalidRequest, "invalid If-Match header"))
+			return
+		}
+		if ifVersion != 0 {
+			if req.Version != 0 && req.Version != ifVersion {
+				log.Warn("version does not match If-Match header")
+
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.InvalidRequest, "version does not match If-Match header"))
+				return
+			}
+			req.Version = ifVersion
+		}
+
+		if msg := validate(&req); msg != "" {
+			log.Warn("invalid update request", slog.String("error", msg))
+
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).
+				Encode(response.NewErrorResponse(handlers.InvalidRequest, msg))
+			return
+		}
+
+		pr, err := prService.UpdatePullRequest(r.Context(), req.PrID, domains.PullRequestUpdate{
+			Name:        req.PrName,
+			Description: req.Description,
+			Labels:      req.Labels,
+			AuthorID:    req.AuthorID,
+			Version:     req.Version,
+		})
+		if err != nil {
+			log.Warn("failed to update pull request", slog.Any("error", err))
+
+			switch {
+			case errors.Is(err, usecase.ErrPullRequestNotFound) ||
+				errors.Is(err, usecase.ErrUserNotFound) ||
+				errors.Is(err, usecase.ErrTeamNotFound):
+				w.WriteHeader(http.StatusNotFound)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.NotFound, "resource not found"))
+			case errors.Is(err, usecase.ErrPRAlreadyMerged):
+				w.WriteHeader(http.StatusConflict)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.PrMerged, "cannot update merged PR"))
+			case errors.Is(err, usecase.ErrVersionConflict) && ifVersion != 0:
+				w.WriteHeader(http.StatusPreconditionFailed)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.PreconditionFailed, "pull request was modified, reload and retry"))
+			case errors.Is(err, usecase.ErrVersionConflict):
+				w.WriteHeader(http.StatusConflict)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.VersionConflict, "pull request was modified, reload and retry"))
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.InternalError, "internal server error"))
+			}
+			return
+		}
+
+		resp := Response{Pr: pull_requests.NewPullRequest(pr)}
+
+		w.Header().Set("ETag", etag.Format(pr.Version))
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.Error("failed to encode response", slog.Any("error", err))
+		}
+	}
+}
+
+// validate normalizes labels and returns a message describing the first problem, if any.
+func validate(req *Request) string {
+	if req.PrID == "" {
+		return "pull_request_id is required"
+	}
+	if req.Version < 1 {
+		return "version is required"
+	}
+	if req.PrName != nil && strings.TrimSpace(*req.PrName) == "" {
+		return "pull_request_name must not be empty"
+	}
+	if req.AuthorID != nil && *req.AuthorID == "" {
+		return "author_id must not be empty"
+	}
+
+	if req.Labels != nil {
+		seen := make(map[string]struct{}, len(*req.Labels))
+		labels := make([]string, 0, len(*req.Labels))
+		for _, label := range *req.Labels {
+			label = strings.TrimSpace(label)
+			if label == "" {
+				return "labels must not contain empty values"
+			}
+			if _, ok := seen[label]; ok {
+				continue
+			}
+			seen[label] = struct{}{}
+			labels = append(labels, label)
+		}
+		req.Labels = &labels
+	}
+
+	return ""
+}
@@ -0,0 +1,194 @@
+package update_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/pull_requests/update"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/pull_requests/update/mocks"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestUpdateHandler(t *testing.T) {
+	updated := &domains.PullRequest{
+		ID:          "pr1",
+		Name:        "New title",
+		Description: "Details",
+		Labels:      []string{"backend", "urgent"},
+		Author:      &domains.User{ID: "u4", Name: "Dan"},
+		Status:      "OPEN",
+		Reviewers: []*domains.Reviewer{
+			{User: &domains.User{ID: "u5", Name: "Eve", IsActive: true}},
+		},
+		Version: 4,
+	}
+
+	type testCase struct {
+		name           string
+		body           string
+		ifMatch        string
+		expectedUpdate *domains.PullRequestUpdate
+		mockError      error
+		expectedStatus int
+		expectedErr    string
+	}
+
+	cases := []testCase{
+		{
+			name:           "Version from If-Match",
+			body:           `{"pull_request_id":"pr1","pull_request_name":"New title"}`,
+			ifMatch:        `"3"`,
+			expectedUpdate: &domains.PullRequestUpdate{Name: ptr("New title"), Version: 3},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Version disagrees with If-Match",
+			body:           `{"pull_request_id":"pr1","version":2}`,
+			ifMatch:        `"3"`,
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "version does not match If-Match header",
+		},
+		{
+			name:           "If-Match mismatch",
+			body:           `{"pull_request_id":"pr1"}`,
+			ifMatch:        `"3"`,
+			expectedUpdate: &domains.PullRequestUpdate{Version: 3},
+			mockError:      usecase.ErrVersionConflict,
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedErr:    "pull request was modified, reload and retry",
+		},
+		{
+			name: "Success",
+			body: `{"pull_request_id":"pr1","version":3,"pull_request_name":"New title",` +
+				`"description":"Details","labels":[" backend","urgent","backend"],"author_id":"u4"}`,
+			expectedUpdate: &domains.PullRequestUpdate{
+				Name:        ptr("New title"),
+				Description: ptr("Details"),
+				Labels:      &[]string{"backend", "urgent"},
+				AuthorID:    ptr("u4"),
+				Version:     3,
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid JSON",
+			body:           `{"pull_request_id":`,
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "invalid JSON format",
+		},
+		{
+			name:           "Missing version",
+			body:           `{"pull_request_id":"pr1","pull_request_name":"New title"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "version is required",
+		},
+		{
+			name:           "Empty title",
+			body:           `{"pull_request_id":"pr1","version":3,"pull_request_name":"  "}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "pull_request_name must not be empty",
+		},
+		{
+			name:           "Empty label",
+			body:           `{"pull_request_id":"pr1","version":3,"labels":["ok",""]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "labels must not contain empty values",
+		},
+		{
+			name:           "Version conflict",
+			body:           `{"pull_request_id":"pr1","version":2,"description":"Details"}`,
+			expectedUpdate: &domains.PullRequestUpdate{Description: ptr("Details"), Version: 2},
+			mockError:      usecase.ErrVersionConflict,
+			expectedStatus: http.StatusConflict,
+			expectedErr:    "pull request was modified, reload and retry",
+		},
+		{
+			name:           "Merged",
+			body:           `{"pull_request_id":"pr1","version":2,"description":"Details"}`,
+			expectedUpdate: &domains.PullRequestUpdate{Description: ptr("Details"), Version: 2},
+			mockError:      usecase.ErrPRAlreadyMerged,
+			expectedStatus: http.StatusConflict,
+			expectedErr:    "cannot update merged PR",
+		},
+		{
+			name:           "Not found",
+			body:           `{"pull_request_id":"pr1","version":2,"author_id":"u9"}`,
+			expectedUpdate: &domains.PullRequestUpdate{AuthorID: ptr("u9"), Version: 2},
+			mockError:      usecase.ErrUserNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedErr:    "resource not found",
+		},
+		{
+			name:           "Unknown error",
+			body:           `{"pull_request_id":"pr1","version":2}`,
+			expectedUpdate: &domains.PullRequestUpdate{Version: 2},
+			mockError:      errors.New("db down"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedErr:    "internal server error",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := mocks.NewPRService(t)
+
+			if tc.expectedUpdate != nil {
+				var pr *domains.PullRequest
+				if tc.mockError == nil {
+					pr = updated
+				}
+				svc.On("UpdatePullRequest", mock.Anything, "pr1", *tc.expectedUpdate).
+					Return(pr, tc.mockError).
+					Once()
+			}
+
+			handler := update.New(discardLogger(), svc)
+
+			req := httptest.NewRequest(http.MethodPost, "/pullRequest/update", bytes.NewReader([]byte(tc.body)))
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+
+			if tc.expectedErr != "" {
+				var resp map[string]any
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Equal(t, tc.expectedErr, resp["error"].(map[string]any)["message"])
+				return
+			}
+
+			require.Equal(t, `"4"`, rr.Header().Get("ETag"))
+
+			var resp update.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, "New title", resp.Pr.PrName)
+			require.Equal(t, []string{"backend", "urgent"}, resp.Pr.Labels)
+			require.Equal(t, "u4", resp.Pr.AuthorID)
+			require.Equal(t, []string{"u5"}, resp.Pr.AssignedReviewers)
+			require.Equal(t, int64(4), resp.Pr.Version)
+		})
+	}
+}
+
+func ptr(s string) *string { return &s }
@@ -3,12 +3,14 @@ package add
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 
 	"github.com/Deymos01/pr-review-manager/internal/domains"
 	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers"
 	"github.com/Deymos01/pr-review-manager/internal/lib/api/response"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@v2.53.5 --name=TeamService
@@ -20,6 +22,7 @@ type Member struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
 	IsActive bool   `json:"is_active"`
+	Role     string `json:"role,omitempty"`
 }
 
 type Request struct {
@@ -54,11 +57,23 @@ func New(
 
 		var members []*domains.User
 		for _, m := range req.Members {
+			// an omitted role keeps the current role of an existing user
+			role := domains.Role(m.Role)
+			if role != "" && !role.Valid() {
+				log.Warn("invalid member role", slog.String("user_id", m.UserID), slog.String("role", m.Role))
+
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.InvalidRequest, "role must be one of: lead, member, observer"))
+				return
+			}
+
 			members = append(members, &domains.User{
 				ID:       m.UserID,
 				Name:     m.Username,
 				TeamName: &req.TeamName,
 				IsActive: m.IsActive,
+				Role:     role,
 			})
 		}
 		team := domains.Team{
@@ -68,6 +83,14 @@ func New(
 
 		createdTeam, err := service.AddTeam(r.Context(), &team)
 		if err != nil {
+			if errors.Is(err, usecase.ErrUserDeleted) {
+				log.Warn("team references deleted user", slog.Any("error", err))
+
+				w.WriteHeader(http.StatusConflict)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.UserDeleted, "team references a deleted user"))
+				return
+			}
 			log.Error("failed to create team", slog.Any("error", err))
 
 			w.WriteHeader(http.StatusBadRequest)
@@ -85,6 +108,7 @@ func New(
 				UserID:   m.ID,
 				Username: m.Name,
 				IsActive: m.IsActive,
+				Role:     string(m.Role),
 			}
 		}
 
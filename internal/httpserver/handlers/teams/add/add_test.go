@@ -13,6 +13,7 @@ import (
 	"github.com/Deymos01/pr-review-manager/internal/domains"
 	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/teams/add"
 	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/teams/add/mocks"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -37,15 +38,15 @@ func TestAddTeamHandler(t *testing.T) {
 			body: `{
 				"team_name":"team",
 				"members":[
-					{"user_id":"u1","username":"Alice","is_active":true},
+					{"user_id":"u1","username":"Alice","is_active":true,"role":"lead"},
 					{"user_id":"u2","username":"Bob","is_active":false}
 				]
 			}`,
 			mockReturnTeam: &domains.Team{
 				Name: "team",
 				Members: []*domains.User{
-					{ID: "u1", Name: "Alice", TeamName: ptr("team"), IsActive: true},
-					{ID: "u2", Name: "Bob", TeamName: ptr("team"), IsActive: false},
+					{ID: "u1", Name: "Alice", TeamName: ptr("team"), IsActive: true, Role: domains.RoleLead},
+					{ID: "u2", Name: "Bob", TeamName: ptr("team"), IsActive: false, Role: domains.RoleMember},
 				},
 			},
 			expectedStatus: http.StatusCreated,
@@ -56,6 +57,12 @@ func TestAddTeamHandler(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectedErr:    "invalid JSON format",
 		},
+		{
+			name:           "Invalid role",
+			body:           `{"team_name":"team","members":[{"user_id":"u1","username":"Alice","role":"owner"}]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "role must be one of: lead, member, observer",
+		},
 		{
 			name:           "Team already exists",
 			body:           `{"team_name":"team","members":[]}`,
@@ -63,6 +70,13 @@ func TestAddTeamHandler(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectedErr:    "team_name already exists",
 		},
+		{
+			name:           "Deleted member",
+			body:           `{"team_name":"team","members":[{"user_id":"u1","username":"Alice","is_active":true}]}`,
+			mockError:      usecase.ErrUserDeleted,
+			expectedStatus: http.StatusConflict,
+			expectedErr:    "team references a deleted user",
+		},
 	}
 
 	for _, tc := range cases {
@@ -106,6 +120,8 @@ func TestAddTeamHandler(t *testing.T) {
 
 				members := team["members"].([]any)
 				require.Len(t, members, 2)
+				require.Equal(t, "lead", members[0].(map[string]any)["role"])
+				require.Equal(t, "member", members[1].(map[string]any)["role"])
 			}
 		})
 	}
@@ -9,13 +9,21 @@ import (
 
 	"github.com/Deymos01/pr-review-manager/internal/domains"
 	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers"
+	mw "github.com/Deymos01/pr-review-manager/internal/httpserver/middlewares"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/etag"
 	"github.com/Deymos01/pr-review-manager/internal/lib/api/response"
 	"github.com/Deymos01/pr-review-manager/internal/usecase"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@v2.53.5 --name=TeamService
 type TeamService interface {
-	DeactivateTeamMembers(ctx context.Context, teamName string, users []string) (*domains.Team, []*domains.ReassignedPR, error)
+	IsTeamLead(ctx context.Context, userID, teamName string) (bool, error)
+	DeactivateTeamMembers(
+		ctx context.Context,
+		teamName string,
+		users []string,
+		ifVersion int64,
+	) (*domains.Team, []*domains.ReassignedPR, error)
 }
 
 type Request struct {
@@ -27,6 +35,7 @@ type Member struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
 	IsActive bool   `json:"is_active"`
+	Role     string `json:"role"`
 }
 
 type ReassignedPR struct {
@@ -61,7 +70,41 @@ func New(
 			return
 		}
 
-		team, reassignedPRs, err := service.DeactivateTeamMembers(r.Context(), req.TeamName, req.UserIDs)
+		ifVersion, err := etag.IfMatch(r)
+		if err != nil {
+			log.Warn("invalid If-Match header", slog.String("if_match", r.Header.Get("If-Match")))
+
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).
+				Encode(response.NewErrorResponse(handlers.InvalidRequest, "invalid If-Match header"))
+			return
+		}
+
+		// Besides admins, only leads may deactivate members of their own team
+		if !mw.IsAdmin(r.Context()) {
+			userID := mw.UserID(r.Context())
+			isLead, err := service.IsTeamLead(r.Context(), userID, req.TeamName)
+			if err != nil {
+				log.Error("failed to check team lead", slog.String("user_id", userID), slog.Any("error", err))
+
+				w.WriteHeader(http.StatusInternalServerError)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.InternalError, "internal server error"))
+				return
+			}
+			if !isLead {
+				log.Warn("user is not a lead of the team",
+					slog.String("user_id", userID),
+					slog.String("team_name", req.TeamName))
+
+				w.WriteHeader(http.StatusForbidden)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.Forbidden, "only team leads can deactivate members"))
+				return
+			}
+		}
+
+		team, reassignedPRs, err := service.DeactivateTeamMembers(r.Context(), req.TeamName, req.UserIDs, ifVersion)
 		if err != nil {
 			switch {
 			case errors.Is(err, usecase.ErrTeamNotFound):
@@ -76,9 +119,15 @@ func New(
 				w.WriteHeader(http.StatusConflict)
 				_ = json.NewEncoder(w).
 					Encode(response.NewErrorResponse(handlers.TeamCompatibilityError, "some users do not belong to the team"))
+			case errors.Is(err, usecase.ErrVersionConflict):
+				log.Warn("team was modified concurrently", slog.String("team_name", req.TeamName))
+
+				w.WriteHeader(http.StatusPreconditionFailed)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.PreconditionFailed, "team was modified, reload and retry"))
 			default:
 				log.Error("failed to deactivate members in team",
-					slog.String("team_name", team.Name),
+					slog.String("team_name", req.TeamName),
 					slog.Any("error", err))
 
 				w.WriteHeader(http.StatusInternalServerError)
@@ -98,6 +147,7 @@ func New(
 				UserID:   m.ID,
 				Username: m.Name,
 				IsActive: m.IsActive,
+				Role:     string(m.Role),
 			})
 		}
 
@@ -109,6 +159,7 @@ func New(
 			})
 		}
 
+		w.Header().Set("ETag", etag.Format(team.Version))
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
 			log.Error("failed to encode response", slog.Any("error", err))
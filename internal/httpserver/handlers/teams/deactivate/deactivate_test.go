@@ -0,0 +1,212 @@
+package deactivate_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/teams/deactivate"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/teams/deactivate/mocks"
+	mw "github.com/Deymos01/pr-review-manager/internal/httpserver/middlewares"
+	"github.com/Deymos01/pr-review-manager/internal/lib/tenant"
+	"github.com/Deymos01/pr-review-manager/internal/lib/usertoken"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var userTokenSecret = []byte("user-secret")
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestDeactivateHandler(t *testing.T) {
+	type testCase struct {
+		name       string
+		body       string
+		adminToken string
+		userID     string
+		ifMatch    string
+		ifVersion  int64
+
+		checkLead   bool
+		isLead      bool
+		mockErrLead error
+
+		deactivate     bool
+		mockReturnTeam *domains.Team
+		mockReturnPRs  []*domains.ReassignedPR
+		mockError      error
+
+		expectedStatus int
+		expectedErr    string
+	}
+
+	team := &domains.Team{
+		Name:    "team",
+		Version: 5,
+		Members: []*domains.User{
+			{ID: "u1", Name: "Alice", IsActive: true, Role: domains.RoleLead},
+			{ID: "u2", Name: "Bob", IsActive: false, Role: domains.RoleMember},
+		},
+	}
+	reassigned := []*domains.ReassignedPR{{PrID: "pr1", OldUserID: "u2", NewUserID: "u3"}}
+
+	cases := []testCase{
+		{
+			name:           "Admin success",
+			body:           `{"team_name":"team","users":["u2"]}`,
+			adminToken:     "admin",
+			deactivate:     true,
+			mockReturnTeam: team,
+			mockReturnPRs:  reassigned,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Lead success",
+			body:           `{"team_name":"team","users":["u2"]}`,
+			userID:         "u1",
+			checkLead:      true,
+			isLead:         true,
+			deactivate:     true,
+			mockReturnTeam: team,
+			mockReturnPRs:  reassigned,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Not a lead",
+			body:           `{"team_name":"team","users":["u2"]}`,
+			userID:         "u2",
+			checkLead:      true,
+			isLead:         false,
+			expectedStatus: http.StatusForbidden,
+			expectedErr:    "only team leads can deactivate members",
+		},
+		{
+			name:           "Lead check fails",
+			body:           `{"team_name":"team","users":["u2"]}`,
+			userID:         "u1",
+			checkLead:      true,
+			mockErrLead:    errors.New("unexpected"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedErr:    "internal server error",
+		},
+		{
+			name:           "Invalid JSON",
+			body:           `{"team_name":`,
+			adminToken:     "admin",
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "invalid JSON format",
+		},
+		{
+			name:           "Team not found",
+			body:           `{"team_name":"team","users":["u2"]}`,
+			adminToken:     "admin",
+			deactivate:     true,
+			mockError:      usecase.ErrTeamNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedErr:    "resource not found",
+		},
+		{
+			name:           "Invalid If-Match",
+			body:           `{"team_name":"team","users":["u2"]}`,
+			adminToken:     "admin",
+			ifMatch:        "4",
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "invalid If-Match header",
+		},
+		{
+			name:           "If-Match mismatch",
+			body:           `{"team_name":"team","users":["u2"]}`,
+			adminToken:     "admin",
+			ifMatch:        `"4"`,
+			ifVersion:      4,
+			deactivate:     true,
+			mockError:      usecase.ErrVersionConflict,
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedErr:    "team was modified, reload and retry",
+		},
+		{
+			name:           "Users outside of team",
+			body:           `{"team_name":"team","users":["u2"]}`,
+			adminToken:     "admin",
+			deactivate:     true,
+			mockError:      usecase.ErrTeamCompatibility,
+			expectedStatus: http.StatusConflict,
+			expectedErr:    "some users do not belong to the team",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := mocks.NewTeamService(t)
+
+			if tc.checkLead {
+				svc.On("IsTeamLead", mock.Anything, tc.userID, "team").
+					Return(tc.isLead, tc.mockErrLead).
+					Once()
+			}
+			if tc.deactivate {
+				svc.On("DeactivateTeamMembers", mock.Anything, "team", []string{"u2"}, tc.ifVersion).
+					Return(tc.mockReturnTeam, tc.mockReturnPRs, tc.mockError).
+					Once()
+			}
+
+			auth := mw.AdminOrUserAuthMiddleware(map[string]string{"admin": tenant.Default}, userTokenSecret)
+			handler := auth(deactivate.New(discardLogger(), svc))
+
+			req := httptest.NewRequest(http.MethodPost, "/team/deactivate", bytes.NewBufferString(tc.body))
+			if tc.adminToken != "" {
+				req.Header.Set("X-Admin-Token", tc.adminToken)
+			}
+			if tc.userID != "" {
+				token := usertoken.Sign(userTokenSecret, tenant.Default, tc.userID, time.Now().Add(time.Hour))
+				req.Header.Set("X-User-Token", token)
+			}
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			if tc.expectedErr != "" {
+				errResp := resp["error"].(map[string]any)
+				require.Equal(t, tc.expectedErr, errResp["message"])
+				return
+			}
+
+			if rr.Code == http.StatusOK {
+				require.Equal(t, `"5"`, rr.Header().Get("ETag"))
+
+				team := resp["team"].(map[string]any)
+				require.Equal(t, "team", team["team_name"])
+
+				members := team["members"].([]any)
+				require.Len(t, members, 2)
+				require.Equal(t, "lead", members[0].(map[string]any)["role"])
+
+				prs := resp["pull_requests"].([]any)
+				require.Len(t, prs, 1)
+				require.Equal(t, "u3", prs[0].(map[string]any)["replaced_by"])
+			}
+		})
+	}
+}
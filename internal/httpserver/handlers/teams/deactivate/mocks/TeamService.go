@@ -15,29 +15,66 @@ type TeamService struct {
 	mock.Mock
 }
 
-// DeactivateTeam provides a mock function with given fields: ctx, teamName
-func (_m *TeamService) DeactivateTeam(ctx context.Context, teamName string) (*domains.Team, error) {
-	ret := _m.Called(ctx, teamName)
+// DeactivateTeamMembers provides a mock function with given fields: ctx, teamName, users, ifVersion
+func (_m *TeamService) DeactivateTeamMembers(ctx context.Context, teamName string, users []string, ifVersion int64) (*domains.Team, []*domains.ReassignedPR, error) {
+	ret := _m.Called(ctx, teamName, users, ifVersion)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeactivateTeam")
+		panic("no return value specified for DeactivateTeamMembers")
 	}
 
 	var r0 *domains.Team
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*domains.Team, error)); ok {
-		return rf(ctx, teamName)
+	var r1 []*domains.ReassignedPR
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, int64) (*domains.Team, []*domains.ReassignedPR, error)); ok {
+		return rf(ctx, teamName, users, ifVersion)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *domains.Team); ok {
-		r0 = rf(ctx, teamName)
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, int64) *domains.Team); ok {
+		r0 = rf(ctx, teamName, users, ifVersion)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domains.Team)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, teamName)
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string, int64) []*domains.ReassignedPR); ok {
+		r1 = rf(ctx, teamName, users, ifVersion)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]*domains.ReassignedPR)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, []string, int64) error); ok {
+		r2 = rf(ctx, teamName, users, ifVersion)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// IsTeamLead provides a mock function with given fields: ctx, userID, teamName
+func (_m *TeamService) IsTeamLead(ctx context.Context, userID string, teamName string) (bool, error) {
+	ret := _m.Called(ctx, userID, teamName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsTeamLead")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (bool, error)); ok {
+		return rf(ctx, userID, teamName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) bool); ok {
+		r0 = rf(ctx, userID, teamName)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, teamName)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/Deymos01/pr-review-manager/internal/domains"
 	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/etag"
 	"github.com/Deymos01/pr-review-manager/internal/lib/api/response"
 )
 
@@ -20,6 +21,7 @@ type MemberResponse struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
 	IsActive bool   `json:"is_active"`
+	Role     string `json:"role"`
 }
 
 type Response struct {
@@ -52,6 +54,7 @@ func New(
 				UserID:   m.ID,
 				Username: m.Name,
 				IsActive: m.IsActive,
+				Role:     string(m.Role),
 			})
 		}
 
@@ -60,6 +63,7 @@ func New(
 			Members:  members,
 		}
 
+		w.Header().Set("ETag", etag.Format(team.Version))
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
 			log.Error("failed to encode response", slog.String("error", err.Error()))
@@ -35,7 +35,8 @@ func TestGetTeamHandler(t *testing.T) {
 			name:     "Success",
 			teamName: "team",
 			mockReturnTeam: &domains.Team{
-				Name: "team",
+				Name:    "team",
+				Version: 3,
 				Members: []*domains.User{
 					{ID: "u1", Name: "Alice", IsActive: true},
 					{ID: "u2", Name: "Bob", IsActive: false},
@@ -92,6 +93,7 @@ func TestGetTeamHandler(t *testing.T) {
 			}
 
 			if rr.Code == http.StatusOK {
+				require.Equal(t, `"3"`, rr.Header().Get("ETag"))
 				require.Equal(t, tc.teamName, resp["team_name"])
 
 				members := resp["members"].([]any)
@@ -0,0 +1,100 @@
+package list
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/pagination"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/response"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.53.5 --name=TeamService
+type TeamService interface {
+	ListTeams(ctx context.Context, filter domains.TeamFilter) (*domains.TeamPage, error)
+}
+
+type TeamResponse struct {
+	TeamName      string `json:"team_name"`
+	MembersCount  int    `json:"members_count"`
+	ActiveMembers int    `json:"active_members"`
+}
+
+type Response struct {
+	Teams      []TeamResponse `json:"teams"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+func New(
+	log *slog.Logger,
+	service TeamService,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.team.list.New"
+		log = log.With(slog.String("op", op))
+
+		filter, err := parseFilter(r.URL.Query())
+		if err != nil {
+			log.Warn("invalid list parameters", slog.String("error", err.Error()))
+
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).
+				Encode(response.NewErrorResponse(handlers.InvalidRequest, err.Error()))
+			return
+		}
+
+		page, err := service.ListTeams(r.Context(), filter)
+		if err != nil {
+			log.Error("failed to list teams", slog.String("error", err.Error()))
+
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).
+				Encode(response.NewErrorResponse(handlers.InternalError, "internal server error"))
+			return
+		}
+
+		resp := Response{
+			Teams:      make([]TeamResponse, 0, len(page.Teams)),
+			NextCursor: pagination.EncodeCursor(page.Next),
+		}
+		for _, t := range page.Teams {
+			resp.Teams = append(resp.Teams, TeamResponse{
+				TeamName:      t.Name,
+				MembersCount:  t.MembersCount,
+				ActiveMembers: t.ActiveMembers,
+			})
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.Error("failed to encode response", slog.String("error", err.Error()))
+		}
+	}
+}
+
+func parseFilter(q url.Values) (domains.TeamFilter, error) {
+	var (
+		filter domains.TeamFilter
+		err    error
+	)
+
+	if filter.Limit, err = pagination.Limit(q.Get("limit")); err != nil {
+		return filter, err
+	}
+	if filter.Desc, err = pagination.Desc(q.Get("order")); err != nil {
+		return filter, err
+	}
+	if filter.HasActive, err = pagination.Bool("active", q.Get("active")); err != nil {
+		return filter, err
+	}
+	if filter.After, err = pagination.DecodeCursor(q.Get("cursor")); err != nil {
+		return filter, err
+	}
+	filter.NamePrefix = q.Get("name_prefix")
+
+	return filter, nil
+}
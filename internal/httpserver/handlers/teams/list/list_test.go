@@ -0,0 +1,114 @@
+package list_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/teams/list"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/teams/list/mocks"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/pagination"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestListTeamsHandler(t *testing.T) {
+	inactive := false
+
+	type testCase struct {
+		name           string
+		query          string
+		expectedFilter *domains.TeamFilter
+		mockPage       *domains.TeamPage
+		mockError      error
+		expectedStatus int
+		expectedErr    string
+	}
+
+	cases := []testCase{
+		{
+			name:           "Defaults",
+			expectedFilter: &domains.TeamFilter{Limit: pagination.DefaultLimit},
+			mockPage: &domains.TeamPage{
+				Teams: []*domains.TeamSummary{{Name: "backend", MembersCount: 2, ActiveMembers: 1}},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "Filters",
+			query: "?active=false&name_prefix=ba&order=desc&limit=10",
+			expectedFilter: &domains.TeamFilter{
+				HasActive:  &inactive,
+				NamePrefix: "ba",
+				Desc:       true,
+				Limit:      10,
+			},
+			mockPage:       &domains.TeamPage{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid order",
+			query:          "?order=up",
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "order must be one of: asc, desc",
+		},
+		{
+			name:           "Service error",
+			expectedFilter: &domains.TeamFilter{Limit: pagination.DefaultLimit},
+			mockError:      errors.New("db down"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedErr:    "internal server error",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := mocks.NewTeamService(t)
+
+			if tc.expectedFilter != nil {
+				svc.On("ListTeams", mock.Anything, *tc.expectedFilter).
+					Return(tc.mockPage, tc.mockError).
+					Once()
+			}
+
+			handler := list.New(discardLogger(), svc)
+
+			req := httptest.NewRequest(http.MethodGet, "/team/list"+tc.query, nil)
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+
+			if tc.expectedErr != "" {
+				var resp map[string]any
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Equal(t, tc.expectedErr, resp["error"].(map[string]any)["message"])
+				return
+			}
+
+			var resp list.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Len(t, resp.Teams, len(tc.mockPage.Teams))
+			for i, team := range tc.mockPage.Teams {
+				require.Equal(t, team.Name, resp.Teams[i].TeamName)
+				require.Equal(t, team.MembersCount, resp.Teams[i].MembersCount)
+				require.Equal(t, team.ActiveMembers, resp.Teams[i].ActiveMembers)
+			}
+			require.Empty(t, resp.NextCursor)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domains "github.com/Deymos01/pr-review-manager/internal/domains"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TeamService is an autogenerated mock type for the TeamService type
+type TeamService struct {
+	mock.Mock
+}
+
+// ListTeams provides a mock function with given fields: ctx, filter
+func (_m *TeamService) ListTeams(ctx context.Context, filter domains.TeamFilter) (*domains.TeamPage, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTeams")
+	}
+
+	var r0 *domains.TeamPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domains.TeamFilter) (*domains.TeamPage, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domains.TeamFilter) *domains.TeamPage); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.TeamPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domains.TeamFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTeamService creates a new instance of TeamService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTeamService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TeamService {
+	mock := &TeamService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -3,27 +3,38 @@ package get_review
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/Deymos01/pr-review-manager/internal/domains"
 	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/pagination"
 	"github.com/Deymos01/pr-review-manager/internal/lib/api/response"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@v2.53.5 --name=UserService
 type UserService interface {
-	GetUsersReview(ctx context.Context, userID string) ([]*domains.PullRequest, error)
+	GetUsersReview(ctx context.Context, userID string, filter domains.ReviewFilter) (*domains.ReviewPage, error)
+}
+
+type ReviewResponse struct {
+	PrID       string     `json:"pull_request_id"`
+	PrName     string     `json:"pull_request_name"`
+	AuthorID   string     `json:"author_id"`
+	Status     string     `json:"status"`
+	AssignedAt time.Time  `json:"assigned_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	MergedAt   *time.Time `json:"merged_at"`
 }
 
 type Response struct {
-	UserID string `json:"user_id"`
-	PRs    []struct {
-		PrID     string `json:"pull_request_id"`
-		PrName   string `json:"pull_request_name"`
-		AuthorID string `json:"author_id"`
-		Status   string `json:"status"`
-	} `json:"pull_requests"`
+	UserID     string           `json:"user_id"`
+	PRs        []ReviewResponse `json:"pull_requests"`
+	NextCursor string           `json:"next_cursor,omitempty"`
 }
 
 func New(
@@ -36,8 +47,25 @@ func New(
 
 		userID := r.URL.Query().Get("user_id")
 
-		reviews, err := userService.GetUsersReview(r.Context(), userID)
+		filter, err := parseFilter(r.URL.Query())
+		if err != nil {
+			log.Warn("invalid review filter", slog.Any("error", err))
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).
+				Encode(response.NewErrorResponse(handlers.InvalidRequest, err.Error()))
+			return
+		}
+
+		page, err := userService.GetUsersReview(r.Context(), userID, filter)
 		if err != nil {
+			if errors.Is(err, usecase.ErrInvalidCursor) {
+				log.Warn("invalid review cursor", slog.Any("error", err))
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.InvalidRequest, "invalid cursor"))
+				return
+			}
+
 			log.Warn("failed to get user reviews", slog.Any("error", err))
 			w.WriteHeader(http.StatusNotFound)
 			_ = json.NewEncoder(w).
@@ -45,19 +73,22 @@ func New(
 			return
 		}
 
-		var resp Response
-		resp.UserID = userID
-		resp.PRs = make([]struct {
-			PrID     string `json:"pull_request_id"`
-			PrName   string `json:"pull_request_name"`
-			AuthorID string `json:"author_id"`
-			Status   string `json:"status"`
-		}, len(reviews))
-		for i, pr := range reviews {
-			resp.PRs[i].PrID = pr.ID
-			resp.PRs[i].PrName = pr.Name
-			resp.PRs[i].AuthorID = pr.Author.ID
-			resp.PRs[i].Status = pr.Status
+		resp := Response{
+			UserID:     userID,
+			PRs:        make([]ReviewResponse, len(page.Reviews)),
+			NextCursor: pagination.EncodeCursor(page.Next),
+		}
+		for i, review := range page.Reviews {
+			pr := review.PullRequest
+			resp.PRs[i] = ReviewResponse{
+				PrID:       pr.ID,
+				PrName:     pr.Name,
+				AuthorID:   pr.Author.ID,
+				Status:     string(pr.Status),
+				AssignedAt: review.AssignedAt,
+				CreatedAt:  pr.CreatedAt,
+				MergedAt:   pr.MergedAt,
+			}
 		}
 
 		w.WriteHeader(http.StatusOK)
@@ -66,3 +97,40 @@ func New(
 		}
 	}
 }
+
+func parseFilter(q url.Values) (domains.ReviewFilter, error) {
+	var (
+		filter domains.ReviewFilter
+		err    error
+	)
+
+	if filter.Limit, err = pagination.Limit(q.Get("limit")); err != nil {
+		return filter, err
+	}
+	if filter.Desc, err = pagination.Desc(q.Get("order")); err != nil {
+		return filter, err
+	}
+	if filter.After, err = pagination.DecodeCursor(q.Get("cursor")); err != nil {
+		return filter, err
+	}
+
+	if status := domains.PRStatus(q.Get("status")); status != "" {
+		if !status.Valid() {
+			return filter, errors.New("status must be one of: OPEN, MERGED")
+		}
+		filter.Status = status
+	}
+
+	if raw := q.Get("since"); raw != "" {
+		since, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return filter, errors.New("since must be an RFC3339 timestamp")
+		}
+		since = since.UTC()
+		filter.Since = &since
+	}
+
+	filter.AuthorID = q.Get("author_id")
+
+	return filter, nil
+}
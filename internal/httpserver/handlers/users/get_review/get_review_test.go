@@ -8,10 +8,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/Deymos01/pr-review-manager/internal/domains"
 	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/users/get_review"
 	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/users/get_review/mocks"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/pagination"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -21,10 +24,19 @@ func discardLogger() *slog.Logger {
 }
 
 func TestGetReviewHandler(t *testing.T) {
+	createdAt := time.Date(2025, 10, 20, 9, 0, 0, 0, time.UTC)
+	assignedAt := createdAt.Add(time.Minute)
+	since := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	next := &domains.Cursor{Key: assignedAt.Format(time.RFC3339Nano), ID: "pr1"}
+
+	defaultFilter := domains.ReviewFilter{Limit: pagination.DefaultLimit}
+
 	type testCase struct {
 		name           string
+		query          string
 		userID         string
-		mockReturnPRs  []*domains.PullRequest
+		expectedFilter *domains.ReviewFilter
+		mockPage       *domains.ReviewPage
 		mockError      error
 		expectedStatus int
 		expectedErr    string
@@ -32,21 +44,67 @@ func TestGetReviewHandler(t *testing.T) {
 
 	cases := []testCase{
 		{
-			name:   "Success (one PR)",
-			userID: "u1",
-			mockReturnPRs: []*domains.PullRequest{
-				{
-					ID:     "pr1",
-					Name:   "Implement feature",
-					Status: "OPEN",
-					Author: &domains.User{ID: "author1"},
+			name:           "Success (one PR)",
+			userID:         "u1",
+			expectedFilter: &defaultFilter,
+			mockPage: &domains.ReviewPage{
+				Reviews: []*domains.Review{
+					{
+						PullRequest: &domains.PullRequest{
+							ID:        "pr1",
+							Name:      "Implement feature",
+							Status:    "OPEN",
+							Author:    &domains.User{ID: "author1"},
+							CreatedAt: createdAt,
+						},
+						AssignedAt: assignedAt,
+					},
 				},
+				Next: next,
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Filters",
+			userID: "u1",
+			query:  "&status=MERGED&since=2025-10-01T00:00:00Z&author_id=author1&order=desc&limit=5&cursor=" + pagination.EncodeCursor(next),
+			expectedFilter: &domains.ReviewFilter{
+				Status:   "MERGED",
+				Since:    &since,
+				AuthorID: "author1",
+				Desc:     true,
+				After:    next,
+				Limit:    5,
 			},
+			mockPage:       &domains.ReviewPage{},
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name:           "Invalid status",
+			userID:         "u1",
+			query:          "&status=CLOSED",
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "status must be one of: OPEN, MERGED",
+		},
+		{
+			name:           "Invalid since",
+			userID:         "u1",
+			query:          "&since=yesterday",
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "since must be an RFC3339 timestamp",
+		},
+		{
+			name:           "Stale cursor",
+			userID:         "u1",
+			expectedFilter: &defaultFilter,
+			mockError:      usecase.ErrInvalidCursor,
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "invalid cursor",
+		},
 		{
 			name:           "User not found",
 			userID:         "missing",
+			expectedFilter: &defaultFilter,
 			mockError:      errors.New("not found"),
 			expectedStatus: http.StatusNotFound,
 			expectedErr:    "resource not found",
@@ -54,6 +112,7 @@ func TestGetReviewHandler(t *testing.T) {
 		{
 			name:           "Empty user_id",
 			userID:         "",
+			expectedFilter: &defaultFilter,
 			mockError:      errors.New("empty"),
 			expectedStatus: http.StatusNotFound,
 			expectedErr:    "resource not found",
@@ -68,17 +127,20 @@ func TestGetReviewHandler(t *testing.T) {
 
 			svc := mocks.NewUserService(t)
 
-			svc.On(
-				"GetUsersReview",
-				mock.Anything,
-				tc.userID,
-			).Return(tc.mockReturnPRs, tc.mockError).Once()
+			if tc.expectedFilter != nil {
+				svc.On(
+					"GetUsersReview",
+					mock.Anything,
+					tc.userID,
+					*tc.expectedFilter,
+				).Return(tc.mockPage, tc.mockError).Once()
+			}
 
 			handler := get_review.New(discardLogger(), svc)
 
 			req := httptest.NewRequest(
 				http.MethodGet,
-				"/users/review?user_id="+tc.userID,
+				"/users/review?user_id="+tc.userID+tc.query,
 				nil,
 			)
 
@@ -100,14 +162,24 @@ func TestGetReviewHandler(t *testing.T) {
 
 			prs, ok := resp["pull_requests"].([]interface{})
 			require.True(t, ok, "pull_requests must be an array")
-			require.Len(t, prs, len(tc.mockReturnPRs))
+			require.Len(t, prs, len(tc.mockPage.Reviews))
 
-			first := prs[0].(map[string]interface{})
+			if len(prs) == 0 {
+				require.NotContains(t, resp, "next_cursor")
+				return
+			}
 
-			require.Equal(t, tc.mockReturnPRs[0].ID, first["pull_request_id"])
-			require.Equal(t, tc.mockReturnPRs[0].Name, first["pull_request_name"])
-			require.Equal(t, tc.mockReturnPRs[0].Author.ID, first["author_id"])
-			require.Equal(t, tc.mockReturnPRs[0].Status, first["status"])
+			first := prs[0].(map[string]interface{})
+			review := tc.mockPage.Reviews[0]
+
+			require.Equal(t, review.PullRequest.ID, first["pull_request_id"])
+			require.Equal(t, review.PullRequest.Name, first["pull_request_name"])
+			require.Equal(t, review.PullRequest.Author.ID, first["author_id"])
+			require.Equal(t, string(review.PullRequest.Status), first["status"])
+			require.Equal(t, "2025-10-20T09:01:00Z", first["assigned_at"])
+			require.Equal(t, "2025-10-20T09:00:00Z", first["created_at"])
+			require.Nil(t, first["merged_at"])
+			require.Equal(t, pagination.EncodeCursor(next), resp["next_cursor"])
 		})
 	}
 }
@@ -15,29 +15,29 @@ type UserService struct {
 	mock.Mock
 }
 
-// GetUsersReview provides a mock function with given fields: ctx, userID
-func (_m *UserService) GetUsersReview(ctx context.Context, userID string) ([]*domains.PullRequest, error) {
-	ret := _m.Called(ctx, userID)
+// GetUsersReview provides a mock function with given fields: ctx, userID, filter
+func (_m *UserService) GetUsersReview(ctx context.Context, userID string, filter domains.ReviewFilter) (*domains.ReviewPage, error) {
+	ret := _m.Called(ctx, userID, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUsersReview")
 	}
 
-	var r0 []*domains.PullRequest
+	var r0 *domains.ReviewPage
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*domains.PullRequest, error)); ok {
-		return rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, domains.ReviewFilter) (*domains.ReviewPage, error)); ok {
+		return rf(ctx, userID, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []*domains.PullRequest); ok {
-		r0 = rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, domains.ReviewFilter) *domains.ReviewPage); ok {
+		r0 = rf(ctx, userID, filter)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*domains.PullRequest)
+			r0 = ret.Get(0).(*domains.ReviewPage)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, userID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, domains.ReviewFilter) error); ok {
+		r1 = rf(ctx, userID, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,60 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	user "github.com/Deymos01/pr-review-manager/internal/usecase/user"
+)
+
+// UserService is an autogenerated mock type for the UserService type
+type UserService struct {
+	mock.Mock
+}
+
+// OffboardUser provides a mock function with given fields: ctx, userID, newAuthorID
+func (_m *UserService) OffboardUser(ctx context.Context, userID string, newAuthorID string) (*user.OffboardResult, error) {
+	ret := _m.Called(ctx, userID, newAuthorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OffboardUser")
+	}
+
+	var r0 *user.OffboardResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*user.OffboardResult, error)); ok {
+		return rf(ctx, userID, newAuthorID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *user.OffboardResult); ok {
+		r0 = rf(ctx, userID, newAuthorID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*user.OffboardResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, newAuthorID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewUserService creates a new instance of UserService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserService {
+	mock := &UserService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
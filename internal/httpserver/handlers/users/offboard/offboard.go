@@ -0,0 +1,108 @@
+package offboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/response"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
+	"github.com/Deymos01/pr-review-manager/internal/usecase/user"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.53.5 --name=UserService
+type UserService interface {
+	OffboardUser(ctx context.Context, userID, newAuthorID string) (*user.OffboardResult, error)
+}
+
+type Request struct {
+	UserID string `json:"user_id"`
+	// NewAuthorID overrides who takes over the open pull requests authored by the user
+	NewAuthorID string `json:"new_author_id"`
+}
+
+type ReassignedPR struct {
+	PrID      string `json:"pull_request_id"`
+	OldUserID string `json:"old_reviewer_id"`
+	NewUserID string `json:"replaced_by"`
+}
+
+type Response struct {
+	User struct {
+		UserID    string     `json:"user_id"`
+		Username  string     `json:"username"`
+		IsActive  bool       `json:"is_active"`
+		DeletedAt *time.Time `json:"deleted_at"`
+	} `json:"user"`
+	NewAuthorID    string         `json:"new_author_id,omitempty"`
+	TransferredPRs []string       `json:"transferred_pull_requests"`
+	PRs            []ReassignedPR `json:"pull_requests"`
+}
+
+func New(
+	log *slog.Logger,
+	userService UserService,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.users.offboard.New"
+		log = log.With(slog.String("op", op))
+
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			log.Warn("invalid request body", slog.Any("error", err))
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).
+				Encode(response.NewErrorResponse(handlers.InvalidRequest, "invalid JSON format"))
+			return
+		}
+
+		result, err := userService.OffboardUser(r.Context(), req.UserID, req.NewAuthorID)
+		if err != nil {
+			log.Warn("failed to offboard user", slog.Any("error", err))
+
+			switch {
+			case errors.Is(err, usecase.ErrUserNotFound):
+				w.WriteHeader(http.StatusNotFound)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.NotFound, "resource not found"))
+			case errors.Is(err, usecase.ErrNoNewAuthor):
+				w.WriteHeader(http.StatusConflict)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.NoCandidate, "no active user to take over pull requests"))
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.InternalError, "internal server error"))
+			}
+			return
+		}
+
+		var resp Response
+		resp.User.UserID = result.User.ID
+		resp.User.Username = result.User.Name
+		resp.User.IsActive = result.User.IsActive
+		resp.User.DeletedAt = result.User.DeletedAt
+		resp.NewAuthorID = result.NewAuthorID
+
+		resp.TransferredPRs = make([]string, 0, len(result.TransferredPRs))
+		resp.TransferredPRs = append(resp.TransferredPRs, result.TransferredPRs...)
+
+		resp.PRs = make([]ReassignedPR, 0, len(result.ReassignedPRs))
+		for _, r := range result.ReassignedPRs {
+			resp.PRs = append(resp.PRs, ReassignedPR{
+				PrID:      r.PrID,
+				OldUserID: r.OldUserID,
+				NewUserID: r.NewUserID,
+			})
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.Error("failed to encode response", slog.Any("error", err))
+		}
+	}
+}
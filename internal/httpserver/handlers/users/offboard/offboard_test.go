@@ -0,0 +1,163 @@
+package offboard_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/users/offboard"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/users/offboard/mocks"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
+	"github.com/Deymos01/pr-review-manager/internal/usecase/user"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestOffboardHandler(t *testing.T) {
+	deletedAt := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	type testCase struct {
+		name           string
+		body           any
+		mockResult     *user.OffboardResult
+		mockError      error
+		expectedStatus int
+		expectedErr    string
+	}
+
+	cases := []testCase{
+		{
+			name: "Success",
+			body: offboard.Request{UserID: "u1"},
+			mockResult: &user.OffboardResult{
+				User: &domains.User{
+					ID:        "u1",
+					Name:      domains.DeletedUserName,
+					DeletedAt: &deletedAt,
+				},
+				NewAuthorID:    "u2",
+				TransferredPRs: []string{"pr1"},
+				ReassignedPRs: []*domains.ReassignedPR{
+					{PrID: "pr2", OldUserID: "u1", NewUserID: "u3"},
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Nothing to hand over",
+			body: offboard.Request{UserID: "u1", NewAuthorID: "u2"},
+			mockResult: &user.OffboardResult{
+				User: &domains.User{
+					ID:        "u1",
+					Name:      domains.DeletedUserName,
+					DeletedAt: &deletedAt,
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid JSON",
+			body:           `{"user_id": 123}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "invalid JSON format",
+		},
+		{
+			name:           "User not found",
+			body:           offboard.Request{UserID: "missing"},
+			mockError:      usecase.ErrUserNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedErr:    "resource not found",
+		},
+		{
+			name:           "No new author",
+			body:           offboard.Request{UserID: "u1"},
+			mockError:      usecase.ErrNoNewAuthor,
+			expectedStatus: http.StatusConflict,
+			expectedErr:    "no active user to take over pull requests",
+		},
+		{
+			name:           "Unknown error",
+			body:           offboard.Request{UserID: "u1"},
+			mockError:      errors.New("unexpected"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedErr:    "internal server error",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := mocks.NewUserService(t)
+
+			var buf bytes.Buffer
+			switch v := tc.body.(type) {
+			case string:
+				buf.WriteString(v)
+			default:
+				require.NoError(t, json.NewEncoder(&buf).Encode(v))
+			}
+
+			if req, ok := tc.body.(offboard.Request); ok {
+				svc.On("OffboardUser", mock.Anything, req.UserID, req.NewAuthorID).
+					Return(tc.mockResult, tc.mockError).Once()
+			}
+
+			handler := offboard.New(discardLogger(), svc)
+
+			req := httptest.NewRequest(http.MethodPost, "/users/offboard", &buf)
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			if tc.expectedErr != "" {
+				errResp := resp["error"].(map[string]any)
+				require.Equal(t, tc.expectedErr, errResp["message"])
+				return
+			}
+
+			u := resp["user"].(map[string]any)
+			require.Equal(t, tc.mockResult.User.ID, u["user_id"])
+			require.Equal(t, domains.DeletedUserName, u["username"])
+			require.Equal(t, false, u["is_active"])
+			require.Equal(t, deletedAt.Format(time.RFC3339), u["deleted_at"])
+
+			if tc.mockResult.NewAuthorID != "" {
+				require.Equal(t, tc.mockResult.NewAuthorID, resp["new_author_id"])
+			} else {
+				require.NotContains(t, resp, "new_author_id")
+			}
+
+			transferred := resp["transferred_pull_requests"].([]any)
+			require.Len(t, transferred, len(tc.mockResult.TransferredPRs))
+			for i, id := range transferred {
+				require.Equal(t, tc.mockResult.TransferredPRs[i], id)
+			}
+
+			prs := resp["pull_requests"].([]any)
+			require.Len(t, prs, len(tc.mockResult.ReassignedPRs))
+			for i, pr := range prs {
+				require.Equal(t, tc.mockResult.ReassignedPRs[i].PrID, pr.(map[string]any)["pull_request_id"])
+				require.Equal(t, tc.mockResult.ReassignedPRs[i].NewUserID, pr.(map[string]any)["replaced_by"])
+			}
+		})
+	}
+}
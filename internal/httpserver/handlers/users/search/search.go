@@ -0,0 +1,117 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/pagination"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/response"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.53.5 --name=UserService
+type UserService interface {
+	SearchUsers(ctx context.Context, filter domains.UserFilter) (*domains.UserPage, error)
+}
+
+type UserResponse struct {
+	UserID   string  `json:"user_id"`
+	Username string  `json:"username"`
+	TeamName *string `json:"team_name"`
+	IsActive bool    `json:"is_active"`
+	Role     string  `json:"role"`
+}
+
+type Response struct {
+	Users      []UserResponse `json:"users"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+func New(
+	log *slog.Logger,
+	service UserService,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.users.search.New"
+		log = log.With(slog.String("op", op))
+
+		filter, err := parseFilter(r.URL.Query())
+		if err != nil {
+			log.Warn("invalid search parameters", slog.Any("error", err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).
+				Encode(response.NewErrorResponse(handlers.InvalidRequest, err.Error()))
+			return
+		}
+
+		page, err := service.SearchUsers(r.Context(), filter)
+		if err != nil {
+			log.Error("failed to search users", slog.Any("error", err))
+
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).
+				Encode(response.NewErrorResponse(handlers.InternalError, "internal server error"))
+			return
+		}
+
+		resp := Response{
+			Users:      make([]UserResponse, 0, len(page.Users)),
+			NextCursor: pagination.EncodeCursor(page.Next),
+		}
+		for _, u := range page.Users {
+			resp.Users = append(resp.Users, UserResponse{
+				UserID:   u.ID,
+				Username: u.Name,
+				TeamName: u.TeamName,
+				IsActive: u.IsActive,
+				Role:     string(u.Role),
+			})
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.Error("failed to encode response", slog.Any("error", err))
+		}
+	}
+}
+
+func parseFilter(q url.Values) (domains.UserFilter, error) {
+	var (
+		filter domains.UserFilter
+		err    error
+	)
+
+	if filter.Limit, err = pagination.Limit(q.Get("limit")); err != nil {
+		return filter, err
+	}
+	if filter.Desc, err = pagination.Desc(q.Get("order")); err != nil {
+		return filter, err
+	}
+	if filter.IsActive, err = pagination.Bool("active", q.Get("active")); err != nil {
+		return filter, err
+	}
+	if filter.After, err = pagination.DecodeCursor(q.Get("cursor")); err != nil {
+		return filter, err
+	}
+
+	filter.SortBy = domains.UserSort(q.Get("sort"))
+	if filter.SortBy == "" {
+		filter.SortBy = domains.UserSortByID
+	}
+	if !filter.SortBy.Valid() {
+		return filter, errors.New("sort must be one of: id, name")
+	}
+
+	if team := q.Get("team_name"); team != "" {
+		filter.TeamName = &team
+	}
+	filter.NamePrefix = q.Get("name_prefix")
+
+	return filter, nil
+}
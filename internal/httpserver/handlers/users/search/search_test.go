@@ -0,0 +1,143 @@
+package search_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/users/search"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/users/search/mocks"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/pagination"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestSearchHandler(t *testing.T) {
+	active := true
+	team := "backend"
+	cursor := &domains.Cursor{Key: "Alice", ID: "u1"}
+
+	type testCase struct {
+		name           string
+		query          string
+		expectedFilter *domains.UserFilter
+		mockPage       *domains.UserPage
+		mockError      error
+		expectedStatus int
+		expectedErr    string
+		expectedNext   string
+	}
+
+	cases := []testCase{
+		{
+			name:  "Defaults",
+			query: "",
+			expectedFilter: &domains.UserFilter{
+				SortBy: domains.UserSortByID,
+				Limit:  pagination.DefaultLimit,
+			},
+			mockPage:       &domains.UserPage{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "All filters",
+			query: "?active=true&team_name=backend&name_prefix=al&sort=name&order=desc&limit=1&cursor=" + pagination.EncodeCursor(cursor),
+			expectedFilter: &domains.UserFilter{
+				IsActive:   &active,
+				TeamName:   &team,
+				NamePrefix: "al",
+				SortBy:     domains.UserSortByName,
+				Desc:       true,
+				After:      cursor,
+				Limit:      1,
+			},
+			mockPage: &domains.UserPage{
+				Users: []*domains.User{{ID: "u0", Name: "Al", TeamName: &team, IsActive: true, Role: domains.RoleMember}},
+				Next:  &domains.Cursor{Key: "Al", ID: "u0"},
+			},
+			expectedStatus: http.StatusOK,
+			expectedNext:   pagination.EncodeCursor(&domains.Cursor{Key: "Al", ID: "u0"}),
+		},
+		{
+			name:           "Invalid limit",
+			query:          "?limit=1000",
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "limit must be an integer between 1 and 100",
+		},
+		{
+			name:           "Invalid sort",
+			query:          "?sort=role",
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "sort must be one of: id, name",
+		},
+		{
+			name:           "Invalid active",
+			query:          "?active=maybe",
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "active must be a boolean",
+		},
+		{
+			name:           "Invalid cursor",
+			query:          "?cursor=bm90LWpzb24",
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "invalid cursor",
+		},
+		{
+			name:  "Service error",
+			query: "",
+			expectedFilter: &domains.UserFilter{
+				SortBy: domains.UserSortByID,
+				Limit:  pagination.DefaultLimit,
+			},
+			mockError:      errors.New("db down"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedErr:    "internal server error",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := mocks.NewUserService(t)
+
+			if tc.expectedFilter != nil {
+				svc.On("SearchUsers", mock.Anything, *tc.expectedFilter).
+					Return(tc.mockPage, tc.mockError).
+					Once()
+			}
+
+			handler := search.New(discardLogger(), svc)
+
+			req := httptest.NewRequest(http.MethodGet, "/users/search"+tc.query, nil)
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+
+			if tc.expectedErr != "" {
+				var resp map[string]any
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Equal(t, tc.expectedErr, resp["error"].(map[string]any)["message"])
+				return
+			}
+
+			var resp search.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Len(t, resp.Users, len(tc.mockPage.Users))
+			require.Equal(t, tc.expectedNext, resp.NextCursor)
+		})
+	}
+}
@@ -14,34 +14,43 @@ type UserService struct {
 	mock.Mock
 }
 
-// SetUserIsActive provides a mock function with given fields: ctx, userID, isActive
-func (_m *UserService) SetUserIsActive(ctx context.Context, userID string, isActive bool) (*domains.User, error) {
-	ret := _m.Called(ctx, userID, isActive)
+// SetUserIsActive provides a mock function with given fields: ctx, userID, isActive, opts
+func (_m *UserService) SetUserIsActive(ctx context.Context, userID string, isActive bool, opts domains.StatusOptions) (*domains.User, []*domains.ReassignedPR, error) {
+	ret := _m.Called(ctx, userID, isActive, opts)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SetUserIsActive")
 	}
 
 	var r0 *domains.User
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, bool) (*domains.User, error)); ok {
-		return rf(ctx, userID, isActive)
+	var r1 []*domains.ReassignedPR
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool, domains.StatusOptions) (*domains.User, []*domains.ReassignedPR, error)); ok {
+		return rf(ctx, userID, isActive, opts)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, bool) *domains.User); ok {
-		r0 = rf(ctx, userID, isActive)
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool, domains.StatusOptions) *domains.User); ok {
+		r0 = rf(ctx, userID, isActive, opts)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domains.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, bool) error); ok {
-		r1 = rf(ctx, userID, isActive)
+	if rf, ok := ret.Get(1).(func(context.Context, string, bool, domains.StatusOptions) []*domains.ReassignedPR); ok {
+		r1 = rf(ctx, userID, isActive, opts)
 	} else {
-		r1 = ret.Error(1)
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]*domains.ReassignedPR)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, bool, domains.StatusOptions) error); ok {
+		r2 = rf(ctx, userID, isActive, opts)
+	} else {
+		r2 = ret.Error(2)
 	}
 
-	return r0, r1
+	return r0, r1, r2
 }
 
 // NewUserService creates a new instance of UserService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -3,22 +3,39 @@ package set_is_active
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 
 	"github.com/Deymos01/pr-review-manager/internal/domains"
 	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers"
 	"github.com/Deymos01/pr-review-manager/internal/lib/api/response"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@v2.53.5 --name=UserService
 type UserService interface {
-	SetUserIsActive(ctx context.Context, userID string, isActive bool) (*domains.User, error)
+	SetUserIsActive(
+		ctx context.Context,
+		userID string,
+		isActive bool,
+		opts domains.StatusOptions,
+	) (*domains.User, []*domains.ReassignedPR, error)
 }
 
 type Request struct {
 	UserID   string `json:"user_id"`
 	IsActive bool   `json:"is_active"`
+	// Rebalance moves a fair share of open reviews back to a reactivated user
+	Rebalance bool `json:"rebalance"`
+	// KeepReviews opts out of releasing the open reviews of a deactivated user
+	KeepReviews bool `json:"keep_reviews"`
+}
+
+type ReassignedPR struct {
+	PrID      string `json:"pull_request_id"`
+	OldUserID string `json:"old_reviewer_id"`
+	NewUserID string `json:"replaced_by"`
 }
 
 type Response struct {
@@ -28,6 +45,7 @@ type Response struct {
 		TeamName string `json:"team_name"`
 		IsActive bool   `json:"is_active"`
 	} `json:"user"`
+	PRs []ReassignedPR `json:"pull_requests"`
 }
 
 func New(
@@ -47,22 +65,43 @@ func New(
 			return
 		}
 
-		user, err := userService.SetUserIsActive(r.Context(), req.UserID, req.IsActive)
+		opts := domains.StatusOptions{
+			Rebalance:   req.Rebalance,
+			KeepReviews: req.KeepReviews,
+		}
+		updated, reassignedPRs, err := userService.SetUserIsActive(r.Context(), req.UserID, req.IsActive, opts)
 		if err != nil {
-			log.Warn("failed to set user is_active", slog.Any("error", err))
-			w.WriteHeader(http.StatusNotFound)
+			if errors.Is(err, usecase.ErrUserNotFound) {
+				log.Warn("user not found", slog.String("user_id", req.UserID))
+				w.WriteHeader(http.StatusNotFound)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.NotFound, "resource not found"))
+				return
+			}
+
+			log.Error("failed to set user is_active", slog.Any("error", err))
+			w.WriteHeader(http.StatusInternalServerError)
 			_ = json.NewEncoder(w).
-				Encode(response.NewErrorResponse(handlers.NotFound, "resource not found"))
+				Encode(response.NewErrorResponse(handlers.InternalError, "internal server error"))
 			return
 		}
 
 		var resp Response
-		resp.User.UserID = user.ID
-		resp.User.Username = user.Name
-		if user.TeamName != nil {
-			resp.User.TeamName = *user.TeamName
+		resp.User.UserID = updated.ID
+		resp.User.Username = updated.Name
+		if updated.TeamName != nil {
+			resp.User.TeamName = *updated.TeamName
+		}
+		resp.User.IsActive = updated.IsActive
+
+		resp.PRs = make([]ReassignedPR, 0, len(reassignedPRs))
+		for _, r := range reassignedPRs {
+			resp.PRs = append(resp.PRs, ReassignedPR{
+				PrID:      r.PrID,
+				OldUserID: r.OldUserID,
+				NewUserID: r.NewUserID,
+			})
 		}
-		resp.User.IsActive = user.IsActive
 
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
@@ -13,6 +13,7 @@ import (
 	"github.com/Deymos01/pr-review-manager/internal/domains"
 	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/users/set_is_active"
 	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/users/set_is_active/mocks"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -26,6 +27,7 @@ func TestSetIsActiveHandler(t *testing.T) {
 		name           string
 		body           any
 		mockUser       *domains.User
+		mockReassigned []*domains.ReassignedPR
 		mockError      error
 		expectedStatus int
 		expectedErr    string
@@ -46,6 +48,24 @@ func TestSetIsActiveHandler(t *testing.T) {
 			},
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name: "Reactivation with rebalance",
+			body: set_is_active.Request{
+				UserID:    "u1",
+				IsActive:  true,
+				Rebalance: true,
+			},
+			mockUser: &domains.User{
+				ID:       "u1",
+				Name:     "John",
+				TeamName: ptr("team"),
+				IsActive: true,
+			},
+			mockReassigned: []*domains.ReassignedPR{
+				{PrID: "pr1", OldUserID: "u2", NewUserID: "u1"},
+			},
+			expectedStatus: http.StatusOK,
+		},
 		{
 			name:           "Invalid JSON",
 			body:           `{"user_id": 123}`,
@@ -58,10 +78,21 @@ func TestSetIsActiveHandler(t *testing.T) {
 				UserID:   "missing",
 				IsActive: true,
 			},
-			mockError:      errors.New("not found"),
+			mockError:      usecase.ErrUserNotFound,
 			expectedStatus: http.StatusNotFound,
 			expectedErr:    "resource not found",
 		},
+		{
+			name: "Internal error",
+			body: set_is_active.Request{
+				UserID:    "u1",
+				IsActive:  true,
+				Rebalance: true,
+			},
+			mockError:      errors.New("rebalance failed"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedErr:    "internal server error",
+		},
 	}
 
 	for _, tc := range cases {
@@ -86,7 +117,8 @@ func TestSetIsActiveHandler(t *testing.T) {
 					mock.Anything,
 					req.UserID,
 					req.IsActive,
-				).Return(tc.mockUser, tc.mockError).Once()
+					domains.StatusOptions{Rebalance: req.Rebalance, KeepReviews: req.KeepReviews},
+				).Return(tc.mockUser, tc.mockReassigned, tc.mockError).Once()
 			}
 
 			handler := set_is_active.New(discardLogger(), svc)
@@ -117,6 +149,13 @@ func TestSetIsActiveHandler(t *testing.T) {
 			require.Equal(t, tc.mockUser.Name, user["username"])
 			require.Equal(t, *tc.mockUser.TeamName, user["team_name"])
 			require.Equal(t, tc.mockUser.IsActive, user["is_active"])
+
+			prs := resp["pull_requests"].([]any)
+			require.Len(t, prs, len(tc.mockReassigned))
+			for i, pr := range prs {
+				require.Equal(t, tc.mockReassigned[i].PrID, pr.(map[string]any)["pull_request_id"])
+				require.Equal(t, tc.mockReassigned[i].OldUserID, pr.(map[string]any)["old_reviewer_id"])
+			}
 		})
 	}
 }
@@ -0,0 +1,65 @@
+package stats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/response"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.53.5 --name=UserService
+type UserService interface {
+	GetReviewStats(ctx context.Context, userID string) (*domains.ReviewStats, error)
+}
+
+type Response struct {
+	UserID   string `json:"user_id"`
+	Authored int64  `json:"authored_pull_requests"`
+	Reviewed int64  `json:"reviewed_pull_requests"`
+}
+
+func New(
+	log *slog.Logger,
+	userService UserService,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.users.stats.New"
+		log = log.With(slog.String("op", op))
+
+		userID := r.URL.Query().Get("user_id")
+
+		stats, err := userService.GetReviewStats(r.Context(), userID)
+		if err != nil {
+			if errors.Is(err, usecase.ErrUserNotFound) {
+				log.Warn("user not found", slog.String("user_id", userID))
+				w.WriteHeader(http.StatusNotFound)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.NotFound, "resource not found"))
+				return
+			}
+
+			log.Error("failed to get review stats", slog.Any("error", err))
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).
+				Encode(response.NewErrorResponse(handlers.InternalError, "internal server error"))
+			return
+		}
+
+		resp := Response{
+			UserID:   stats.UserID,
+			Authored: stats.Authored,
+			Reviewed: stats.Reviewed,
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.Error("failed to encode response", slog.Any("error", err))
+		}
+	}
+}
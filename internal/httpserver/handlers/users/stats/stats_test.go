@@ -0,0 +1,85 @@
+package stats_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/users/stats"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers/users/stats/mocks"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestStatsHandler(t *testing.T) {
+	type testCase struct {
+		name           string
+		mockStats      *domains.ReviewStats
+		mockError      error
+		expectedStatus int
+		expectedErr    string
+	}
+
+	cases := []testCase{
+		{
+			name:           "Success",
+			mockStats:      &domains.ReviewStats{UserID: "u1", Authored: 4, Reviewed: 7},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "User not found",
+			mockError:      usecase.ErrUserNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedErr:    "resource not found",
+		},
+		{
+			name:           "Unknown error",
+			mockError:      errors.New("unexpected"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedErr:    "internal server error",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := mocks.NewUserService(t)
+			svc.On("GetReviewStats", mock.Anything, "u1").Return(tc.mockStats, tc.mockError).Once()
+
+			handler := stats.New(discardLogger(), svc)
+
+			req := httptest.NewRequest(http.MethodGet, "/users/stats?user_id=u1", nil)
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			if tc.expectedErr != "" {
+				errResp := resp["error"].(map[string]any)
+				require.Equal(t, tc.expectedErr, errResp["message"])
+				return
+			}
+
+			require.Equal(t, "u1", resp["user_id"])
+			require.Equal(t, float64(4), resp["authored_pull_requests"])
+			require.Equal(t, float64(7), resp["reviewed_pull_requests"])
+		})
+	}
+}
@@ -1,16 +1,24 @@
 package middlewares
 
-import "net/http"
+import (
+	"net/http"
 
-func AdminAuthMiddleware(adminToken string) func(http.Handler) http.Handler {
+	"github.com/Deymos01/pr-review-manager/internal/lib/tenant"
+)
+
+// AdminAuthMiddleware lets through requests carrying one of the admin tokens and makes
+// them act for the organization that owns the token.
+func AdminAuthMiddleware(adminTokens map[string]string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			token := r.Header.Get("X-Admin-Token")
-			if token == "" || token != adminToken {
+			org, ok := adminTokens[token]
+			if token == "" || !ok {
 				http.Error(w, "unauthorized", http.StatusUnauthorized)
 				return
 			}
-			next.ServeHTTP(w, r)
+			ctx := withAdminCaller(r.Context(), token)
+			next.ServeHTTP(w, r.WithContext(tenant.WithOrg(ctx, org)))
 		})
 	}
 }
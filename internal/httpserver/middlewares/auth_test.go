@@ -0,0 +1,196 @@
+package middlewares_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mw "github.com/Deymos01/pr-review-manager/internal/httpserver/middlewares"
+	"github.com/Deymos01/pr-review-manager/internal/lib/tenant"
+	"github.com/Deymos01/pr-review-manager/internal/lib/usertoken"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	adminTokens = map[string]string{
+		"root-token": tenant.Default,
+		"acme-token": "acme",
+	}
+	userTokenSecret = []byte("user-secret")
+)
+
+func TestAdminAuthMiddleware(t *testing.T) {
+	cases := []struct {
+		name           string
+		token          string
+		orgHeader      string
+		expectedStatus int
+		expectedOrg    string
+	}{
+		{
+			name:           "Default organization token",
+			token:          "root-token",
+			expectedStatus: http.StatusOK,
+			expectedOrg:    tenant.Default,
+		},
+		{
+			name:           "Tenant token",
+			token:          "acme-token",
+			expectedStatus: http.StatusOK,
+			expectedOrg:    "acme",
+		},
+		{
+			name:           "X-Org-Id does not override the token",
+			token:          "root-token",
+			orgHeader:      "acme",
+			expectedStatus: http.StatusOK,
+			expectedOrg:    tenant.Default,
+		},
+		{
+			name:           "Unknown token",
+			token:          "other-token",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "No token",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var org string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				org = tenant.Org(r.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/team/get", nil)
+			if tc.token != "" {
+				req.Header.Set("X-Admin-Token", tc.token)
+			}
+			if tc.orgHeader != "" {
+				req.Header.Set("X-Org-Id", tc.orgHeader)
+			}
+			rr := httptest.NewRecorder()
+
+			mw.AdminAuthMiddleware(adminTokens)(next).ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+			require.Equal(t, tc.expectedOrg, org)
+		})
+	}
+}
+
+func TestAdminOrUserAuthMiddleware(t *testing.T) {
+	validUntil := time.Now().Add(time.Hour)
+
+	cases := []struct {
+		name           string
+		token          string
+		userToken      string
+		userIDHeader   string
+		orgHeader      string
+		expectedStatus int
+		expectedOrg    string
+		expectedUserID string
+		expectedAdmin  bool
+	}{
+		{
+			name:           "Tenant admin",
+			token:          "acme-token",
+			expectedStatus: http.StatusOK,
+			expectedOrg:    "acme",
+			expectedAdmin:  true,
+		},
+		{
+			name:           "Signed user token",
+			userToken:      usertoken.Sign(userTokenSecret, tenant.Default, "u1", validUntil),
+			expectedStatus: http.StatusOK,
+			expectedOrg:    tenant.Default,
+			expectedUserID: "u1",
+		},
+		{
+			name:           "Forged X-User-Id without a token",
+			userIDHeader:   "u1",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "X-User-Id does not override the token",
+			userToken:      usertoken.Sign(userTokenSecret, tenant.Default, "u1", validUntil),
+			userIDHeader:   "lead",
+			expectedStatus: http.StatusOK,
+			expectedOrg:    tenant.Default,
+			expectedUserID: "u1",
+		},
+		{
+			name:           "X-Org-Id does not override the token",
+			userToken:      usertoken.Sign(userTokenSecret, tenant.Default, "u1", validUntil),
+			orgHeader:      "acme",
+			expectedStatus: http.StatusOK,
+			expectedOrg:    tenant.Default,
+			expectedUserID: "u1",
+		},
+		{
+			name:           "User token signed with another secret",
+			userToken:      usertoken.Sign([]byte("forged"), tenant.Default, "u1", validUntil),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Expired user token",
+			userToken:      usertoken.Sign(userTokenSecret, tenant.Default, "u1", time.Now().Add(-time.Minute)),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Unknown admin token",
+			token:          "other-token",
+			userToken:      usertoken.Sign(userTokenSecret, tenant.Default, "u1", validUntil),
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				org     string
+				userID  string
+				isAdmin bool
+			)
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				org = tenant.Org(r.Context())
+				userID = mw.UserID(r.Context())
+				isAdmin = mw.IsAdmin(r.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/team/deactivate", nil)
+			if tc.token != "" {
+				req.Header.Set("X-Admin-Token", tc.token)
+			}
+			if tc.userToken != "" {
+				req.Header.Set("X-User-Token", tc.userToken)
+			}
+			if tc.userIDHeader != "" {
+				req.Header.Set("X-User-Id", tc.userIDHeader)
+			}
+			if tc.orgHeader != "" {
+				req.Header.Set("X-Org-Id", tc.orgHeader)
+			}
+			rr := httptest.NewRecorder()
+
+			mw.AdminOrUserAuthMiddleware(adminTokens, userTokenSecret)(next).ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+			require.Equal(t, tc.expectedOrg, org)
+			require.Equal(t, tc.expectedUserID, userID)
+			require.Equal(t, tc.expectedAdmin, isAdmin)
+		})
+	}
+}
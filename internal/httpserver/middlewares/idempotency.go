@@ -0,0 +1,219 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/handlers"
+	"github.com/Deymos01/pr-review-manager/internal/lib/api/response"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+
+	completeAttempts = 3
+	completeBackoff  = 50 * time.Millisecond
+)
+
+// replayedHeaders are the response headers stored with an idempotent response, so that a
+// replay carries the same representation metadata as the original.
+var replayedHeaders = []string{"Content-Type", "Content-Disposition", "ETag", "Location"}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.53.5 --name=IdempotencyStore
+type IdempotencyStore interface {
+	ReserveIdempotencyKey(
+		ctx context.Context,
+		scope, key, requestHash string,
+		lease time.Duration,
+	) (*domains.IdempotencyRecord, error)
+	CompleteIdempotencyKey(
+		ctx context.Context,
+		scope, key string,
+		statusCode int,
+		header map[string]string,
+		body []byte,
+		ttl time.Duration,
+	) error
+	ReleaseIdempotencyKey(ctx context.Context, scope, key string) error
+}
+
+// IdempotencyMiddleware makes requests carrying an Idempotency-Key header safe to retry.
+// Keys are scoped to the caller, method and path. The first request with a key is executed
+// and its response is stored for ttl; identical retries get the stored status, headers and
+// body back, while reusing the key with a different body or If-Match header is rejected
+// with 422. Server errors are not stored, so such requests may be retried. While a request
+// runs it holds its key for lockTimeout; a retry arriving later takes the key over, so a
+// request lost with a crashed instance does not block its key until ttl passes. If the
+// response of a finished request cannot be stored, the key stays reserved until its lease
+// expires rather than being released, so a retry cannot run the request a second time.
+func IdempotencyMiddleware(
+	log *slog.Logger,
+	store IdempotencyStore,
+	ttl time.Duration,
+	lockTimeout time.Duration,
+) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			const op = "http.middlewares.IdempotencyMiddleware"
+			log := log.With(slog.String("op", op))
+
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+
+			if len(key) > maxIdempotencyKeyLength {
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.InvalidRequest, "Idempotency-Key is too long"))
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				log.Warn("failed to read request body", slog.Any("error", err))
+
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.InvalidRequest, "failed to read request body"))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			// keys are private to the caller, and a retry must carry the same precondition
+			scope := r.Method + " " + r.URL.Path
+			if c := caller(r.Context()); c != "" {
+				scope = c + " " + scope
+			}
+			hash := requestHash(r.Header.Get("If-Match"), body)
+
+			record, err := store.ReserveIdempotencyKey(r.Context(), scope, key, hash, lockTimeout)
+			if err != nil {
+				log.Error("failed to reserve idempotency key", slog.Any("error", err))
+
+				w.WriteHeader(http.StatusInternalServerError)
+				_ = json.NewEncoder(w).
+					Encode(response.NewErrorResponse(handlers.InternalError, "internal server error"))
+				return
+			}
+
+			if record != nil {
+				switch {
+				case record.RequestHash != hash:
+					log.Warn("idempotency key reused with different request", slog.String("key", key))
+
+					w.WriteHeader(http.StatusUnprocessableEntity)
+					_ = json.NewEncoder(w).Encode(response.NewErrorResponse(
+						handlers.IdempotencyKeyMismatch,
+						"Idempotency-Key was already used with a different request",
+					))
+				case !record.Completed:
+					log.Warn("idempotency key is still in progress", slog.String("key", key))
+
+					w.WriteHeader(http.StatusConflict)
+					_ = json.NewEncoder(w).Encode(response.NewErrorResponse(
+						handlers.IdempotencyKeyInProgress,
+						"request with this Idempotency-Key is still in progress",
+					))
+				default:
+					for name, value := range record.Header {
+						w.Header().Set(name, value)
+					}
+					w.Header().Set(IdempotentReplayedHeader, "true")
+					w.WriteHeader(record.StatusCode)
+					_, _ = w.Write(record.Body)
+				}
+
+				return
+			}
+
+			rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			// The outcome is persisted even if the client goes away, so that its retry is answered.
+			ctx := context.WithoutCancel(r.Context())
+			finished := false
+			defer func() {
+				if finished {
+					return
+				}
+				if err := store.ReleaseIdempotencyKey(ctx, scope, key); err != nil {
+					log.Error("failed to release idempotency key", slog.Any("error", err))
+				}
+			}()
+
+			next.ServeHTTP(rec, r)
+
+			if rec.status >= http.StatusInternalServerError {
+				return
+			}
+			// the handler has taken effect, so the key must not be released from here on
+			finished = true
+
+			header := make(map[string]string, len(replayedHeaders))
+			for _, name := range replayedHeaders {
+				if value := w.Header().Get(name); value != "" {
+					header[name] = value
+				}
+			}
+
+			for attempt := 1; ; attempt++ {
+				err := store.CompleteIdempotencyKey(ctx, scope, key, rec.status, header, rec.body.Bytes(), ttl)
+				if err == nil {
+					return
+				}
+				if attempt == completeAttempts {
+					log.Error("failed to store idempotent response, key stays reserved until its lease expires",
+						slog.String("key", key), slog.Any("error", err))
+					return
+				}
+
+				log.Warn("failed to store idempotent response, retrying",
+					slog.Int("attempt", attempt), slog.Any("error", err))
+				time.Sleep(completeBackoff * time.Duration(attempt))
+			}
+		})
+	}
+}
+
+func requestHash(ifMatch string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(ifMatch))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder passes the response through while keeping a copy for replays.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rr *responseRecorder) WriteHeader(status int) {
+	if !rr.wroteHeader {
+		rr.status = status
+		rr.wroteHeader = true
+	}
+	rr.ResponseWriter.WriteHeader(status)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	rr.wroteHeader = true
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
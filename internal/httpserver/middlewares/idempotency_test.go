@@ -0,0 +1,327 @@
+package middlewares_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	mw "github.com/Deymos01/pr-review-manager/internal/httpserver/middlewares"
+	"github.com/Deymos01/pr-review-manager/internal/httpserver/middlewares/mocks"
+	"github.com/Deymos01/pr-review-manager/internal/lib/usertoken"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func hashOf(ifMatch, body string) string {
+	sum := sha256.Sum256([]byte(ifMatch + "\x00" + body))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	const (
+		body  = `{"pull_request_id":"pr-1"}`
+		scope = "POST /pullRequest/merge"
+		key   = "key-1"
+		ttl   = time.Hour
+		lease = time.Minute
+	)
+
+	// only the replayed headers set by the handler are stored
+	storedHeader := map[string]string{"Content-Type": "application/json", "ETag": `"3"`}
+
+	type testCase struct {
+		name           string
+		key            string
+		ifMatch        string
+		handlerStatus  int
+		setup          func(store *mocks.IdempotencyStore)
+		expectedCalls  int
+		expectedStatus int
+		expectedBody   string
+		expectedHeader map[string]string
+		expectedErr    string
+		replayed       bool
+	}
+
+	cases := []testCase{
+		{
+			name:           "No key",
+			handlerStatus:  http.StatusOK,
+			setup:          func(store *mocks.IdempotencyStore) {},
+			expectedCalls:  1,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"ok":true}`,
+		},
+		{
+			name:          "First request is stored",
+			key:           key,
+			handlerStatus: http.StatusOK,
+			setup: func(store *mocks.IdempotencyStore) {
+				store.On("ReserveIdempotencyKey", mock.Anything, scope, key, hashOf("", body), lease).
+					Return(nil, nil).Once()
+				store.On("CompleteIdempotencyKey", mock.Anything, scope, key, http.StatusOK, storedHeader, []byte(`{"ok":true}`), ttl).
+					Return(nil).Once()
+			},
+			expectedCalls:  1,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"ok":true}`,
+		},
+		{
+			name:          "Client errors are stored",
+			key:           key,
+			handlerStatus: http.StatusNotFound,
+			setup: func(store *mocks.IdempotencyStore) {
+				store.On("ReserveIdempotencyKey", mock.Anything, scope, key, hashOf("", body), lease).
+					Return(nil, nil).Once()
+				store.On("CompleteIdempotencyKey", mock.Anything, scope, key, http.StatusNotFound, storedHeader, []byte(`{"ok":true}`), ttl).
+					Return(nil).Once()
+			},
+			expectedCalls:  1,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"ok":true}`,
+		},
+		{
+			name:          "Failed completion is retried",
+			key:           key,
+			handlerStatus: http.StatusOK,
+			setup: func(store *mocks.IdempotencyStore) {
+				store.On("ReserveIdempotencyKey", mock.Anything, scope, key, hashOf("", body), lease).
+					Return(nil, nil).Once()
+				store.On("CompleteIdempotencyKey", mock.Anything, scope, key, http.StatusOK, storedHeader, []byte(`{"ok":true}`), ttl).
+					Return(errors.New("db down")).Once()
+				store.On("CompleteIdempotencyKey", mock.Anything, scope, key, http.StatusOK, storedHeader, []byte(`{"ok":true}`), ttl).
+					Return(nil).Once()
+			},
+			expectedCalls:  1,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"ok":true}`,
+		},
+		{
+			// releasing the key would let a retry run the request again
+			name:          "Failed completion keeps key reserved",
+			key:           key,
+			handlerStatus: http.StatusOK,
+			setup: func(store *mocks.IdempotencyStore) {
+				store.On("ReserveIdempotencyKey", mock.Anything, scope, key, hashOf("", body), lease).
+					Return(nil, nil).Once()
+				store.On("CompleteIdempotencyKey", mock.Anything, scope, key, http.StatusOK, storedHeader, []byte(`{"ok":true}`), ttl).
+					Return(errors.New("db down")).Times(3)
+			},
+			expectedCalls:  1,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"ok":true}`,
+		},
+		{
+			name:          "Server error releases key",
+			key:           key,
+			handlerStatus: http.StatusInternalServerError,
+			setup: func(store *mocks.IdempotencyStore) {
+				store.On("ReserveIdempotencyKey", mock.Anything, scope, key, hashOf("", body), lease).
+					Return(nil, nil).Once()
+				store.On("ReleaseIdempotencyKey", mock.Anything, scope, key).
+					Return(nil).Once()
+			},
+			expectedCalls:  1,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"ok":true}`,
+		},
+		{
+			name: "Replay",
+			key:  key,
+			setup: func(store *mocks.IdempotencyStore) {
+				store.On("ReserveIdempotencyKey", mock.Anything, scope, key, hashOf("", body), lease).
+					Return(&domains.IdempotencyRecord{
+						RequestHash: hashOf("", body),
+						Completed:   true,
+						StatusCode:  http.StatusCreated,
+						Header:      map[string]string{"Content-Type": "text/csv", "ETag": `"7"`},
+						Body:        []byte(`{"stored":true}`),
+					}, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"stored":true}`,
+			expectedHeader: map[string]string{"Content-Type": "text/csv", "ETag": `"7"`},
+			replayed:       true,
+		},
+		{
+			name: "Different request with same key",
+			key:  key,
+			setup: func(store *mocks.IdempotencyStore) {
+				store.On("ReserveIdempotencyKey", mock.Anything, scope, key, hashOf("", body), lease).
+					Return(&domains.IdempotencyRecord{
+						RequestHash: hashOf("", `{"pull_request_id":"pr-2"}`),
+						Completed:   true,
+						StatusCode:  http.StatusOK,
+					}, nil).Once()
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedErr:    "Idempotency-Key was already used with a different request",
+		},
+		{
+			name:    "Different If-Match with same key",
+			key:     key,
+			ifMatch: `"2"`,
+			setup: func(store *mocks.IdempotencyStore) {
+				store.On("ReserveIdempotencyKey", mock.Anything, scope, key, hashOf(`"2"`, body), lease).
+					Return(&domains.IdempotencyRecord{
+						RequestHash: hashOf(`"1"`, body),
+						Completed:   true,
+						StatusCode:  http.StatusOK,
+					}, nil).Once()
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedErr:    "Idempotency-Key was already used with a different request",
+		},
+		{
+			name: "Request in progress",
+			key:  key,
+			setup: func(store *mocks.IdempotencyStore) {
+				store.On("ReserveIdempotencyKey", mock.Anything, scope, key, hashOf("", body), lease).
+					Return(&domains.IdempotencyRecord{RequestHash: hashOf("", body)}, nil).Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedErr:    "request with this Idempotency-Key is still in progress",
+		},
+		{
+			name: "Store failure",
+			key:  key,
+			setup: func(store *mocks.IdempotencyStore) {
+				store.On("ReserveIdempotencyKey", mock.Anything, scope, key, hashOf("", body), lease).
+					Return(nil, errors.New("db down")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedErr:    "internal server error",
+		},
+		{
+			name:           "Key too long",
+			key:            string(bytes.Repeat([]byte("k"), 256)),
+			setup:          func(store *mocks.IdempotencyStore) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedErr:    "Idempotency-Key is too long",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := mocks.NewIdempotencyStore(t)
+			tc.setup(store)
+
+			calls := 0
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+
+				got, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.Equal(t, body, string(got))
+
+				w.Header().Set("ETag", `"3"`)
+				w.Header().Set("X-Request-Id", "not stored")
+				w.WriteHeader(tc.handlerStatus)
+				_, _ = w.Write([]byte(`{"ok":true}`))
+			})
+
+			handler := mw.IdempotencyMiddleware(discardLogger(), store, ttl, lease)(next)
+
+			req := httptest.NewRequest(http.MethodPost, "/pullRequest/merge", bytes.NewBufferString(body))
+			if tc.key != "" {
+				req.Header.Set(mw.IdempotencyKeyHeader, tc.key)
+			}
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+			require.Equal(t, tc.expectedCalls, calls)
+
+			if tc.replayed {
+				require.Equal(t, "true", rr.Header().Get(mw.IdempotentReplayedHeader))
+			} else {
+				require.Empty(t, rr.Header().Get(mw.IdempotentReplayedHeader))
+			}
+
+			if tc.expectedErr != "" {
+				var resp map[string]any
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				errResp := resp["error"].(map[string]any)
+				require.Equal(t, tc.expectedErr, errResp["message"])
+				return
+			}
+
+			require.Equal(t, tc.expectedBody, rr.Body.String())
+			for name, value := range tc.expectedHeader {
+				require.Equal(t, value, rr.Header().Get(name), name)
+			}
+		})
+	}
+}
+
+func TestIdempotencyMiddlewareScopesKeysToCaller(t *testing.T) {
+	const (
+		body  = `{"pull_request_id":"pr-1"}`
+		key   = "key-1"
+		ttl   = time.Hour
+		lease = time.Minute
+	)
+	secret := []byte("user-secret")
+	adminTokens := map[string]string{"admin": "acme"}
+
+	for name, tc := range map[string]struct {
+		header        string
+		token         string
+		expectedScope string
+	}{
+		"Admin": {header: "X-Admin-Token", token: "admin", expectedScope: "admin:" + adminDigest("admin") + " POST /team/deactivate"},
+		"User": {
+			header:        "X-User-Token",
+			token:         usertoken.Sign(secret, "acme", "u1", time.Now().Add(time.Hour)),
+			expectedScope: "user:u1 POST /team/deactivate",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := mocks.NewIdempotencyStore(t)
+			store.On("ReserveIdempotencyKey", mock.Anything, tc.expectedScope, key, hashOf("", body), lease).
+				Return(&domains.IdempotencyRecord{RequestHash: hashOf("", body)}, nil).Once()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				t.Fatal("a request in progress must not reach the handler")
+			})
+			handler := mw.AdminOrUserAuthMiddleware(adminTokens, secret)(
+				mw.IdempotencyMiddleware(discardLogger(), store, ttl, lease)(next),
+			)
+
+			req := httptest.NewRequest(http.MethodPost, "/team/deactivate", bytes.NewBufferString(body))
+			req.Header.Set(tc.header, tc.token)
+			req.Header.Set(mw.IdempotencyKeyHeader, key)
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, http.StatusConflict, rr.Code)
+		})
+	}
+}
+
+func adminDigest(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:8])
+}
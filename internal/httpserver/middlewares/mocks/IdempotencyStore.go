@@ -0,0 +1,98 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domains "github.com/Deymos01/pr-review-manager/internal/domains"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// IdempotencyStore is an autogenerated mock type for the IdempotencyStore type
+type IdempotencyStore struct {
+	mock.Mock
+}
+
+// CompleteIdempotencyKey provides a mock function with given fields: ctx, scope, key, statusCode, header, body, ttl
+func (_m *IdempotencyStore) CompleteIdempotencyKey(ctx context.Context, scope string, key string, statusCode int, header map[string]string, body []byte, ttl time.Duration) error {
+	ret := _m.Called(ctx, scope, key, statusCode, header, body, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteIdempotencyKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, map[string]string, []byte, time.Duration) error); ok {
+		r0 = rf(ctx, scope, key, statusCode, header, body, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReleaseIdempotencyKey provides a mock function with given fields: ctx, scope, key
+func (_m *IdempotencyStore) ReleaseIdempotencyKey(ctx context.Context, scope string, key string) error {
+	ret := _m.Called(ctx, scope, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseIdempotencyKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, scope, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReserveIdempotencyKey provides a mock function with given fields: ctx, scope, key, requestHash, lease
+func (_m *IdempotencyStore) ReserveIdempotencyKey(ctx context.Context, scope string, key string, requestHash string, lease time.Duration) (*domains.IdempotencyRecord, error) {
+	ret := _m.Called(ctx, scope, key, requestHash, lease)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReserveIdempotencyKey")
+	}
+
+	var r0 *domains.IdempotencyRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, time.Duration) (*domains.IdempotencyRecord, error)); ok {
+		return rf(ctx, scope, key, requestHash, lease)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, time.Duration) *domains.IdempotencyRecord); ok {
+		r0 = rf(ctx, scope, key, requestHash, lease)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.IdempotencyRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, time.Duration) error); ok {
+		r1 = rf(ctx, scope, key, requestHash, lease)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewIdempotencyStore creates a new instance of IdempotencyStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIdempotencyStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IdempotencyStore {
+	mock := &IdempotencyStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
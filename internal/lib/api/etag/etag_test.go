@@ -0,0 +1,34 @@
+package etag
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIfMatch(t *testing.T) {
+	for header, want := range map[string]int64{
+		"":        0,
+		"*":       0,
+		Format(7): 7,
+		` "12" `:  12,
+	} {
+		r := httptest.NewRequest("POST", "/", nil)
+		r.Header.Set("If-Match", header)
+
+		version, err := IfMatch(r)
+		require.NoError(t, err, header)
+		require.Equal(t, want, version, header)
+	}
+}
+
+func TestIfMatch_Invalid(t *testing.T) {
+	for _, header := range []string{`7`, `W/"7"`, `"0"`, `"-1"`, `"a"`, `"1", "2"`, `"`} {
+		r := httptest.NewRequest("POST", "/", nil)
+		r.Header.Set("If-Match", header)
+
+		_, err := IfMatch(r)
+		require.ErrorIs(t, err, ErrInvalidIfMatch, header)
+	}
+}
@@ -0,0 +1,87 @@
+// Package pagination parses the query parameters shared by the cursor-paginated listing endpoints.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 100
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Limit parses the page size, falling back to DefaultLimit when raw is empty.
+func Limit(raw string) (int, error) {
+	if raw == "" {
+		return DefaultLimit, nil
+	}
+
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 1 || limit > MaxLimit {
+		return 0, fmt.Errorf("limit must be an integer between 1 and %d", MaxLimit)
+	}
+
+	return limit, nil
+}
+
+// Desc reports whether raw asks for descending order. Empty means ascending.
+func Desc(raw string) (bool, error) {
+	switch raw {
+	case "", "asc":
+		return false, nil
+	case "desc":
+		return true, nil
+	}
+	return false, errors.New("order must be one of: asc, desc")
+}
+
+// Bool parses an optional boolean filter; an empty value means "not set".
+func Bool(name, raw string) (*bool, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a boolean", name)
+	}
+
+	return &v, nil
+}
+
+// EncodeCursor returns the opaque token handed to clients, or an empty string on the last page.
+func EncodeCursor(c *domains.Cursor) string {
+	if c == nil {
+		return ""
+	}
+
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor parses a token produced by EncodeCursor; an empty token starts from the first page.
+func DecodeCursor(token string) (*domains.Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c domains.Cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	return &c, nil
+}
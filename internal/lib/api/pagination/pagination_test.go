@@ -0,0 +1,44 @@
+package pagination
+
+import (
+	"testing"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	c := &domains.Cursor{Key: "Alice Smith", ID: "u1"}
+
+	decoded, err := DecodeCursor(EncodeCursor(c))
+	require.NoError(t, err)
+	require.Equal(t, c, decoded)
+
+	require.Empty(t, EncodeCursor(nil))
+
+	decoded, err = DecodeCursor("")
+	require.NoError(t, err)
+	require.Nil(t, decoded)
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	for _, token := range []string{"***", "bm90LWpzb24", EncodeCursor(&domains.Cursor{Key: "k"})} {
+		_, err := DecodeCursor(token)
+		require.ErrorIs(t, err, ErrInvalidCursor, token)
+	}
+}
+
+func TestLimit(t *testing.T) {
+	limit, err := Limit("")
+	require.NoError(t, err)
+	require.Equal(t, DefaultLimit, limit)
+
+	limit, err = Limit("7")
+	require.NoError(t, err)
+	require.Equal(t, 7, limit)
+
+	for _, raw := range []string{"0", "-1", "101", "ten"} {
+		_, err := Limit(raw)
+		require.Error(t, err, raw)
+	}
+}
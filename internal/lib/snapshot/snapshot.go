@@ -0,0 +1,256 @@
+// Package snapshot defines the versioned JSON document used to export and restore the service state.
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+)
+
+// Version of the document format. Bump it on any incompatible change of Document.
+// Version 2 added the archived pull requests and the review stats.
+const Version = 2
+
+// minVersion is the oldest format Decode still reads; its documents restore without an
+// archive and without review stats.
+const minVersion = 1
+
+type Document struct {
+	Version      int           `json:"version"`
+	CreatedAt    time.Time     `json:"created_at"`
+	Statuses     []string      `json:"statuses"`
+	Teams        []string      `json:"teams"`
+	Users        []User        `json:"users"`
+	PullRequests []PullRequest `json:"pull_requests"`
+	// ArchivedPullRequests and ReviewStats are missing from version 1 documents
+	ArchivedPullRequests []ArchivedPullRequest `json:"archived_pull_requests"`
+	ReviewStats          []ReviewStats         `json:"review_stats"`
+}
+
+type User struct {
+	UserID   string  `json:"user_id"`
+	Username string  `json:"username"`
+	TeamName *string `json:"team_name"`
+	IsActive bool    `json:"is_active"`
+	Role     string  `json:"role"`
+	// DeletedAt is set for offboarded users; older documents simply lack it
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+type Reviewer struct {
+	UserID     string    `json:"user_id"`
+	AssignedAt time.Time `json:"assigned_at"`
+}
+
+type PullRequest struct {
+	PrID              string     `json:"pull_request_id"`
+	PrName            string     `json:"pull_request_name"`
+	Description       string     `json:"description,omitempty"`
+	Labels            []string   `json:"labels,omitempty"`
+	AuthorID          string     `json:"author_id"`
+	Status            string     `json:"status"`
+	NeedMoreReviewers bool       `json:"need_more_reviewers"`
+	CreatedAt         time.Time  `json:"created_at"`
+	MergedAt          *time.Time `json:"merged_at"`
+	Reviewers         []Reviewer `json:"reviewers"`
+}
+
+type ArchivedPullRequest struct {
+	PrID        string     `json:"pull_request_id"`
+	PrName      string     `json:"pull_request_name"`
+	Description string     `json:"description,omitempty"`
+	Labels      []string   `json:"labels,omitempty"`
+	AuthorID    string     `json:"author_id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	MergedAt    time.Time  `json:"merged_at"`
+	ArchivedAt  time.Time  `json:"archived_at"`
+	Reviewers   []Reviewer `json:"reviewers"`
+}
+
+type ReviewStats struct {
+	UserID   string `json:"user_id"`
+	Authored int64  `json:"authored"`
+	Reviewed int64  `json:"reviewed"`
+}
+
+func FromDomain(snap *domains.Snapshot) Document {
+	doc := Document{
+		Version:      Version,
+		CreatedAt:    snap.CreatedAt,
+		Statuses:     make([]string, 0, len(snap.Statuses)),
+		Teams:        append([]string{}, snap.Teams...),
+		Users:        make([]User, 0, len(snap.Users)),
+		PullRequests: make([]PullRequest, 0, len(snap.PullRequests)),
+
+		ArchivedPullRequests: make([]ArchivedPullRequest, 0, len(snap.ArchivedPullRequests)),
+		ReviewStats:          make([]ReviewStats, 0, len(snap.ReviewStats)),
+	}
+
+	for _, st := range snap.Statuses {
+		doc.Statuses = append(doc.Statuses, string(st))
+	}
+
+	for _, u := range snap.Users {
+		doc.Users = append(doc.Users, User{
+			UserID:    u.ID,
+			Username:  u.Name,
+			TeamName:  u.TeamName,
+			IsActive:  u.IsActive,
+			Role:      string(u.Role),
+			DeletedAt: u.DeletedAt,
+		})
+	}
+
+	for _, pr := range snap.PullRequests {
+		p := PullRequest{
+			PrID:              pr.ID,
+			PrName:            pr.Name,
+			Description:       pr.Description,
+			Labels:            pr.Labels,
+			AuthorID:          pr.Author.ID,
+			Status:            string(pr.Status),
+			NeedMoreReviewers: pr.NeedMoreReviewers,
+			CreatedAt:         pr.CreatedAt,
+			MergedAt:          pr.MergedAt,
+			Reviewers:         make([]Reviewer, 0, len(pr.Reviewers)),
+		}
+		for _, r := range pr.Reviewers {
+			p.Reviewers = append(p.Reviewers, Reviewer{UserID: r.User.ID, AssignedAt: r.AssignedAt})
+		}
+		doc.PullRequests = append(doc.PullRequests, p)
+	}
+
+	for _, archived := range snap.ArchivedPullRequests {
+		pr := archived.PullRequest
+		p := ArchivedPullRequest{
+			PrID:        pr.ID,
+			PrName:      pr.Name,
+			Description: pr.Description,
+			Labels:      pr.Labels,
+			AuthorID:    pr.Author.ID,
+			CreatedAt:   pr.CreatedAt,
+			ArchivedAt:  archived.ArchivedAt,
+			Reviewers:   make([]Reviewer, 0, len(pr.Reviewers)),
+		}
+		if pr.MergedAt != nil {
+			p.MergedAt = *pr.MergedAt
+		}
+		for _, r := range pr.Reviewers {
+			p.Reviewers = append(p.Reviewers, Reviewer{UserID: r.User.ID, AssignedAt: r.AssignedAt})
+		}
+		doc.ArchivedPullRequests = append(doc.ArchivedPullRequests, p)
+	}
+
+	for _, stats := range snap.ReviewStats {
+		doc.ReviewStats = append(doc.ReviewStats, ReviewStats{
+			UserID:   stats.UserID,
+			Authored: stats.Authored,
+			Reviewed: stats.Reviewed,
+		})
+	}
+
+	return doc
+}
+
+func (d Document) ToDomain() *domains.Snapshot {
+	snap := &domains.Snapshot{
+		CreatedAt: d.CreatedAt,
+		Teams:     d.Teams,
+	}
+
+	for _, st := range d.Statuses {
+		snap.Statuses = append(snap.Statuses, domains.PRStatus(st))
+	}
+
+	for _, u := range d.Users {
+		snap.Users = append(snap.Users, &domains.User{
+			ID:        u.UserID,
+			Name:      u.Username,
+			TeamName:  u.TeamName,
+			IsActive:  u.IsActive,
+			Role:      domains.Role(u.Role),
+			DeletedAt: u.DeletedAt,
+		})
+	}
+
+	for _, p := range d.PullRequests {
+		pr := &domains.PullRequest{
+			ID:                p.PrID,
+			Name:              p.PrName,
+			Description:       p.Description,
+			Labels:            p.Labels,
+			Author:            &domains.User{ID: p.AuthorID},
+			Status:            domains.PRStatus(p.Status),
+			NeedMoreReviewers: p.NeedMoreReviewers,
+			CreatedAt:         p.CreatedAt,
+			MergedAt:          p.MergedAt,
+		}
+		for _, r := range p.Reviewers {
+			pr.Reviewers = append(pr.Reviewers, &domains.Reviewer{
+				User:       &domains.User{ID: r.UserID},
+				AssignedAt: r.AssignedAt,
+			})
+		}
+		snap.PullRequests = append(snap.PullRequests, pr)
+	}
+
+	for _, p := range d.ArchivedPullRequests {
+		mergedAt := p.MergedAt
+		pr := &domains.PullRequest{
+			ID:          p.PrID,
+			Name:        p.PrName,
+			Description: p.Description,
+			Labels:      p.Labels,
+			Author:      &domains.User{ID: p.AuthorID},
+			Status:      domains.PRStatusMerged,
+			CreatedAt:   p.CreatedAt,
+			MergedAt:    &mergedAt,
+		}
+		for _, r := range p.Reviewers {
+			pr.Reviewers = append(pr.Reviewers, &domains.Reviewer{
+				User:       &domains.User{ID: r.UserID},
+				AssignedAt: r.AssignedAt,
+			})
+		}
+		snap.ArchivedPullRequests = append(snap.ArchivedPullRequests, &domains.ArchivedPullRequest{
+			PullRequest: pr,
+			ArchivedAt:  p.ArchivedAt,
+		})
+	}
+
+	for _, stats := range d.ReviewStats {
+		snap.ReviewStats = append(snap.ReviewStats, &domains.ReviewStats{
+			UserID:   stats.UserID,
+			Authored: stats.Authored,
+			Reviewed: stats.Reviewed,
+		})
+	}
+
+	return snap
+}
+
+func Encode(w io.Writer, snap *domains.Snapshot) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(FromDomain(snap))
+}
+
+// Decode reads a document and rejects versions this build does not understand.
+func Decode(r io.Reader) (*domains.Snapshot, error) {
+	var doc Document
+
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	if doc.Version < minVersion || doc.Version > Version {
+		return nil, fmt.Errorf("unsupported snapshot version %d, expected %d to %d", doc.Version, minVersion, Version)
+	}
+
+	return doc.ToDomain(), nil
+}
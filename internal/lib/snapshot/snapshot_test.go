@@ -0,0 +1,88 @@
+package snapshot_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/lib/snapshot"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	team := "backend"
+	createdAt := time.Date(2025, 10, 24, 12, 0, 0, 0, time.UTC)
+	mergedAt := createdAt.Add(time.Hour)
+
+	snap := &domains.Snapshot{
+		CreatedAt: createdAt,
+		Statuses:  domains.PRStatuses,
+		Teams:     []string{team},
+		Users: []*domains.User{
+			{ID: "u1", Name: "Alice", TeamName: &team, IsActive: true, Role: domains.RoleLead},
+			{ID: "u2", Name: "Bob", IsActive: false, Role: domains.RoleMember},
+		},
+		PullRequests: []*domains.PullRequest{
+			{
+				ID:        "pr1",
+				Name:      "Add search",
+				Author:    &domains.User{ID: "u1"},
+				Status:    "MERGED",
+				CreatedAt: createdAt,
+				MergedAt:  &mergedAt,
+				Reviewers: []*domains.Reviewer{
+					{User: &domains.User{ID: "u2"}, AssignedAt: createdAt},
+				},
+			},
+		},
+		ArchivedPullRequests: []*domains.ArchivedPullRequest{
+			{
+				PullRequest: &domains.PullRequest{
+					ID:        "pr0",
+					Name:      "Bootstrap",
+					Labels:    []string{"infra"},
+					Author:    &domains.User{ID: "u2"},
+					Status:    domains.PRStatusMerged,
+					CreatedAt: createdAt.Add(-48 * time.Hour),
+					MergedAt:  &createdAt,
+					Reviewers: []*domains.Reviewer{
+						{User: &domains.User{ID: "u1"}, AssignedAt: createdAt.Add(-48 * time.Hour)},
+					},
+				},
+				ArchivedAt: mergedAt,
+			},
+		},
+		ReviewStats: []*domains.ReviewStats{
+			{UserID: "u1", Reviewed: 1},
+			{UserID: "u2", Authored: 1},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, snapshot.Encode(&buf, snap))
+	require.Contains(t, buf.String(), `"version": 2`)
+
+	decoded, err := snapshot.Decode(&buf)
+	require.NoError(t, err)
+	require.Equal(t, snap, decoded)
+}
+
+func TestDecode_ReadsVersion1(t *testing.T) {
+	snap, err := snapshot.Decode(strings.NewReader(`{"version": 1, "teams": ["backend"]}`))
+	require.NoError(t, err)
+	require.Equal(t, []string{"backend"}, snap.Teams)
+	require.Empty(t, snap.ArchivedPullRequests)
+	require.Empty(t, snap.ReviewStats)
+}
+
+func TestDecode_RejectsUnknownVersion(t *testing.T) {
+	_, err := snapshot.Decode(strings.NewReader(`{"version": 3}`))
+	require.EqualError(t, err, "unsupported snapshot version 3, expected 1 to 2")
+}
+
+func TestDecode_RejectsUnknownFields(t *testing.T) {
+	_, err := snapshot.Decode(strings.NewReader(`{"version": 1, "secrets": []}`))
+	require.Error(t, err)
+}
@@ -0,0 +1,24 @@
+// Package tenant carries the organization a request acts for. Repositories scope every
+// query to it, so data of one organization is never visible to another.
+package tenant
+
+import "context"
+
+// Default owns the data created before organizations were introduced and the requests
+// authenticated with the top-level admin token.
+const Default = "default"
+
+type ctxKey struct{}
+
+// WithOrg returns a context acting for org.
+func WithOrg(ctx context.Context, org string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, org)
+}
+
+// Org returns the organization of ctx, or Default when none was set.
+func Org(ctx context.Context) string {
+	if org, ok := ctx.Value(ctxKey{}).(string); ok && org != "" {
+		return org
+	}
+	return Default
+}
@@ -0,0 +1,49 @@
+package usertoken
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	secret = []byte("secret")
+	now    = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func TestVerify(t *testing.T) {
+	token := Sign(secret, "acme", "u1", now.Add(time.Hour))
+
+	org, userID, err := Verify(secret, token, now)
+	require.NoError(t, err)
+	require.Equal(t, "acme", org)
+	require.Equal(t, "u1", userID)
+}
+
+func TestVerify_Invalid(t *testing.T) {
+	valid := Sign(secret, "acme", "u1", now.Add(time.Hour))
+	parts := strings.Split(valid, ".")
+	otherOrg := Sign(secret, "globex", "u1", now.Add(time.Hour))
+
+	for name, tc := range map[string]struct {
+		secret []byte
+		token  string
+	}{
+		"Empty":          {secret: secret, token: ""},
+		"Malformed":      {secret: secret, token: "u1"},
+		"Other secret":   {secret: []byte("other"), token: valid},
+		"No secret":      {secret: nil, token: valid},
+		"Expired":        {secret: secret, token: Sign(secret, "acme", "u1", now)},
+		"Forged user":    {secret: secret, token: parts[0] + "." + encoding.EncodeToString([]byte("u2")) + "." + parts[2] + "." + parts[3]},
+		"Moved org":      {secret: secret, token: strings.SplitN(otherOrg, ".", 2)[0] + "." + strings.SplitN(valid, ".", 2)[1]},
+		"Extended":       {secret: secret, token: parts[0] + "." + parts[1] + ".99999999999." + parts[3]},
+		"Bad signature":  {secret: secret, token: parts[0] + "." + parts[1] + "." + parts[2] + ".!!"},
+		"Empty user id":  {secret: secret, token: Sign(secret, "acme", "", now.Add(time.Hour))},
+		"Empty org name": {secret: secret, token: Sign(secret, "", "u1", now.Add(time.Hour))},
+	} {
+		_, _, err := Verify(tc.secret, tc.token, now)
+		require.ErrorIs(t, err, ErrInvalid, name)
+	}
+}
@@ -0,0 +1,176 @@
+// Package cache is a read-through cache in front of a repository backend. It serves
+// GetTeamByName and UsersReview from a Backend and drops the cached entries of an
+// organization whenever a mutation could change them.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/lib/tenant"
+	"github.com/Deymos01/pr-review-manager/internal/repository"
+)
+
+// Repository is the backend being cached.
+type Repository = repository.Storage
+
+// Backend keeps encoded entries and the generation counters that invalidate them.
+type Backend interface {
+	// Get returns the entry stored at key; ok is false when it is missing or expired.
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Counter returns the counter at key, zero when it was never incremented.
+	Counter(ctx context.Context, key string) (int64, error)
+	// Incr increments the counter at key. Counters never expire.
+	Incr(ctx context.Context, key string) error
+}
+
+// namespace groups the entries dropped together by a mutation.
+type namespace string
+
+const (
+	// nsTeams holds teams with their members
+	nsTeams namespace = "team"
+	// nsReviews holds review pages of users
+	nsReviews namespace = "review"
+)
+
+const keyPrefix = "prm"
+
+// Stats counts cache lookups since the storage was created.
+type Stats struct {
+	Hits          uint64 `json:"hits"`
+	Misses        uint64 `json:"misses"`
+	Invalidations uint64 `json:"invalidations"`
+	Errors        uint64 `json:"errors"`
+}
+
+type metrics struct {
+	hits          atomic.Uint64
+	misses        atomic.Uint64
+	invalidations atomic.Uint64
+	errors        atomic.Uint64
+}
+
+// Storage decorates a Repository. Every entry key carries the generation of its
+// organization and namespace, and a mutation increments that generation, so stale entries
+// are never read again and expire with their TTL. A failing Backend never fails a call:
+// reads fall through to the Repository and the error is only counted.
+type Storage struct {
+	Repository
+	backend Backend
+	ttl     time.Duration
+	metrics metrics
+}
+
+func New(repo Repository, backend Backend, ttl time.Duration) *Storage {
+	return &Storage{Repository: repo, backend: backend, ttl: ttl}
+}
+
+// Stats returns cache hit and miss metrics.
+func (s *Storage) Stats() Stats {
+	return Stats{
+		Hits:          s.metrics.hits.Load(),
+		Misses:        s.metrics.misses.Load(),
+		Invalidations: s.metrics.invalidations.Load(),
+		Errors:        s.metrics.errors.Load(),
+	}
+}
+
+// read returns the entry of ns and id from the backend, or loads it with load and stores
+// it. Inside a transaction the backend is bypassed: the transaction may see its own
+// uncommitted changes, which must not reach other readers.
+func read[T any](
+	ctx context.Context,
+	s *Storage,
+	ns namespace,
+	id string,
+	load func() (T, error),
+) (T, error) {
+	if inTx(ctx) {
+		return load()
+	}
+
+	// The generation is read before loading, so an entry loaded concurrently with
+	// a mutation is stored under the generation that mutation retires
+	key, ok := s.entryKey(ctx, ns, id)
+	if !ok {
+		return load()
+	}
+
+	if data, found, err := s.backend.Get(ctx, key); err != nil {
+		s.metrics.errors.Add(1)
+	} else if found {
+		var v T
+		if err := json.Unmarshal(data, &v); err == nil {
+			s.metrics.hits.Add(1)
+			return v, nil
+		}
+		s.metrics.errors.Add(1)
+	}
+	s.metrics.misses.Add(1)
+
+	v, err := load()
+	if err != nil {
+		return v, err
+	}
+
+	data, err := json.Marshal(v)
+	if err == nil {
+		err = s.backend.Set(ctx, key, data, s.ttl)
+	}
+	if err != nil {
+		s.metrics.errors.Add(1)
+	}
+
+	return v, nil
+}
+
+// invalidate retires the entries of namespaces in the organization of ctx. Inside a
+// transaction it waits for the commit, see WithinTx.
+func (s *Storage) invalidate(ctx context.Context, namespaces ...namespace) {
+	if p, ok := ctx.Value(txKey{}).(*pending); ok {
+		p.add(namespaces)
+		return
+	}
+
+	for _, ns := range namespaces {
+		if err := s.backend.Incr(ctx, generationKey(tenant.Org(ctx), ns)); err != nil {
+			s.metrics.errors.Add(1)
+			continue
+		}
+		s.metrics.invalidations.Add(1)
+	}
+}
+
+// entryKey returns the key of id in ns under the current generation. It fails when the
+// generation cannot be read.
+func (s *Storage) entryKey(ctx context.Context, ns namespace, id string) (string, bool) {
+	org := tenant.Org(ctx)
+
+	gen, err := s.backend.Counter(ctx, generationKey(org, ns))
+	if err != nil {
+		s.metrics.errors.Add(1)
+		return "", false
+	}
+
+	return joinKey(keyPrefix, org, string(ns), strconv.FormatInt(gen, 10), id), true
+}
+
+func generationKey(org string, ns namespace) string {
+	return joinKey(keyPrefix, org, string(ns), "gen")
+}
+
+// joinKey escapes every part, so organizations and ids may contain the separator.
+func joinKey(parts ...string) string {
+	escaped := make([]string, len(parts))
+	for i, p := range parts {
+		escaped[i] = url.QueryEscape(p)
+	}
+	return strings.Join(escaped, ":")
+}
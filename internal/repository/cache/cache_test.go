@@ -0,0 +1,263 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/lib/tenant"
+	"github.com/Deymos01/pr-review-manager/internal/repository/cache"
+	"github.com/Deymos01/pr-review-manager/internal/repository/conformance"
+	"github.com/Deymos01/pr-review-manager/internal/repository/memory"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func newRedis(t *testing.T) *cache.Redis {
+	t.Helper()
+
+	srv := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return cache.NewRedis(client)
+}
+
+func TestConformance(t *testing.T) {
+	t.Run("LRU", func(t *testing.T) {
+		conformance.Run(t, func(t *testing.T) conformance.Storage {
+			return cache.New(memory.New(), cache.NewLRU(100), time.Minute)
+		})
+	})
+
+	t.Run("Redis", func(t *testing.T) {
+		conformance.Run(t, func(t *testing.T) conformance.Storage {
+			return cache.New(memory.New(), newRedis(t), time.Minute)
+		})
+	})
+}
+
+// seed creates the "backend" team with an open pull request reviewed by two of its members.
+func seed(t *testing.T, s *cache.Storage) {
+	t.Helper()
+	ctx := context.Background()
+
+	err := s.CreateTeam(ctx, &domains.Team{
+		Name: "backend",
+		Members: []*domains.User{
+			{ID: "u1", Name: "Alice", IsActive: true},
+			{ID: "u2", Name: "Bob", IsActive: true},
+			{ID: "u3", Name: "Carol", IsActive: true},
+			{ID: "u4", Name: "Dan", IsActive: true},
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = s.CreatePullRequest(ctx, "pr1", "Feature", "u1", false)
+	require.NoError(t, err)
+}
+
+// snapshot reads the team and the reviews of every member through the cache.
+func snapshot(t *testing.T, s *cache.Storage) (*domains.Team, map[string]*domains.ReviewPage) {
+	t.Helper()
+	ctx := context.Background()
+
+	team, err := s.GetTeamByName(ctx, "backend")
+	require.NoError(t, err)
+
+	reviews := make(map[string]*domains.ReviewPage)
+	for _, id := range []string{"u1", "u2", "u3", "u4"} {
+		reviews[id], err = s.UsersReview(ctx, id, domains.ReviewFilter{Limit: 10})
+		require.NoError(t, err)
+	}
+
+	return team, reviews
+}
+
+func TestStorageInvalidation(t *testing.T) {
+	cases := []struct {
+		name   string
+		mutate func(t *testing.T, s *cache.Storage)
+	}{
+		{
+			name: "Create team",
+			mutate: func(t *testing.T, s *cache.Storage) {
+				err := s.CreateTeam(context.Background(), &domains.Team{
+					Name:    "frontend",
+					Members: []*domains.User{{ID: "u4", Name: "Dan", IsActive: true}},
+				})
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Create pull request",
+			mutate: func(t *testing.T, s *cache.Storage) {
+				_, err := s.CreatePullRequest(context.Background(), "pr2", "Fix", "u2", false)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Merge",
+			mutate: func(t *testing.T, s *cache.Storage) {
+				require.NoError(t, s.MergePullRequest(context.Background(), "pr1", 0))
+			},
+		},
+		{
+			name: "Reassign",
+			mutate: func(t *testing.T, s *cache.Storage) {
+				pr, err := s.GetPullRequestByID(context.Background(), "pr1")
+				require.NoError(t, err)
+
+				_, err = s.ReassignReviewer(context.Background(), "pr1", pr.Reviewers[0].User.ID, 0)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Deactivate",
+			mutate: func(t *testing.T, s *cache.Storage) {
+				pr, err := s.GetPullRequestByID(context.Background(), "pr1")
+				require.NoError(t, err)
+
+				_, _, err = s.DeactivateTeamMembers(context.Background(), "backend",
+					[]string{pr.Reviewers[0].User.ID}, 0)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Set is active",
+			mutate: func(t *testing.T, s *cache.Storage) {
+				_, err := s.SetUserStatus(context.Background(), "u2", false)
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := memory.New()
+			s := cache.New(repo, cache.NewLRU(100), time.Minute)
+			seed(t, s)
+
+			snapshot(t, s)
+			snapshot(t, s)
+			require.Equal(t, uint64(5), s.Stats().Hits, "the second read is served from the cache")
+
+			tc.mutate(t, s)
+
+			// a cached read must match the repository after any mutation
+			team, reviews := snapshot(t, s)
+			expectedTeam, err := repo.GetTeamByName(context.Background(), "backend")
+			require.NoError(t, err)
+			require.Equal(t, expectedTeam, team)
+			for id, page := range reviews {
+				expected, err := repo.UsersReview(context.Background(), id, domains.ReviewFilter{Limit: 10})
+				require.NoError(t, err)
+				require.Equal(t, len(expected.Reviews), len(page.Reviews), id)
+				for i := range expected.Reviews {
+					require.Equal(t, expected.Reviews[i].PullRequest.Status, page.Reviews[i].PullRequest.Status, id)
+					require.Equal(t, expected.Reviews[i].PullRequest.ID, page.Reviews[i].PullRequest.ID, id)
+				}
+			}
+			require.NotZero(t, s.Stats().Invalidations)
+		})
+	}
+}
+
+func TestStorageInvalidatesAfterCommit(t *testing.T) {
+	ctx := context.Background()
+	s := cache.New(memory.New(), cache.NewLRU(100), time.Minute)
+	seed(t, s)
+
+	_, err := s.GetTeamByName(ctx, "backend")
+	require.NoError(t, err)
+
+	errAbort := errors.New("abort")
+	err = s.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.SetUserStatus(ctx, "u2", false); err != nil {
+			return err
+		}
+
+		team, err := s.GetTeamByName(ctx, "backend")
+		require.NoError(t, err)
+		require.False(t, team.Members[1].IsActive, "the transaction sees its own changes")
+
+		return errAbort
+	})
+	require.ErrorIs(t, err, errAbort)
+
+	before := s.Stats()
+	team, err := s.GetTeamByName(ctx, "backend")
+	require.NoError(t, err)
+	require.True(t, team.Members[1].IsActive)
+	require.Equal(t, before.Hits+1, s.Stats().Hits, "a rolled back transaction keeps the cache")
+
+	err = s.WithinTx(ctx, func(ctx context.Context) error {
+		_, err := s.SetUserStatus(ctx, "u2", false)
+		return err
+	})
+	require.NoError(t, err)
+
+	team, err = s.GetTeamByName(ctx, "backend")
+	require.NoError(t, err)
+	require.False(t, team.Members[1].IsActive)
+}
+
+func TestStorageScopesEntriesToOrganization(t *testing.T) {
+	s := cache.New(memory.New(), cache.NewLRU(100), time.Minute)
+	acme := tenant.WithOrg(context.Background(), "acme")
+
+	seed(t, s)
+	_, err := s.GetTeamByName(context.Background(), "backend")
+	require.NoError(t, err)
+
+	_, err = s.GetTeamByName(acme, "backend")
+	require.Error(t, err, "a team cached for one organization is not served to another")
+
+	err = s.CreateTeam(acme, &domains.Team{
+		Name:    "backend",
+		Members: []*domains.User{{ID: "a1", Name: "Eve", IsActive: true}},
+	})
+	require.NoError(t, err)
+
+	team, err := s.GetTeamByName(acme, "backend")
+	require.NoError(t, err)
+	require.Len(t, team.Members, 1)
+
+	team, err = s.GetTeamByName(context.Background(), "backend")
+	require.NoError(t, err)
+	require.Len(t, team.Members, 4)
+}
+
+// failingBackend fails every call, like an unreachable Redis.
+type failingBackend struct{}
+
+var errBackend = errors.New("backend unavailable")
+
+func (failingBackend) Get(context.Context, string) ([]byte, bool, error) {
+	return nil, false, errBackend
+}
+func (failingBackend) Set(context.Context, string, []byte, time.Duration) error {
+	return errBackend
+}
+func (failingBackend) Counter(context.Context, string) (int64, error) { return 0, errBackend }
+func (failingBackend) Incr(context.Context, string) error             { return errBackend }
+
+func TestStorageFallsThroughOnBackendErrors(t *testing.T) {
+	s := cache.New(memory.New(), failingBackend{}, time.Minute)
+	seed(t, s)
+
+	team, err := s.GetTeamByName(context.Background(), "backend")
+	require.NoError(t, err)
+	require.Len(t, team.Members, 4)
+
+	stats := s.Stats()
+	require.Zero(t, stats.Hits)
+	require.NotZero(t, stats.Errors)
+}
@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+	clock := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	c := NewLRU(2)
+	c.now = func() time.Time { return clock }
+
+	require.NoError(t, c.Set(ctx, "a", []byte("1"), time.Minute))
+	require.NoError(t, c.Set(ctx, "b", []byte("2"), time.Minute))
+
+	// reading a makes b the least recently used entry
+	value, ok, err := c.Get(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []byte("1"), value)
+
+	require.NoError(t, c.Set(ctx, "c", []byte("3"), time.Second))
+	_, ok, _ = c.Get(ctx, "b")
+	require.False(t, ok, "b is evicted")
+	require.Equal(t, 2, c.order.Len())
+
+	clock = clock.Add(time.Second)
+	_, ok, _ = c.Get(ctx, "c")
+	require.False(t, ok, "c has expired")
+	_, ok, _ = c.Get(ctx, "a")
+	require.True(t, ok)
+	require.Equal(t, 1, c.order.Len())
+
+	n, err := c.Counter(ctx, "gen")
+	require.NoError(t, err)
+	require.Zero(t, n)
+	require.NoError(t, c.Incr(ctx, "gen"))
+	n, _ = c.Counter(ctx, "gen")
+	require.Equal(t, int64(1), n)
+}
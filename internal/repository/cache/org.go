@@ -0,0 +1,29 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+)
+
+func (s *Storage) ImportTeams(ctx context.Context, teams []*domains.Team, dryRun bool) (*domains.ImportReport, error) {
+	report, err := s.Repository.ImportTeams(ctx, teams, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	// deactivated members may have released reviews
+	if !dryRun {
+		s.invalidate(ctx, nsTeams, nsReviews)
+	}
+	return report, nil
+}
+
+func (s *Storage) RestoreSnapshot(ctx context.Context, snap *domains.Snapshot) error {
+	if err := s.Repository.RestoreSnapshot(ctx, snap); err != nil {
+		return err
+	}
+
+	s.invalidate(ctx, nsTeams, nsReviews)
+	return nil
+}
@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+)
+
+func (s *Storage) CreatePullRequest(ctx context.Context, prID, prName, authorID string, requireLead bool) ([]string, error) {
+	reviewers, err := s.Repository.CreatePullRequest(ctx, prID, prName, authorID, requireLead)
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidate(ctx, nsReviews)
+	return reviewers, nil
+}
+
+func (s *Storage) MergePullRequest(ctx context.Context, prID string, ifVersion int64) error {
+	if err := s.Repository.MergePullRequest(ctx, prID, ifVersion); err != nil {
+		return err
+	}
+
+	s.invalidate(ctx, nsReviews)
+	return nil
+}
+
+func (s *Storage) ReassignReviewer(ctx context.Context, prID, oldUserID string, ifVersion int64) (string, error) {
+	newUserID, err := s.Repository.ReassignReviewer(ctx, prID, oldUserID, ifVersion)
+	if err != nil {
+		return "", err
+	}
+
+	s.invalidate(ctx, nsReviews)
+	return newUserID, nil
+}
+
+func (s *Storage) UpdatePullRequest(ctx context.Context, prID string, upd domains.PullRequestUpdate) error {
+	if err := s.Repository.UpdatePullRequest(ctx, prID, upd); err != nil {
+		return err
+	}
+
+	s.invalidate(ctx, nsReviews)
+	return nil
+}
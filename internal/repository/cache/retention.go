@@ -0,0 +1,25 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+)
+
+func (s *Storage) PruneMergedPullRequests(
+	ctx context.Context,
+	mergedBefore time.Time,
+	mode domains.RetentionMode,
+	limit int,
+) (int, int, error) {
+	prs, reviews, err := s.Repository.PruneMergedPullRequests(ctx, mergedBefore, mode, limit)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if prs > 0 {
+		s.invalidate(ctx, nsReviews)
+	}
+	return prs, reviews, nil
+}
@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+)
+
+func (s *Storage) GetTeamByName(ctx context.Context, name string) (*domains.Team, error) {
+	return read(ctx, s, nsTeams, name, func() (*domains.Team, error) {
+		return s.Repository.GetTeamByName(ctx, name)
+	})
+}
+
+func (s *Storage) CreateTeam(ctx context.Context, team *domains.Team) error {
+	if err := s.Repository.CreateTeam(ctx, team); err != nil {
+		return err
+	}
+
+	// members may have moved in from other teams
+	s.invalidate(ctx, nsTeams)
+	return nil
+}
+
+// DeactivateTeamMembers also invalidates reviews, since the open reviews of deactivated
+// users move to their teammates.
+func (s *Storage) DeactivateTeamMembers(
+	ctx context.Context,
+	teamName string,
+	userIDs []string,
+	ifVersion int64,
+) (*domains.Team, []*domains.ReassignedPR, error) {
+	team, reassigned, err := s.Repository.DeactivateTeamMembers(ctx, teamName, userIDs, ifVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.invalidate(ctx, nsTeams, nsReviews)
+	return team, reassigned, nil
+}
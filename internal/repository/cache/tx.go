@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"context"
+	"sync"
+)
+
+type txKey struct{}
+
+// pending collects the namespaces a transaction invalidates until it commits.
+type pending struct {
+	mu         sync.Mutex
+	namespaces map[namespace]bool
+}
+
+func (p *pending) add(namespaces []namespace) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, ns := range namespaces {
+		p.namespaces[ns] = true
+	}
+}
+
+// WithinTx runs fn in a transaction of the Repository. Reads made with the context passed
+// to fn skip the cache, and the namespaces its mutations touch are invalidated once the
+// transaction commits: invalidating earlier would let a concurrent reader cache the data
+// the transaction is about to replace. Nested calls join the outer transaction.
+func (s *Storage) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if inTx(ctx) {
+		return s.Repository.WithinTx(ctx, fn)
+	}
+
+	p := &pending{namespaces: make(map[namespace]bool)}
+	err := s.Repository.WithinTx(ctx, func(ctx context.Context) error {
+		return fn(context.WithValue(ctx, txKey{}, p))
+	})
+	if err != nil {
+		return err
+	}
+
+	for ns := range p.namespaces {
+		s.invalidate(ctx, ns)
+	}
+
+	return nil
+}
+
+func inTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*pending)
+	return ok
+}
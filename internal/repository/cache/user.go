@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+)
+
+func (s *Storage) UsersReview(ctx context.Context, userID string, filter domains.ReviewFilter) (*domains.ReviewPage, error) {
+	load := func() (*domains.ReviewPage, error) {
+		return s.Repository.UsersReview(ctx, userID, filter)
+	}
+
+	f, err := json.Marshal(filter)
+	if err != nil {
+		return load()
+	}
+
+	return read(ctx, s, nsReviews, joinKey(userID, string(f)), load)
+}
+
+func (s *Storage) SetUserStatus(ctx context.Context, userID string, isActive bool) (*domains.User, error) {
+	u, err := s.Repository.SetUserStatus(ctx, userID, isActive)
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidate(ctx, nsTeams)
+	return u, nil
+}
+
+func (s *Storage) RebalanceReviews(ctx context.Context, userID string) ([]*domains.ReassignedPR, error) {
+	reassigned, err := s.Repository.RebalanceReviews(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidate(ctx, nsReviews)
+	return reassigned, nil
+}
+
+func (s *Storage) SoftDeleteUser(ctx context.Context, userID string) (*domains.User, error) {
+	u, err := s.Repository.SoftDeleteUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidate(ctx, nsTeams, nsReviews)
+	return u, nil
+}
@@ -0,0 +1,934 @@
+// Package conformance is the behaviour shared by every repository backend. Backends run it
+// from their own tests, so that the service works the same whichever storage is configured.
+package conformance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/lib/tenant"
+	"github.com/Deymos01/pr-review-manager/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// Storage is everything the service needs from a repository backend.
+type Storage = repository.Storage
+
+// NewStorage returns an empty storage that is not shared with any other test.
+type NewStorage func(t *testing.T) Storage
+
+// Run checks that the storage returned by newStorage behaves like every other backend.
+// Each case runs in parallel against its own storage.
+func Run(t *testing.T, newStorage NewStorage) {
+	t.Helper()
+
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Storage)
+	}{
+		{name: "CreateTeamUpsertsMembers", fn: testCreateTeamUpsertsMembers},
+		{name: "CreatePullRequest", fn: testCreatePullRequest},
+		{name: "MergePullRequest", fn: testMergePullRequest},
+		{name: "MergePullRequestIdempotent", fn: testMergePullRequestIdempotent},
+		{name: "ReassignReviewer", fn: testReassignReviewer},
+		{name: "ReassignReviewerExclusions", fn: testReassignReviewerExclusions},
+		{name: "UpdatePullRequestAuthor", fn: testUpdatePullRequestAuthor},
+		{name: "DeactivateTeamMembers", fn: testDeactivateTeamMembers},
+		{name: "SoftDeleteUser", fn: testSoftDeleteUser},
+		{name: "PruneMergedPullRequestsArchive", fn: testPruneMergedPullRequests(domains.RetentionArchive)},
+		{name: "PruneMergedPullRequestsDelete", fn: testPruneMergedPullRequests(domains.RetentionDelete)},
+		{name: "SnapshotKeepsRetainedHistory", fn: testSnapshotKeepsRetainedHistory},
+		{name: "WithinTxRollsBack", fn: testWithinTxRollsBack},
+		{name: "ImportTeamsDryRun", fn: testImportTeamsDryRun},
+		{name: "ImportTeamsReleasesReviews", fn: testImportTeamsReleasesReviews},
+		{name: "ListPullRequestsPagination", fn: testListPullRequestsPagination},
+		{name: "IdempotencyKeys", fn: testIdempotencyKeys},
+		{name: "SentinelErrors", fn: testSentinelErrors},
+		{name: "TenantIsolation", fn: testTenantIsolation},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tc.fn(t, newStorage(t))
+		})
+	}
+}
+
+// seedTeam creates the "backend" team: two members, a lead and an observer.
+func seedTeam(t *testing.T, s Storage) {
+	t.Helper()
+
+	seedOrgTeam(context.Background(), t, s)
+}
+
+// seedOrgTeam creates the team of seedTeam in the organization of ctx.
+func seedOrgTeam(ctx context.Context, t *testing.T, s Storage) {
+	t.Helper()
+
+	err := s.CreateTeam(ctx, &domains.Team{
+		Name: "backend",
+		Members: []*domains.User{
+			{ID: "u1", Name: "Alice", IsActive: true},
+			{ID: "u2", Name: "Bob", IsActive: true},
+			{ID: "u3", Name: "Carol", IsActive: true, Role: domains.RoleLead},
+			{ID: "u4", Name: "Dan", IsActive: true, Role: domains.RoleObserver},
+		},
+	})
+	require.NoError(t, err)
+}
+
+func reviewerIDs(pr *domains.PullRequest) []string {
+	ids := make([]string, 0, len(pr.Reviewers))
+	for _, r := range pr.Reviewers {
+		ids = append(ids, r.User.ID)
+	}
+	return ids
+}
+
+func testCreateTeamUpsertsMembers(t *testing.T, s Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	require.Error(t, s.CreateTeam(ctx, &domains.Team{Name: "backend"}), "team names are unique")
+
+	// u2 moves to the new team and takes the submitted name, activity and role
+	err := s.CreateTeam(ctx, &domains.Team{
+		Name: "frontend",
+		Members: []*domains.User{
+			{ID: "u2", Name: "Robert", IsActive: false, Role: domains.RoleLead},
+			{ID: "u6", Name: "Frank", IsActive: true},
+		},
+	})
+	require.NoError(t, err)
+
+	exists, err := s.TeamExists(ctx, "frontend")
+	require.NoError(t, err)
+	require.True(t, exists)
+	exists, err = s.TeamExists(ctx, "mobile")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	backend, err := s.GetTeamByName(ctx, "backend")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), backend.Version, "losing a member changes the team")
+	require.ElementsMatch(t, []string{"u1", "u3", "u4"}, memberIDs(backend))
+
+	frontend, err := s.GetTeamByName(ctx, "frontend")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), frontend.Version)
+	require.ElementsMatch(t, []string{"u2", "u6"}, memberIDs(frontend))
+
+	user, err := s.GetUserByID(ctx, "u2")
+	require.NoError(t, err)
+	require.Equal(t, "Robert", user.Name)
+	require.False(t, user.IsActive)
+	require.Equal(t, domains.RoleLead, user.Role)
+	require.Equal(t, "frontend", *user.TeamName)
+
+	user, err = s.GetUserByID(ctx, "u6")
+	require.NoError(t, err)
+	require.Equal(t, domains.RoleMember, user.Role, "role defaults to member")
+
+	isLead, err := s.UserIsTeamLead(ctx, "u2", "frontend")
+	require.NoError(t, err)
+	require.False(t, isLead, "inactive leads do not count")
+	isLead, err = s.UserIsTeamLead(ctx, "u3", "backend")
+	require.NoError(t, err)
+	require.True(t, isLead)
+
+	// a member listed without a role keeps the current one
+	err = s.CreateTeam(ctx, &domains.Team{
+		Name:    "mobile",
+		Members: []*domains.User{{ID: "u3", Name: "Carol", IsActive: true}},
+	})
+	require.NoError(t, err)
+
+	user, err = s.GetUserByID(ctx, "u3")
+	require.NoError(t, err)
+	require.Equal(t, domains.RoleLead, user.Role, "an omitted role does not demote a lead")
+}
+
+func memberIDs(team *domains.Team) []string {
+	ids := make([]string, 0, len(team.Members))
+	for _, m := range team.Members {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+func testCreatePullRequest(t *testing.T, s Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	reviewers, err := s.CreatePullRequest(ctx, "pr1", "Feature", "u1", true)
+	require.NoError(t, err)
+	require.Len(t, reviewers, 2)
+	require.Contains(t, reviewers, "u3")
+	require.NotContains(t, reviewers, "u1")
+	require.NotContains(t, reviewers, "u4")
+
+	pr, err := s.GetPullRequestByID(ctx, "pr1")
+	require.NoError(t, err)
+	require.Equal(t, domains.PRStatusOpen, pr.Status)
+	require.Equal(t, "Alice", pr.Author.Name)
+	require.Equal(t, int64(1), pr.Version)
+	require.ElementsMatch(t, reviewers, reviewerIDs(pr))
+
+	_, err = s.CreatePullRequest(ctx, "pr1", "Feature", "u1", false)
+	require.ErrorIs(t, err, repository.ErrPRAlreadyExists)
+
+	_, err = s.GetPullRequestByID(ctx, "missing")
+	require.ErrorIs(t, err, repository.ErrPRNotFound)
+}
+
+func testMergePullRequest(t *testing.T, s Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	_, err := s.CreatePullRequest(ctx, "pr1", "Feature", "u1", false)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, s.MergePullRequest(ctx, "pr1", 7), repository.ErrVersionConflict)
+	require.ErrorIs(t, s.MergePullRequest(ctx, "missing", 0), repository.ErrPRNotFound)
+	require.NoError(t, s.MergePullRequest(ctx, "pr1", 1))
+
+	pr, err := s.GetPullRequestByID(ctx, "pr1")
+	require.NoError(t, err)
+	require.Equal(t, domains.PRStatusMerged, pr.Status)
+	require.NotNil(t, pr.MergedAt)
+	require.Equal(t, int64(2), pr.Version)
+
+	_, err = s.ReassignReviewer(ctx, "pr1", pr.Reviewers[0].User.ID, 0)
+	require.ErrorIs(t, err, repository.ErrPRMerged)
+}
+
+func testMergePullRequestIdempotent(t *testing.T, s Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	_, err := s.CreatePullRequest(ctx, "pr1", "Feature", "u1", false)
+	require.NoError(t, err)
+
+	merged, err := s.PullRequestMerged(ctx, "pr1")
+	require.NoError(t, err)
+	require.False(t, merged)
+
+	require.NoError(t, s.MergePullRequest(ctx, "pr1", 0))
+
+	first, err := s.GetPullRequestByID(ctx, "pr1")
+	require.NoError(t, err)
+	require.NotNil(t, first.MergedAt)
+
+	// a later merge would stamp a visibly different merged_at
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, s.MergePullRequest(ctx, "pr1", 0), "merging twice is not an error")
+
+	merged, err = s.PullRequestMerged(ctx, "pr1")
+	require.NoError(t, err)
+	require.True(t, merged)
+
+	pr, err := s.GetPullRequestByID(ctx, "pr1")
+	require.NoError(t, err)
+	require.Equal(t, domains.PRStatusMerged, pr.Status)
+	require.NotNil(t, pr.MergedAt)
+	require.True(t, first.MergedAt.Equal(*pr.MergedAt), "merging again keeps merged_at")
+	require.Equal(t, first.Version, pr.Version, "merging again keeps the version")
+	require.Len(t, pr.Reviewers, 2, "merging keeps the reviewers")
+}
+
+func testReassignReviewer(t *testing.T, s Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	reviewers, err := s.CreatePullRequest(ctx, "pr1", "Feature", "u1", false)
+	require.NoError(t, err)
+
+	// every active teammate except the author already reviews the PR
+	_, err = s.ReassignReviewer(ctx, "pr1", reviewers[0], 0)
+	require.ErrorIs(t, err, repository.ErrNoCandidate)
+
+	_, err = s.ImportTeams(ctx, []*domains.Team{
+		{Name: "backend", Members: []*domains.User{{ID: "u5", Name: "Eve", IsActive: true}}},
+	}, false)
+	require.NoError(t, err)
+
+	newUserID, err := s.ReassignReviewer(ctx, "pr1", reviewers[0], 1)
+	require.NoError(t, err)
+	require.Equal(t, "u5", newUserID)
+
+	pr, err := s.GetPullRequestByID(ctx, "pr1")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"u5", reviewers[1]}, reviewerIDs(pr))
+	require.Equal(t, int64(2), pr.Version)
+}
+
+func testReassignReviewerExclusions(t *testing.T, s Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	_, err := s.ImportTeams(ctx, []*domains.Team{
+		{Name: "backend", Members: []*domains.User{{ID: "u5", Name: "Eve", IsActive: false}}},
+		{Name: "frontend", Members: []*domains.User{{ID: "u6", Name: "Frank", IsActive: true}}},
+	}, false)
+	require.NoError(t, err)
+
+	reviewers, err := s.CreatePullRequest(ctx, "pr1", "Feature", "u1", false)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"u2", "u3"}, reviewers)
+
+	// neither the author, the other reviewer, the observer, the inactive member nor
+	// a member of another team may take over
+	_, err = s.ReassignReviewer(ctx, "pr1", "u2", 0)
+	require.ErrorIs(t, err, repository.ErrNoCandidate)
+
+	_, err = s.SetUserStatus(ctx, "u5", true)
+	require.NoError(t, err)
+
+	newUserID, err := s.ReassignReviewer(ctx, "pr1", "u2", 0)
+	require.NoError(t, err)
+	require.Equal(t, "u5", newUserID)
+
+	assigned, err := s.UserAssigned(ctx, "pr1", "u2")
+	require.NoError(t, err)
+	require.False(t, assigned)
+	assigned, err = s.UserAssigned(ctx, "pr1", "u5")
+	require.NoError(t, err)
+	require.True(t, assigned)
+}
+
+func testUpdatePullRequestAuthor(t *testing.T, s Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	_, err := s.ImportTeams(ctx, []*domains.Team{
+		{Name: "frontend", Members: []*domains.User{{ID: "u6", Name: "Frank", IsActive: true}}},
+	}, false)
+	require.NoError(t, err)
+
+	reviewers, err := s.CreatePullRequest(ctx, "pr1", "Feature", "u1", false)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"u2", "u3"}, reviewers)
+	_, err = s.SetUserStatus(ctx, "u1", false)
+	require.NoError(t, err)
+
+	// an author who does not review the PR keeps every reviewer in place
+	u6 := "u6"
+	require.NoError(t, s.UpdatePullRequest(ctx, "pr1", domains.PullRequestUpdate{AuthorID: &u6, Version: 1}))
+
+	pr, err := s.GetPullRequestByID(ctx, "pr1")
+	require.NoError(t, err)
+	require.Equal(t, "u6", pr.Author.ID)
+	require.ElementsMatch(t, []string{"u2", "u3"}, reviewerIDs(pr))
+
+	// a reviewing author hands their review over to a free teammate
+	_, err = s.ImportTeams(ctx, []*domains.Team{
+		{Name: "backend", Members: []*domains.User{{ID: "u5", Name: "Eve", IsActive: true}}},
+	}, false)
+	require.NoError(t, err)
+
+	u2 := "u2"
+	require.NoError(t, s.UpdatePullRequest(ctx, "pr1", domains.PullRequestUpdate{AuthorID: &u2, Version: 2}))
+
+	updated, err := s.GetPullRequestByID(ctx, "pr1")
+	require.NoError(t, err)
+	require.Equal(t, "u2", updated.Author.ID)
+	require.Equal(t, int64(3), updated.Version)
+	require.ElementsMatch(t, []string{"u3", "u5"}, reviewerIDs(updated))
+	require.Equal(t, assignedAt(pr, "u3"), assignedAt(updated, "u3"), "the untouched reviewer keeps their assignment")
+	require.False(t, updated.NeedMoreReviewers)
+
+	// without a free teammate the review is dropped and the PR asks for more reviewers
+	_, err = s.SetUserStatus(ctx, "u2", false)
+	require.NoError(t, err)
+
+	u5 := "u5"
+	require.NoError(t, s.UpdatePullRequest(ctx, "pr1", domains.PullRequestUpdate{AuthorID: &u5, Version: 3}))
+
+	updated, err = s.GetPullRequestByID(ctx, "pr1")
+	require.NoError(t, err)
+	require.Equal(t, "u5", updated.Author.ID)
+	require.Equal(t, int64(4), updated.Version)
+	require.Equal(t, []string{"u3"}, reviewerIDs(updated))
+	require.True(t, updated.NeedMoreReviewers)
+}
+
+func assignedAt(pr *domains.PullRequest, userID string) time.Time {
+	for _, r := range pr.Reviewers {
+		if r.User.ID == userID {
+			return r.AssignedAt
+		}
+	}
+	return time.Time{}
+}
+
+func testDeactivateTeamMembers(t *testing.T, s Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	reviewers, err := s.CreatePullRequest(ctx, "pr1", "Feature", "u1", false)
+	require.NoError(t, err)
+
+	_, _, err = s.DeactivateTeamMembers(ctx, "backend", []string{"u1", "u9"}, 0)
+	require.ErrorIs(t, err, repository.ErrTeamCompatibility)
+	_, _, err = s.DeactivateTeamMembers(ctx, "backend", []string{"u1"}, 9)
+	require.ErrorIs(t, err, repository.ErrVersionConflict)
+	_, _, err = s.DeactivateTeamMembers(ctx, "frontend", []string{"u1"}, 0)
+	require.ErrorIs(t, err, repository.ErrTeamNotFound)
+
+	team, reassigned, err := s.DeactivateTeamMembers(ctx, "backend", []string{reviewers[0]}, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), team.Version)
+	require.Empty(t, reassigned, "the only other active member already reviews the PR")
+
+	pr, err := s.GetPullRequestByID(ctx, "pr1")
+	require.NoError(t, err)
+	require.Equal(t, []string{reviewers[1]}, reviewerIDs(pr))
+
+	user, err := s.GetUserByID(ctx, reviewers[0])
+	require.NoError(t, err)
+	require.False(t, user.IsActive)
+}
+
+func testSoftDeleteUser(t *testing.T, s Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	// u1 has exactly two candidates, so u2 reviews both PRs
+	_, err := s.CreatePullRequest(ctx, "pr1", "Feature", "u1", false)
+	require.NoError(t, err)
+	_, err = s.CreatePullRequest(ctx, "pr2", "Fix", "u1", false)
+	require.NoError(t, err)
+	require.NoError(t, s.MergePullRequest(ctx, "pr2", 1))
+
+	deleted, err := s.SoftDeleteUser(ctx, "u2")
+	require.NoError(t, err)
+	require.Equal(t, "u2", deleted.ID)
+	require.Equal(t, domains.DeletedUserName, deleted.Name)
+	require.False(t, deleted.IsActive)
+	require.Nil(t, deleted.TeamName)
+	require.NotNil(t, deleted.DeletedAt)
+
+	_, err = s.GetUserByID(ctx, "u2")
+	require.ErrorIs(t, err, repository.ErrUserNotFound)
+	exists, err := s.UserExists(ctx, "u2")
+	require.NoError(t, err)
+	require.False(t, exists)
+	_, err = s.SoftDeleteUser(ctx, "u2")
+	require.ErrorIs(t, err, repository.ErrUserNotFound)
+
+	page, err := s.SearchUsers(ctx, domains.UserFilter{Limit: 10})
+	require.NoError(t, err)
+	for _, u := range page.Users {
+		require.NotEqual(t, "u2", u.ID)
+	}
+
+	backend, err := s.GetTeamByName(ctx, "backend")
+	require.NoError(t, err)
+	require.NotContains(t, memberIDs(backend), "u2")
+
+	open, err := s.GetPullRequestByID(ctx, "pr1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"u3"}, reviewerIDs(open))
+	require.True(t, open.NeedMoreReviewers)
+
+	merged, err := s.GetPullRequestByID(ctx, "pr2")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"u2", "u3"}, reviewerIDs(merged), "history is kept")
+
+	err = s.CreateTeam(ctx, &domains.Team{
+		Name:    "frontend",
+		Members: []*domains.User{{ID: "u2", Name: "Bob", IsActive: true}},
+	})
+	require.ErrorIs(t, err, repository.ErrUserDeleted)
+}
+
+func testPruneMergedPullRequests(mode domains.RetentionMode) func(t *testing.T, s Storage) {
+	return func(t *testing.T, s Storage) {
+		ctx := context.Background()
+		seedTeam(t, s)
+
+		_, err := s.CreatePullRequest(ctx, "pr1", "Feature", "u1", false)
+		require.NoError(t, err)
+		_, err = s.CreatePullRequest(ctx, "pr2", "Fix", "u2", false)
+		require.NoError(t, err)
+		require.NoError(t, s.MergePullRequest(ctx, "pr1", 1))
+
+		before := make(map[string]*domains.ReviewStats)
+		for _, id := range []string{"u1", "u2", "u3"} {
+			before[id], err = s.GetReviewStats(ctx, id)
+			require.NoError(t, err)
+		}
+
+		prs, reviews, err := s.PruneMergedPullRequests(ctx, time.Now().Add(-time.Hour), mode, 10)
+		require.NoError(t, err)
+		require.Zero(t, prs, "pr1 was merged after the cutoff")
+		require.Zero(t, reviews)
+
+		prs, reviews, err = s.PruneMergedPullRequests(ctx, time.Now().Add(time.Hour), mode, 10)
+		require.NoError(t, err)
+		require.Equal(t, 1, prs)
+		require.Equal(t, 2, reviews)
+
+		_, err = s.GetPullRequestByID(ctx, "pr1")
+		require.ErrorIs(t, err, repository.ErrPRNotFound)
+		_, err = s.GetPullRequestByID(ctx, "pr2")
+		require.NoError(t, err, "open pull requests are kept")
+
+		for id, stats := range before {
+			after, err := s.GetReviewStats(ctx, id)
+			require.NoError(t, err)
+			require.Equal(t, stats, after, "totals of %s survive retention", id)
+		}
+
+		prs, _, err = s.PruneMergedPullRequests(ctx, time.Now().Add(time.Hour), mode, 10)
+		require.NoError(t, err)
+		require.Zero(t, prs)
+	}
+}
+
+func testSnapshotKeepsRetainedHistory(t *testing.T, s Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	_, err := s.CreatePullRequest(ctx, "pr1", "Feature", "u1", false)
+	require.NoError(t, err)
+	_, err = s.CreatePullRequest(ctx, "pr2", "Fix", "u2", false)
+	require.NoError(t, err)
+	require.NoError(t, s.MergePullRequest(ctx, "pr1", 1))
+	_, _, err = s.PruneMergedPullRequests(ctx, time.Now().Add(time.Hour), domains.RetentionArchive, 10)
+	require.NoError(t, err)
+
+	snap, err := s.ExportSnapshot(ctx)
+	require.NoError(t, err)
+	require.Len(t, snap.PullRequests, 1)
+	require.Len(t, snap.ArchivedPullRequests, 1)
+	require.Equal(t, "pr1", snap.ArchivedPullRequests[0].PullRequest.ID)
+	require.Len(t, snap.ArchivedPullRequests[0].PullRequest.Reviewers, 2)
+	require.NotEmpty(t, snap.ReviewStats)
+
+	acme := tenant.WithOrg(context.Background(), "acme")
+	require.NoError(t, s.RestoreSnapshot(acme, snap))
+
+	for _, id := range []string{"u1", "u2", "u3"} {
+		expected, err := s.GetReviewStats(ctx, id)
+		require.NoError(t, err)
+		restored, err := s.GetReviewStats(acme, id)
+		require.NoError(t, err)
+		require.Equal(t, expected, restored, "totals of %s survive a restore", id)
+	}
+
+	restored, err := s.ExportSnapshot(acme)
+	require.NoError(t, err)
+	require.Equal(t, snap.ArchivedPullRequests, restored.ArchivedPullRequests)
+	require.Equal(t, snap.ReviewStats, restored.ReviewStats)
+}
+
+func testWithinTxRollsBack(t *testing.T, s Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+	errAbort := errors.New("abort")
+
+	err := s.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.CreatePullRequest(ctx, "pr1", "Feature", "u1", false); err != nil {
+			return err
+		}
+		if _, err := s.SetUserStatus(ctx, "u2", false); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	require.ErrorIs(t, err, errAbort)
+
+	exists, err := s.PullRequestExists(ctx, "pr1")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	user, err := s.GetUserByID(ctx, "u2")
+	require.NoError(t, err)
+	require.True(t, user.IsActive)
+}
+
+func testImportTeamsDryRun(t *testing.T, s Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	err := s.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.SetUserStatus(ctx, "u1", false); err != nil {
+			return err
+		}
+
+		report, err := s.ImportTeams(ctx, []*domains.Team{
+			{Name: "frontend", Members: []*domains.User{{ID: "u6", Name: "Frank", IsActive: true}}},
+		}, true)
+		if err != nil {
+			return err
+		}
+		require.True(t, report.DryRun)
+		require.Equal(t, []string{"frontend"}, report.CreatedTeams)
+		require.Len(t, report.Created, 1)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	// the dry run inside the transaction discards only its own changes
+	exists, err := s.TeamExists(ctx, "frontend")
+	require.NoError(t, err)
+	require.False(t, exists)
+	_, err = s.GetUserByID(ctx, "u6")
+	require.ErrorIs(t, err, repository.ErrUserNotFound)
+
+	user, err := s.GetUserByID(ctx, "u1")
+	require.NoError(t, err)
+	require.False(t, user.IsActive)
+}
+
+func testImportTeamsReleasesReviews(t *testing.T, s Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	_, err := s.ImportTeams(ctx, []*domains.Team{
+		{Name: "backend", Members: []*domains.User{{ID: "u5", Name: "Eve", IsActive: true}}},
+	}, false)
+	require.NoError(t, err)
+
+	reviewers, err := s.CreatePullRequest(ctx, "pr1", "Feature", "u1", false)
+	require.NoError(t, err)
+	require.Len(t, reviewers, 2)
+
+	// an import deactivating a reviewer hands their review over like DeactivateTeamMembers
+	report, err := s.ImportTeams(ctx, []*domains.Team{
+		{Name: "backend", Members: []*domains.User{{ID: reviewers[0], Name: "Renamed", IsActive: false}}},
+	}, false)
+	require.NoError(t, err)
+	require.Len(t, report.Reassigned, 1)
+	require.Equal(t, "pr1", report.Reassigned[0].PrID)
+	require.Equal(t, reviewers[0], report.Reassigned[0].OldUserID)
+
+	pr, err := s.GetPullRequestByID(ctx, "pr1")
+	require.NoError(t, err)
+	require.NotContains(t, reviewerIDs(pr), reviewers[0])
+	require.Contains(t, reviewerIDs(pr), report.Reassigned[0].NewUserID)
+	require.Len(t, pr.Reviewers, 2)
+}
+
+func testListPullRequestsPagination(t *testing.T, s Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	for _, id := range []string{"pr1", "pr2", "pr3"} {
+		_, err := s.CreatePullRequest(ctx, id, "Feature", "u1", false)
+		require.NoError(t, err)
+	}
+
+	var seen []string
+	filter := domains.PullRequestFilter{AuthorID: "u1", Limit: 2}
+	for {
+		page, err := s.ListPullRequests(ctx, filter)
+		require.NoError(t, err)
+		for _, pr := range page.PullRequests {
+			seen = append(seen, pr.ID)
+		}
+		if page.Next == nil {
+			break
+		}
+		filter.After = page.Next
+	}
+	require.Equal(t, []string{"pr1", "pr2", "pr3"}, seen)
+
+	_, err := s.ListPullRequests(ctx, domains.PullRequestFilter{After: &domains.Cursor{Key: "bad"}, Limit: 2})
+	require.ErrorIs(t, err, repository.ErrInvalidCursor)
+}
+
+func testIdempotencyKeys(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	record, err := s.ReserveIdempotencyKey(ctx, "POST /x", "k", "hash", time.Hour)
+	require.NoError(t, err)
+	require.Nil(t, record)
+
+	record, err = s.ReserveIdempotencyKey(ctx, "POST /x", "k", "hash", time.Hour)
+	require.NoError(t, err)
+	require.False(t, record.Completed)
+
+	header := map[string]string{"Content-Type": "application/json", "ETag": `"1"`}
+	require.NoError(t, s.CompleteIdempotencyKey(ctx, "POST /x", "k", 201, header, []byte(`{}`), time.Hour))
+	record, err = s.ReserveIdempotencyKey(ctx, "POST /x", "k", "hash", time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, &domains.IdempotencyRecord{
+		RequestHash: "hash",
+		Completed:   true,
+		StatusCode:  201,
+		Header:      header,
+		Body:        []byte(`{}`),
+	}, record)
+
+	// a request whose lease ran out gives the key up to a retry
+	record, err = s.ReserveIdempotencyKey(ctx, "POST /x", "abandoned", "hash", -time.Second)
+	require.NoError(t, err)
+	require.Nil(t, record)
+	record, err = s.ReserveIdempotencyKey(ctx, "POST /x", "abandoned", "hash", time.Hour)
+	require.NoError(t, err)
+	require.Nil(t, record, "the retry takes the key over")
+	record, err = s.ReserveIdempotencyKey(ctx, "POST /x", "abandoned", "hash", time.Hour)
+	require.NoError(t, err)
+	require.False(t, record.Completed)
+
+	// the TTL of a stored response starts when it is completed, not with the lease
+	record, err = s.ReserveIdempotencyKey(ctx, "POST /x", "slow", "hash", -time.Second)
+	require.NoError(t, err)
+	require.Nil(t, record)
+	require.NoError(t, s.CompleteIdempotencyKey(ctx, "POST /x", "slow", 200, header, []byte(`{}`), time.Hour))
+	record, err = s.ReserveIdempotencyKey(ctx, "POST /x", "slow", "hash", time.Hour)
+	require.NoError(t, err)
+	require.True(t, record.Completed)
+
+	record, err = s.ReserveIdempotencyKey(ctx, "POST /x", "expired", "hash", -time.Second)
+	require.NoError(t, err)
+	require.Nil(t, record)
+
+	n, err := s.PurgeIdempotencyKeys(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+}
+
+func testSentinelErrors(t *testing.T, s Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	err := s.CreateTeam(ctx, &domains.Team{
+		Name:    "frontend",
+		Members: []*domains.User{{ID: "u6", Name: "Frank", IsActive: true}, {ID: "u7", Name: "Grace", IsActive: true}},
+	})
+	require.NoError(t, err)
+
+	_, err = s.CreatePullRequest(ctx, "pr1", "Feature", "u1", false)
+	require.NoError(t, err)
+	_, err = s.CreatePullRequest(ctx, "merged", "Feature", "u1", false)
+	require.NoError(t, err)
+	require.NoError(t, s.MergePullRequest(ctx, "merged", 0))
+
+	name := "Renamed"
+
+	tests := []struct {
+		name    string
+		call    func() error
+		wantErr error
+	}{
+		{
+			name:    "get missing team",
+			call:    func() error { _, err := s.GetTeamByName(ctx, "missing"); return err },
+			wantErr: repository.ErrTeamNotFound,
+		},
+		{
+			name: "deactivate in missing team",
+			call: func() error {
+				_, _, err := s.DeactivateTeamMembers(ctx, "missing", []string{"u1"}, 0)
+				return err
+			},
+			wantErr: repository.ErrTeamNotFound,
+		},
+		{
+			name: "deactivate outside of team",
+			call: func() error {
+				_, _, err := s.DeactivateTeamMembers(ctx, "backend", []string{"u6"}, 0)
+				return err
+			},
+			wantErr: repository.ErrTeamCompatibility,
+		},
+		{
+			name:    "get missing user",
+			call:    func() error { _, err := s.GetUserByID(ctx, "missing"); return err },
+			wantErr: repository.ErrUserNotFound,
+		},
+		{
+			name:    "set status of missing user",
+			call:    func() error { _, err := s.SetUserStatus(ctx, "missing", false); return err },
+			wantErr: repository.ErrUserNotFound,
+		},
+		{
+			name:    "rebalance missing user",
+			call:    func() error { _, err := s.RebalanceReviews(ctx, "missing"); return err },
+			wantErr: repository.ErrUserNotFound,
+		},
+		{
+			name:    "create existing pull request",
+			call:    func() error { _, err := s.CreatePullRequest(ctx, "pr1", "Feature", "u1", false); return err },
+			wantErr: repository.ErrPRAlreadyExists,
+		},
+		{
+			name:    "require a lead in a team without one",
+			call:    func() error { _, err := s.CreatePullRequest(ctx, "pr2", "Feature", "u6", true); return err },
+			wantErr: repository.ErrNoCandidate,
+		},
+		{
+			name:    "get missing pull request",
+			call:    func() error { _, err := s.GetPullRequestByID(ctx, "missing"); return err },
+			wantErr: repository.ErrPRNotFound,
+		},
+		{
+			name:    "check merge of missing pull request",
+			call:    func() error { _, err := s.PullRequestMerged(ctx, "missing"); return err },
+			wantErr: repository.ErrPRNotFound,
+		},
+		{
+			name:    "merge missing pull request",
+			call:    func() error { return s.MergePullRequest(ctx, "missing", 0) },
+			wantErr: repository.ErrPRNotFound,
+		},
+		{
+			name:    "merge stale version",
+			call:    func() error { return s.MergePullRequest(ctx, "pr1", 5) },
+			wantErr: repository.ErrVersionConflict,
+		},
+		{
+			name:    "reassign on missing pull request",
+			call:    func() error { _, err := s.ReassignReviewer(ctx, "missing", "u2", 0); return err },
+			wantErr: repository.ErrPRNotFound,
+		},
+		{
+			name:    "reassign on merged pull request",
+			call:    func() error { _, err := s.ReassignReviewer(ctx, "merged", "u2", 0); return err },
+			wantErr: repository.ErrPRMerged,
+		},
+		{
+			name:    "reassign stale version",
+			call:    func() error { _, err := s.ReassignReviewer(ctx, "pr1", "u2", 5); return err },
+			wantErr: repository.ErrVersionConflict,
+		},
+		{
+			name: "update missing pull request",
+			call: func() error {
+				return s.UpdatePullRequest(ctx, "missing", domains.PullRequestUpdate{Name: &name, Version: 1})
+			},
+			wantErr: repository.ErrPRNotFound,
+		},
+		{
+			name: "update merged pull request",
+			call: func() error {
+				return s.UpdatePullRequest(ctx, "merged", domains.PullRequestUpdate{Name: &name, Version: 2})
+			},
+			wantErr: repository.ErrPRMerged,
+		},
+		{
+			name: "update stale version",
+			call: func() error {
+				return s.UpdatePullRequest(ctx, "pr1", domains.PullRequestUpdate{Name: &name, Version: 5})
+			},
+			wantErr: repository.ErrVersionConflict,
+		},
+		{
+			name: "list reviews after invalid cursor",
+			call: func() error {
+				_, err := s.UsersReview(ctx, "u2", domains.ReviewFilter{After: &domains.Cursor{Key: "bad"}, Limit: 2})
+				return err
+			},
+			wantErr: repository.ErrInvalidCursor,
+		},
+		{
+			name:    "restore into non-empty storage",
+			call:    func() error { return s.RestoreSnapshot(ctx, &domains.Snapshot{}) },
+			wantErr: repository.ErrStorageNotEmpty,
+		},
+	}
+
+	for _, tc := range tests {
+		require.ErrorIs(t, tc.call(), tc.wantErr, tc.name)
+	}
+}
+
+func testTenantIsolation(t *testing.T, s Storage) {
+	acme := tenant.WithOrg(context.Background(), "acme")
+	globex := tenant.WithOrg(context.Background(), "globex")
+
+	// the same team name, user ids and pull request id live in both organizations
+	seedOrgTeam(acme, t, s)
+	seedOrgTeam(globex, t, s)
+	_, err := s.CreatePullRequest(acme, "pr1", "Feature", "u1", false)
+	require.NoError(t, err)
+	_, err = s.CreatePullRequest(globex, "pr1", "Feature", "u1", false)
+	require.NoError(t, err)
+
+	err = s.CreateTeam(acme, &domains.Team{
+		Name:    "frontend",
+		Members: []*domains.User{{ID: "u6", Name: "Frank", IsActive: true}, {ID: "u7", Name: "Grace", IsActive: true}},
+	})
+	require.NoError(t, err)
+	_, err = s.CreatePullRequest(acme, "pr2", "Fix", "u6", false)
+	require.NoError(t, err)
+	require.NoError(t, s.MergePullRequest(acme, "pr1", 0))
+	_, err = s.SetUserStatus(acme, "u2", false)
+	require.NoError(t, err)
+	record, err := s.ReserveIdempotencyKey(acme, "POST /x", "k", "hash", time.Hour)
+	require.NoError(t, err)
+	require.Nil(t, record)
+
+	// nothing acme did is visible from globex
+	exists, err := s.TeamExists(globex, "frontend")
+	require.NoError(t, err)
+	require.False(t, exists)
+	_, err = s.GetTeamByName(globex, "frontend")
+	require.ErrorIs(t, err, repository.ErrTeamNotFound)
+	_, _, err = s.DeactivateTeamMembers(globex, "frontend", []string{"u6"}, 0)
+	require.ErrorIs(t, err, repository.ErrTeamNotFound)
+	teams, err := s.ListTeams(globex, domains.TeamFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, teams.Teams, 1)
+	require.Equal(t, "backend", teams.Teams[0].Name)
+
+	_, err = s.GetUserByID(globex, "u6")
+	require.ErrorIs(t, err, repository.ErrUserNotFound)
+	_, err = s.SetUserStatus(globex, "u6", false)
+	require.ErrorIs(t, err, repository.ErrUserNotFound)
+	_, err = s.SoftDeleteUser(globex, "u6")
+	require.ErrorIs(t, err, repository.ErrUserNotFound)
+	users, err := s.SearchUsers(globex, domains.UserFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, users.Users, 4)
+	bob, err := s.GetUserByID(globex, "u2")
+	require.NoError(t, err)
+	require.True(t, bob.IsActive)
+
+	_, err = s.GetPullRequestByID(globex, "pr2")
+	require.ErrorIs(t, err, repository.ErrPRNotFound)
+	require.ErrorIs(t, s.MergePullRequest(globex, "pr2", 0), repository.ErrPRNotFound)
+	_, err = s.ReassignReviewer(globex, "pr2", "u7", 0)
+	require.ErrorIs(t, err, repository.ErrPRNotFound)
+	prs, err := s.ListPullRequests(globex, domains.PullRequestFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, prs.PullRequests, 1)
+	require.Equal(t, domains.PRStatusOpen, prs.PullRequests[0].Status)
+	reviews, err := s.UsersReview(globex, "u7", domains.ReviewFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Empty(t, reviews.Reviews)
+
+	record, err = s.ReserveIdempotencyKey(globex, "POST /x", "k", "other", time.Hour)
+	require.NoError(t, err)
+	require.Nil(t, record, "keys are scoped by organization")
+
+	pruned, _, err := s.PruneMergedPullRequests(globex, time.Now().Add(time.Hour), domains.RetentionDelete, 10)
+	require.NoError(t, err)
+	require.Zero(t, pruned)
+	merged, err := s.GetPullRequestByID(acme, "pr1")
+	require.NoError(t, err)
+	require.Equal(t, domains.PRStatusMerged, merged.Status)
+
+	snap, err := s.ExportSnapshot(globex)
+	require.NoError(t, err)
+	require.Equal(t, []string{"backend"}, snap.Teams)
+	require.Len(t, snap.PullRequests, 1)
+
+	// a snapshot restores into any empty organization
+	initech := tenant.WithOrg(context.Background(), "initech")
+	require.ErrorIs(t, s.RestoreSnapshot(acme, snap), repository.ErrStorageNotEmpty)
+	require.NoError(t, s.RestoreSnapshot(initech, snap))
+	restored, err := s.GetPullRequestByID(initech, "pr1")
+	require.NoError(t, err)
+	require.ElementsMatch(t, reviewerIDs(prs.PullRequests[0]), reviewerIDs(restored))
+}
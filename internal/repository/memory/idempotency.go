@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/lib/tenant"
+)
+
+type idempotencyKey struct {
+	org   string
+	scope string
+	key   string
+}
+
+type idempotencyEntry struct {
+	record      domains.IdempotencyRecord
+	lockedUntil time.Time
+	expiresAt   time.Time
+}
+
+// reclaimable reports whether the key may be claimed by a new request at t.
+func (e *idempotencyEntry) reclaimable(t time.Time) bool {
+	return !e.expiresAt.After(t) || (!e.record.Completed && !e.lockedUntil.After(t))
+}
+
+// ReserveIdempotencyKey claims key within scope for a new request and holds it for lease.
+// It returns nil when the caller now owns the key, or the record left by an earlier request
+// with the same key. Expired keys, and keys whose request let its lease run out without
+// completing, are reclaimed as if they never existed.
+func (s *Storage) ReserveIdempotencyKey(
+	ctx context.Context,
+	scope, key, requestHash string,
+	lease time.Duration,
+) (*domains.IdempotencyRecord, error) {
+	unlock := s.lock(ctx)
+	defer unlock()
+
+	k := idempotencyKey{org: tenant.Org(ctx), scope: scope, key: key}
+	if entry, ok := s.idempotency[k]; ok && !entry.reclaimable(now()) {
+		record := entry.record
+		record.Header = maps.Clone(entry.record.Header)
+		record.Body = append([]byte(nil), entry.record.Body...)
+		return &record, nil
+	}
+
+	s.idempotency[k] = &idempotencyEntry{
+		record:      domains.IdempotencyRecord{RequestHash: requestHash},
+		lockedUntil: now().Add(lease),
+		expiresAt:   now().Add(lease),
+	}
+
+	return nil, nil
+}
+
+// CompleteIdempotencyKey stores the response produced for a reserved key, to be replayed
+// for ttl.
+func (s *Storage) CompleteIdempotencyKey(
+	ctx context.Context,
+	scope, key string,
+	statusCode int,
+	header map[string]string,
+	body []byte,
+	ttl time.Duration,
+) error {
+	unlock := s.lock(ctx)
+	defer unlock()
+
+	if entry, ok := s.idempotency[idempotencyKey{org: tenant.Org(ctx), scope: scope, key: key}]; ok {
+		entry.record.Completed = true
+		entry.record.StatusCode = statusCode
+		entry.record.Header = maps.Clone(header)
+		entry.record.Body = append([]byte(nil), body...)
+		entry.expiresAt = now().Add(ttl)
+	}
+
+	return nil
+}
+
+// ReleaseIdempotencyKey forgets a reserved key so that the request may be retried with it.
+func (s *Storage) ReleaseIdempotencyKey(ctx context.Context, scope, key string) error {
+	unlock := s.lock(ctx)
+	defer unlock()
+
+	delete(s.idempotency, idempotencyKey{org: tenant.Org(ctx), scope: scope, key: key})
+
+	return nil
+}
+
+// PurgeIdempotencyKeys deletes expired keys and returns how many were removed.
+func (s *Storage) PurgeIdempotencyKeys(ctx context.Context) (int64, error) {
+	unlock := s.lock(ctx)
+	defer unlock()
+
+	var n int64
+	current := now()
+	for k, entry := range s.idempotency {
+		if !entry.expiresAt.After(current) {
+			delete(s.idempotency, k)
+			n++
+		}
+	}
+
+	return n, nil
+}
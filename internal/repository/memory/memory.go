@@ -0,0 +1,177 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/lib/tenant"
+	"github.com/Deymos01/pr-review-manager/internal/repository"
+)
+
+// Storage keeps the whole service state in process memory. It mirrors the semantics and
+// sentinel errors of the postgres Storage and is meant for demos and fast end-to-end tests.
+// Every call runs under one mutex, so operations are serialized.
+type Storage struct {
+	mu sync.Mutex
+	// orgs keeps separate tables for every organization, created on first use.
+	orgs        map[string]*state
+	idempotency map[idempotencyKey]*idempotencyEntry
+}
+
+func New() *Storage {
+	return &Storage{
+		orgs:        make(map[string]*state),
+		idempotency: make(map[idempotencyKey]*idempotencyEntry),
+	}
+}
+
+// orgState returns the tables of the organization ctx acts for. The caller must hold the lock.
+func (s *Storage) orgState(ctx context.Context) *state {
+	org := tenant.Org(ctx)
+	st, ok := s.orgs[org]
+	if !ok {
+		st = newState()
+		s.orgs[org] = st
+	}
+	return st
+}
+
+// state holds the tables; WithinTx clones it to roll back a failed unit of work.
+type state struct {
+	// teams maps a team name to its version
+	teams map[string]int64
+	users map[string]*domains.User
+	prs   map[string]*pullRequest
+	// archive keeps the pull requests moved out of prs by retention; entries never change
+	archive []archivedPullRequest
+	// retained counts the pull requests removed by retention per user
+	retained map[string]reviewTotals
+}
+
+type pullRequest struct {
+	id                string
+	name              string
+	description       string
+	labels            []string
+	authorID          string
+	status            domains.PRStatus
+	needMoreReviewers bool
+	createdAt         time.Time
+	mergedAt          *time.Time
+	version           int64
+	reviewers         []reviewer
+}
+
+type reviewer struct {
+	userID     string
+	assignedAt time.Time
+}
+
+type archivedPullRequest struct {
+	pr         *pullRequest
+	archivedAt time.Time
+}
+
+type reviewTotals struct {
+	authored int64
+	reviewed int64
+}
+
+func newState() *state {
+	return &state{
+		teams:    make(map[string]int64),
+		users:    make(map[string]*domains.User),
+		prs:      make(map[string]*pullRequest),
+		retained: make(map[string]reviewTotals),
+	}
+}
+
+func (st *state) clone() *state {
+	c := newState()
+	for name, version := range st.teams {
+		c.teams[name] = version
+	}
+	for id, user := range st.users {
+		c.users[id] = cloneUser(user)
+	}
+	for id, pr := range st.prs {
+		cp := *pr
+		cp.labels = append([]string{}, pr.labels...)
+		cp.reviewers = append([]reviewer(nil), pr.reviewers...)
+		if pr.mergedAt != nil {
+			mergedAt := *pr.mergedAt
+			cp.mergedAt = &mergedAt
+		}
+		c.prs[id] = &cp
+	}
+	c.archive = append(c.archive, st.archive...)
+	for id, totals := range st.retained {
+		c.retained[id] = totals
+	}
+	return c
+}
+
+func (pr *pullRequest) hasReviewer(userID string) bool {
+	for _, r := range pr.reviewers {
+		if r.userID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func (pr *pullRequest) removeReviewer(userID string) {
+	for i, r := range pr.reviewers {
+		if r.userID == userID {
+			pr.reviewers = append(pr.reviewers[:i], pr.reviewers[i+1:]...)
+			return
+		}
+	}
+}
+
+func (pr *pullRequest) reassignable() bool {
+	for _, status := range repository.ReassignableStatuses {
+		if pr.status == status {
+			return true
+		}
+	}
+	return false
+}
+
+func cloneUser(u *domains.User) *domains.User {
+	c := *u
+	if u.TeamName != nil {
+		teamName := *u.TeamName
+		c.TeamName = &teamName
+	}
+	if u.DeletedAt != nil {
+		deletedAt := *u.DeletedAt
+		c.DeletedAt = &deletedAt
+	}
+	return &c
+}
+
+// liveUser returns the user unless it is missing or deleted.
+func (st *state) liveUser(id string) (*domains.User, bool) {
+	user, ok := st.users[id]
+	if !ok || user.DeletedAt != nil {
+		return nil, false
+	}
+	return user, true
+}
+
+func inTeam(u *domains.User, teamName string) bool {
+	return u.TeamName != nil && *u.TeamName == teamName
+}
+
+// canReview reports whether u may be assigned as a reviewer in teamName.
+func canReview(u *domains.User, teamName string) bool {
+	return inTeam(u, teamName) && u.IsActive && u.Role != domains.RoleObserver
+}
+
+// now mirrors the database clock: timestamps are stored in UTC.
+func now() time.Time {
+	return time.Now().UTC()
+}
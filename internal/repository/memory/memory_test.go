@@ -0,0 +1,14 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/Deymos01/pr-review-manager/internal/repository/conformance"
+	"github.com/Deymos01/pr-review-manager/internal/repository/memory"
+)
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T) conformance.Storage {
+		return memory.New()
+	})
+}
@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/repository"
+)
+
+// ImportTeams applies the whole org chart at once using the same upsert semantics as
+// CreateTeam. With dryRun set the changes are made on a copy and only the report is returned.
+func (s *Storage) ImportTeams(ctx context.Context, teams []*domains.Team, dryRun bool) (*domains.ImportReport, error) {
+	const op = "storage.memory.ImportTeams"
+
+	unlock := s.lock(ctx)
+	defer unlock()
+
+	st := s.orgState(ctx)
+	for _, team := range teams {
+		if st.hasDeletedMembers(team) {
+			return nil, fmt.Errorf("%s: %w", op, repository.ErrUserDeleted)
+		}
+	}
+
+	if dryRun {
+		st = st.clone()
+	}
+
+	report := &domains.ImportReport{DryRun: dryRun}
+
+	// Snapshot of the users being imported, taken before any change
+	existing := make(map[string]*domains.User)
+	for _, team := range teams {
+		for _, member := range team.Members {
+			if user, ok := st.users[member.ID]; ok {
+				existing[member.ID] = cloneUser(user)
+			}
+		}
+	}
+
+	for _, team := range teams {
+		if _, ok := st.teams[team.Name]; !ok {
+			st.teams[team.Name] = 1
+			report.CreatedTeams = append(report.CreatedTeams, team.Name)
+		}
+
+		st.upsertTeamMembers(team)
+		st.teams[team.Name]++
+
+		var deactivated []string
+		for _, member := range team.Members {
+			after := &domains.User{
+				ID:       member.ID,
+				Name:     member.Name,
+				TeamName: &team.Name,
+				IsActive: member.IsActive,
+				Role:     member.Role,
+			}
+
+			before, ok := existing[member.ID]
+			if after.Role == "" {
+				after.Role = domains.RoleMember
+				if ok {
+					after.Role = before.Role
+				}
+			}
+
+			if ok && before.IsActive && !after.IsActive {
+				deactivated = append(deactivated, member.ID)
+			}
+
+			switch {
+			case !ok:
+				report.Created = append(report.Created, after)
+			case before.TeamName == nil || *before.TeamName != team.Name:
+				report.Moved = append(report.Moved, &domains.UserChange{Before: before, After: after})
+			case before.Name != after.Name || before.IsActive != after.IsActive || before.Role != after.Role:
+				report.Updated = append(report.Updated, &domains.UserChange{Before: before, After: after})
+			default:
+				report.Unchanged++
+			}
+		}
+
+		// deactivated members release their open reviews, as with /team/deactivate
+		if len(deactivated) == 0 {
+			continue
+		}
+		_, reassigned, err := st.deactivateTeamMembers(team.Name, deactivated, 0)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		report.Reassigned = append(report.Reassigned, reassigned...)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,372 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/repository"
+)
+
+// CreatePullRequest stores an open pull request and assigns reviewers from the author's team.
+func (s *Storage) CreatePullRequest(ctx context.Context, prID, prName, authorID string, requireLead bool) ([]string, error) {
+	const op = "repository.memory.CreatePullRequest"
+
+	unlock := s.lock(ctx)
+	defer unlock()
+	st := s.orgState(ctx)
+
+	if _, ok := st.prs[prID]; ok {
+		return nil, repository.ErrPRAlreadyExists
+	}
+
+	members, leads := st.reviewerCandidates(authorID)
+	if len(members) == 0 {
+		return nil, fmt.Errorf("%s: no active teammates found for author %s", op, authorID)
+	}
+
+	reviewers, err := repository.SelectReviewers(members, leads, requireLead)
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt := now()
+	pr := &pullRequest{
+		id:        prID,
+		name:      prName,
+		labels:    []string{},
+		authorID:  authorID,
+		status:    domains.PRStatusOpen,
+		createdAt: createdAt,
+		version:   1,
+	}
+	for _, reviewerID := range reviewers {
+		pr.reviewers = append(pr.reviewers, reviewer{userID: reviewerID, assignedAt: createdAt})
+	}
+	st.prs[prID] = pr
+
+	return reviewers, nil
+}
+
+// reviewerCandidates returns the author's active teammates that may review their pull
+// request; leads holds the subset with the lead role.
+func (st *state) reviewerCandidates(authorID string) (members, leads []string) {
+	author, ok := st.users[authorID]
+	if !ok || author.TeamName == nil {
+		return nil, nil
+	}
+
+	for _, user := range st.users {
+		// Observers are never assigned as reviewers
+		if user.ID == authorID || !canReview(user, *author.TeamName) {
+			continue
+		}
+		members = append(members, user.ID)
+	}
+	sort.Strings(members)
+
+	for _, id := range members {
+		if st.users[id].Role == domains.RoleLead {
+			leads = append(leads, id)
+		}
+	}
+
+	return members, leads
+}
+
+func (s *Storage) PullRequestExists(ctx context.Context, prID string) (bool, error) {
+	unlock := s.lock(ctx)
+	defer unlock()
+
+	_, ok := s.orgState(ctx).prs[prID]
+	return ok, nil
+}
+
+func (s *Storage) PullRequestMerged(ctx context.Context, prID string) (bool, error) {
+	const op = "repository.memory.PullRequestMerged"
+
+	unlock := s.lock(ctx)
+	defer unlock()
+
+	pr, ok := s.orgState(ctx).prs[prID]
+	if !ok {
+		return false, fmt.Errorf("%s: %w", op, repository.ErrPRNotFound)
+	}
+
+	return pr.status == domains.PRStatusMerged, nil
+}
+
+// MergePullRequest marks the pull request as merged. A non-zero ifVersion makes the merge
+// conditional on the stored version, as requested by an If-Match header.
+// Merging a merged pull request changes nothing.
+func (s *Storage) MergePullRequest(ctx context.Context, prID string, ifVersion int64) error {
+	unlock := s.lock(ctx)
+	defer unlock()
+
+	pr, ok := s.orgState(ctx).prs[prID]
+	if !ok {
+		return repository.ErrPRNotFound
+	}
+	if pr.status == domains.PRStatusMerged {
+		return nil
+	}
+	if ifVersion != 0 && pr.version != ifVersion {
+		return repository.ErrVersionConflict
+	}
+
+	mergedAt := now()
+	pr.status = domains.PRStatusMerged
+	pr.mergedAt = &mergedAt
+	pr.version++
+
+	return nil
+}
+
+// GetPullRequestByID loads the pull request with its author and reviewers.
+func (s *Storage) GetPullRequestByID(ctx context.Context, prID string) (*domains.PullRequest, error) {
+	unlock := s.lock(ctx)
+	defer unlock()
+	st := s.orgState(ctx)
+
+	pr, ok := st.prs[prID]
+	if !ok {
+		return nil, repository.ErrPRNotFound
+	}
+
+	return st.toDomain(pr), nil
+}
+
+// toDomain copies pr with its author and reviewers ordered by assignment time.
+func (st *state) toDomain(pr *pullRequest) *domains.PullRequest {
+	out := &domains.PullRequest{
+		ID:                pr.id,
+		Name:              pr.name,
+		Description:       pr.description,
+		Labels:            append([]string{}, pr.labels...),
+		Author:            st.user(pr.authorID),
+		Reviewers:         make([]*domains.Reviewer, 0, len(pr.reviewers)),
+		Status:            pr.status,
+		NeedMoreReviewers: pr.needMoreReviewers,
+		CreatedAt:         pr.createdAt,
+		Version:           pr.version,
+	}
+	if pr.mergedAt != nil {
+		mergedAt := *pr.mergedAt
+		out.MergedAt = &mergedAt
+	}
+
+	for _, r := range pr.reviewers {
+		out.Reviewers = append(out.Reviewers, &domains.Reviewer{User: st.user(r.userID), AssignedAt: r.assignedAt})
+	}
+	sort.Slice(out.Reviewers, func(i, j int) bool {
+		a, b := out.Reviewers[i], out.Reviewers[j]
+		if !a.AssignedAt.Equal(b.AssignedAt) {
+			return a.AssignedAt.Before(b.AssignedAt)
+		}
+		return a.User.ID < b.User.ID
+	})
+
+	return out
+}
+
+// user returns a copy of the user, or a stub carrying only the id if it is unknown.
+func (st *state) user(id string) *domains.User {
+	if user, ok := st.users[id]; ok {
+		return cloneUser(user)
+	}
+	return &domains.User{ID: id}
+}
+
+// ListPullRequests returns a page of pull requests ordered by creation time.
+func (s *Storage) ListPullRequests(ctx context.Context, filter domains.PullRequestFilter) (*domains.PullRequestPage, error) {
+	var cursorTime time.Time
+	if filter.After != nil {
+		var err error
+		cursorTime, err = time.Parse(time.RFC3339Nano, filter.After.Key)
+		if err != nil {
+			return nil, repository.ErrInvalidCursor
+		}
+	}
+
+	unlock := s.lock(ctx)
+	defer unlock()
+	st := s.orgState(ctx)
+
+	var prs []*pullRequest
+	for _, pr := range st.prs {
+		if st.matches(pr, filter) &&
+			(filter.After == nil || afterTime(pr.createdAt, pr.id, cursorTime, filter.After.ID, filter.Desc)) {
+			prs = append(prs, pr)
+		}
+	}
+	sort.Slice(prs, func(i, j int) bool {
+		return afterTime(prs[j].createdAt, prs[j].id, prs[i].createdAt, prs[i].id, filter.Desc)
+	})
+
+	page := &domains.PullRequestPage{PullRequests: make([]*domains.PullRequest, 0, filter.Limit)}
+	for _, pr := range prs {
+		if len(page.PullRequests) == filter.Limit {
+			last := page.PullRequests[len(page.PullRequests)-1]
+			page.Next = &domains.Cursor{Key: last.CreatedAt.Format(time.RFC3339Nano), ID: last.ID}
+			break
+		}
+		page.PullRequests = append(page.PullRequests, st.toDomain(pr))
+	}
+
+	return page, nil
+}
+
+func (st *state) matches(pr *pullRequest, filter domains.PullRequestFilter) bool {
+	if filter.Status != "" && pr.status != filter.Status {
+		return false
+	}
+	if filter.TeamName != "" {
+		author, ok := st.users[pr.authorID]
+		if !ok || !inTeam(author, filter.TeamName) {
+			return false
+		}
+	}
+	if filter.AuthorID != "" && pr.authorID != filter.AuthorID {
+		return false
+	}
+	if filter.ReviewerID != "" && !pr.hasReviewer(filter.ReviewerID) {
+		return false
+	}
+	if filter.CreatedFrom != nil && pr.createdAt.Before(*filter.CreatedFrom) {
+		return false
+	}
+	if filter.CreatedTo != nil && !pr.createdAt.Before(*filter.CreatedTo) {
+		return false
+	}
+	if filter.MergedFrom != nil && (pr.mergedAt == nil || pr.mergedAt.Before(*filter.MergedFrom)) {
+		return false
+	}
+	if filter.MergedTo != nil && (pr.mergedAt == nil || !pr.mergedAt.Before(*filter.MergedTo)) {
+		return false
+	}
+	if filter.NeedMoreReviewers != nil && pr.needMoreReviewers != *filter.NeedMoreReviewers {
+		return false
+	}
+	return true
+}
+
+// ReassignReviewer replaces oldUserID with a random eligible member of their team.
+// A non-zero ifVersion must match the stored version.
+func (s *Storage) ReassignReviewer(ctx context.Context, prID, oldUserID string, ifVersion int64) (string, error) {
+	unlock := s.lock(ctx)
+	defer unlock()
+
+	st := s.orgState(ctx)
+
+	pr, ok := st.prs[prID]
+	if !ok {
+		return "", repository.ErrPRNotFound
+	}
+	if ifVersion != 0 && pr.version != ifVersion {
+		return "", repository.ErrVersionConflict
+	}
+	if pr.status == domains.PRStatusMerged {
+		return "", repository.ErrPRMerged
+	}
+
+	newUserID, err := st.replacementReviewer(pr, oldUserID, pr.authorID)
+	if err != nil {
+		return "", err
+	}
+
+	for i := range pr.reviewers {
+		if pr.reviewers[i].userID == oldUserID {
+			pr.reviewers[i] = reviewer{userID: newUserID, assignedAt: now()}
+		}
+	}
+	pr.version++
+
+	return newUserID, nil
+}
+
+// replacementReviewer returns a random active teammate of userID that is not authorID and
+// does not review pr yet. It fails with ErrNoCandidate when there is none.
+func (st *state) replacementReviewer(pr *pullRequest, userID, authorID string) (string, error) {
+	user, ok := st.users[userID]
+	if !ok || user.TeamName == nil {
+		return "", repository.ErrNoCandidate
+	}
+
+	var candidates []string
+	for _, u := range st.users {
+		if u.ID != userID && u.ID != authorID && canReview(u, *user.TeamName) && !pr.hasReviewer(u.ID) {
+			candidates = append(candidates, u.ID)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", repository.ErrNoCandidate
+	}
+	sort.Strings(candidates)
+
+	return candidates[rand.Intn(len(candidates))], nil
+}
+
+// UpdatePullRequest applies upd to an open pull request if upd.Version matches the stored
+// version. When the PR moves to an author who reviews it, their review goes to a teammate of
+// the new author; the other reviewers are kept.
+func (s *Storage) UpdatePullRequest(ctx context.Context, prID string, upd domains.PullRequestUpdate) error {
+	unlock := s.lock(ctx)
+	defer unlock()
+	st := s.orgState(ctx)
+
+	pr, ok := st.prs[prID]
+	if !ok {
+		return repository.ErrPRNotFound
+	}
+	if pr.version != upd.Version {
+		return repository.ErrVersionConflict
+	}
+	if pr.status == domains.PRStatusMerged {
+		return repository.ErrPRMerged
+	}
+
+	// The new author must never review their own PR. Only their review is handed over; the
+	// other reviewers keep their assignment. Without a free teammate the review is dropped
+	// and the PR waits for another reviewer.
+	authorChanged := upd.AuthorID != nil && *upd.AuthorID != pr.authorID
+	dropReview := authorChanged && pr.hasReviewer(*upd.AuthorID)
+	var replacement string
+	if dropReview {
+		var err error
+		replacement, err = st.replacementReviewer(pr, *upd.AuthorID, *upd.AuthorID)
+		if err != nil && !errors.Is(err, repository.ErrNoCandidate) {
+			return err
+		}
+	}
+
+	if upd.Name != nil {
+		pr.name = *upd.Name
+	}
+	if upd.Description != nil {
+		pr.description = *upd.Description
+	}
+	if upd.Labels != nil {
+		pr.labels = append([]string{}, *upd.Labels...)
+	}
+	if authorChanged {
+		pr.authorID = *upd.AuthorID
+	}
+	switch {
+	case replacement != "":
+		for i := range pr.reviewers {
+			if pr.reviewers[i].userID == *upd.AuthorID {
+				pr.reviewers[i] = reviewer{userID: replacement, assignedAt: now()}
+			}
+		}
+	case dropReview:
+		pr.removeReviewer(*upd.AuthorID)
+		pr.needMoreReviewers = true
+	}
+	pr.version++
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"sort"
+	"time"
+)
+
+// after reports whether key comes after cursor in the listing order.
+func after(key, cursor string, desc bool) bool {
+	if desc {
+		return key < cursor
+	}
+	return key > cursor
+}
+
+// afterPair is after for a keyset of a sort value with the id breaking ties.
+func afterPair(key, id, cursorKey, cursorID string, desc bool) bool {
+	if key != cursorKey {
+		return after(key, cursorKey, desc)
+	}
+	return after(id, cursorID, desc)
+}
+
+// afterTime is afterPair for keysets ordered by a timestamp.
+func afterTime(key time.Time, id string, cursorKey time.Time, cursorID string, desc bool) bool {
+	if !key.Equal(cursorKey) {
+		if desc {
+			return key.Before(cursorKey)
+		}
+		return key.After(cursorKey)
+	}
+	return after(id, cursorID, desc)
+}
+
+// sortedPRs returns the pull requests ordered by id, so that iteration is deterministic.
+func (st *state) sortedPRs() []*pullRequest {
+	prs := make([]*pullRequest, 0, len(st.prs))
+	for _, pr := range st.prs {
+		prs = append(prs, pr)
+	}
+	sort.Slice(prs, func(i, j int) bool { return prs[i].id < prs[j].id })
+
+	return prs
+}
@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+)
+
+// PruneMergedPullRequests removes up to limit pull requests merged before mergedBefore,
+// oldest first, and reports how many pull requests and reviews it removed. Their authors
+// and reviewers keep the counts; in archive mode the pull requests are kept in the archive.
+func (s *Storage) PruneMergedPullRequests(
+	ctx context.Context,
+	mergedBefore time.Time,
+	mode domains.RetentionMode,
+	limit int,
+) (int, int, error) {
+	unlock := s.lock(ctx)
+	defer unlock()
+	st := s.orgState(ctx)
+
+	var expired []*pullRequest
+	for _, pr := range st.prs {
+		if pr.status == domains.PRStatusMerged && pr.mergedAt != nil && pr.mergedAt.Before(mergedBefore) {
+			expired = append(expired, pr)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool {
+		if !expired[i].mergedAt.Equal(*expired[j].mergedAt) {
+			return expired[i].mergedAt.Before(*expired[j].mergedAt)
+		}
+		return expired[i].id < expired[j].id
+	})
+	if len(expired) > limit {
+		expired = expired[:limit]
+	}
+
+	archivedAt := now()
+	reviews := 0
+	for _, pr := range expired {
+		totals := st.retained[pr.authorID]
+		totals.authored++
+		st.retained[pr.authorID] = totals
+
+		for _, r := range pr.reviewers {
+			totals := st.retained[r.userID]
+			totals.reviewed++
+			st.retained[r.userID] = totals
+		}
+		reviews += len(pr.reviewers)
+
+		if mode == domains.RetentionArchive {
+			st.archive = append(st.archive, archivedPullRequest{pr: pr, archivedAt: archivedAt})
+		}
+		delete(st.prs, pr.id)
+	}
+
+	return len(expired), reviews, nil
+}
+
+// GetReviewStats counts the pull requests the user authored and reviewed, adding those
+// already removed by retention.
+func (s *Storage) GetReviewStats(ctx context.Context, userID string) (*domains.ReviewStats, error) {
+	unlock := s.lock(ctx)
+	defer unlock()
+	st := s.orgState(ctx)
+
+	totals := st.retained[userID]
+	stats := &domains.ReviewStats{
+		UserID:   userID,
+		Authored: totals.authored,
+		Reviewed: totals.reviewed,
+	}
+	for _, pr := range st.prs {
+		if pr.authorID == userID {
+			stats.Authored++
+		}
+		if pr.hasReviewer(userID) {
+			stats.Reviewed++
+		}
+	}
+
+	return stats, nil
+}
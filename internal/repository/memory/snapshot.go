@@ -0,0 +1,202 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/lib/tenant"
+	"github.com/Deymos01/pr-review-manager/internal/repository"
+)
+
+// ExportSnapshot copies the whole state, including the archive and the review stats, under
+// the storage lock so the snapshot is consistent.
+func (s *Storage) ExportSnapshot(ctx context.Context) (*domains.Snapshot, error) {
+	unlock := s.lock(ctx)
+	defer unlock()
+	st := s.orgState(ctx)
+
+	snap := &domains.Snapshot{
+		CreatedAt: now(),
+		Statuses:  domains.PRStatuses,
+	}
+
+	for name := range st.teams {
+		snap.Teams = append(snap.Teams, name)
+	}
+	sort.Strings(snap.Teams)
+
+	for _, user := range st.users {
+		snap.Users = append(snap.Users, cloneUser(user))
+	}
+	sort.Slice(snap.Users, func(i, j int) bool { return snap.Users[i].ID < snap.Users[j].ID })
+
+	for _, pr := range st.sortedPRs() {
+		snap.PullRequests = append(snap.PullRequests, snapshotPullRequest(pr))
+	}
+
+	for _, archived := range st.archive {
+		snap.ArchivedPullRequests = append(snap.ArchivedPullRequests, &domains.ArchivedPullRequest{
+			PullRequest: snapshotPullRequest(archived.pr),
+			ArchivedAt:  archived.archivedAt,
+		})
+	}
+
+	for userID, totals := range st.retained {
+		snap.ReviewStats = append(snap.ReviewStats, &domains.ReviewStats{
+			UserID:   userID,
+			Authored: totals.authored,
+			Reviewed: totals.reviewed,
+		})
+	}
+	sort.Slice(snap.ReviewStats, func(i, j int) bool { return snap.ReviewStats[i].UserID < snap.ReviewStats[j].UserID })
+
+	return snap, nil
+}
+
+// snapshotPullRequest copies pr with its reviewers in assignment order.
+func snapshotPullRequest(pr *pullRequest) *domains.PullRequest {
+	out := &domains.PullRequest{
+		ID:                pr.id,
+		Name:              pr.name,
+		Description:       pr.description,
+		Labels:            append([]string{}, pr.labels...),
+		Author:            &domains.User{ID: pr.authorID},
+		Status:            pr.status,
+		NeedMoreReviewers: pr.needMoreReviewers,
+		CreatedAt:         pr.createdAt,
+	}
+	if pr.mergedAt != nil {
+		mergedAt := *pr.mergedAt
+		out.MergedAt = &mergedAt
+	}
+	for _, r := range pr.reviewers {
+		out.Reviewers = append(out.Reviewers, &domains.Reviewer{
+			User:       &domains.User{ID: r.userID},
+			AssignedAt: r.assignedAt,
+		})
+	}
+	sort.Slice(out.Reviewers, func(i, j int) bool {
+		a, b := out.Reviewers[i], out.Reviewers[j]
+		if !a.AssignedAt.Equal(b.AssignedAt) {
+			return a.AssignedAt.Before(b.AssignedAt)
+		}
+		return a.User.ID < b.User.ID
+	})
+	return out
+}
+
+// RestoreSnapshot loads the snapshot, including the archive and the review stats, into an
+// empty storage at once.
+// It returns repository.ErrStorageNotEmpty if any team, user or pull request already exists.
+// References between the entities are checked like foreign keys are in the database.
+func (s *Storage) RestoreSnapshot(ctx context.Context, snap *domains.Snapshot) error {
+	const op = "repository.memory.RestoreSnapshot"
+
+	unlock := s.lock(ctx)
+	defer unlock()
+
+	if current := s.orgState(ctx); len(current.teams) > 0 || len(current.users) > 0 || len(current.prs) > 0 ||
+		len(current.archive) > 0 {
+		return repository.ErrStorageNotEmpty
+	}
+
+	st := newState()
+
+	for _, team := range snap.Teams {
+		if _, ok := st.teams[team]; ok {
+			return fmt.Errorf("%s: duplicate team %s", op, team)
+		}
+		st.teams[team] = 1
+	}
+
+	for _, user := range snap.Users {
+		if _, ok := st.users[user.ID]; ok {
+			return fmt.Errorf("%s: duplicate user %s", op, user.ID)
+		}
+		if user.TeamName != nil {
+			if _, ok := st.teams[*user.TeamName]; !ok {
+				return fmt.Errorf("%s: user %s references unknown team %s", op, user.ID, *user.TeamName)
+			}
+		}
+		st.users[user.ID] = cloneUser(user)
+	}
+
+	restoredAt := now()
+	for _, pr := range snap.PullRequests {
+		if _, ok := st.prs[pr.ID]; ok {
+			return fmt.Errorf("%s: duplicate pull request %s", op, pr.ID)
+		}
+
+		restored, err := st.restorePullRequest(pr, restoredAt)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		st.prs[pr.ID] = restored
+	}
+
+	for _, archived := range snap.ArchivedPullRequests {
+		restored, err := st.restorePullRequest(archived.PullRequest, restoredAt)
+		if err != nil {
+			return fmt.Errorf("%s: archive: %w", op, err)
+		}
+		st.archive = append(st.archive, archivedPullRequest{pr: restored, archivedAt: archived.ArchivedAt})
+	}
+
+	for _, stats := range snap.ReviewStats {
+		if _, ok := st.users[stats.UserID]; !ok {
+			return fmt.Errorf("%s: review stats reference unknown user %s", op, stats.UserID)
+		}
+		if _, ok := st.retained[stats.UserID]; ok {
+			return fmt.Errorf("%s: duplicate review stats of user %s", op, stats.UserID)
+		}
+		st.retained[stats.UserID] = reviewTotals{authored: stats.Authored, reviewed: stats.Reviewed}
+	}
+
+	s.orgs[tenant.Org(ctx)] = st
+
+	return nil
+}
+
+// restorePullRequest converts a pull request of a snapshot, checking that its author and
+// reviewers exist. Reviewers without an assignment time get restoredAt.
+func (st *state) restorePullRequest(pr *domains.PullRequest, restoredAt time.Time) (*pullRequest, error) {
+	if _, ok := st.users[pr.Author.ID]; !ok {
+		return nil, fmt.Errorf("pull request %s references unknown author %s", pr.ID, pr.Author.ID)
+	}
+
+	restored := &pullRequest{
+		id:                pr.ID,
+		name:              pr.Name,
+		description:       pr.Description,
+		labels:            append([]string{}, pr.Labels...),
+		authorID:          pr.Author.ID,
+		status:            pr.Status,
+		needMoreReviewers: pr.NeedMoreReviewers,
+		createdAt:         pr.CreatedAt,
+		version:           1,
+	}
+	if pr.MergedAt != nil {
+		mergedAt := *pr.MergedAt
+		restored.mergedAt = &mergedAt
+	}
+
+	for _, r := range pr.Reviewers {
+		if _, ok := st.users[r.User.ID]; !ok {
+			return nil, fmt.Errorf("pull request %s references unknown reviewer %s", pr.ID, r.User.ID)
+		}
+		if restored.hasReviewer(r.User.ID) {
+			return nil, fmt.Errorf("duplicate reviewer %s of pull request %s", r.User.ID, pr.ID)
+		}
+
+		assignedAt := r.AssignedAt
+		if assignedAt.IsZero() {
+			assignedAt = restoredAt
+		}
+		restored.reviewers = append(restored.reviewers, reviewer{userID: r.User.ID, assignedAt: assignedAt})
+	}
+
+	return restored, nil
+}
@@ -0,0 +1,264 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/repository"
+)
+
+func (s *Storage) CreateTeam(ctx context.Context, team *domains.Team) error {
+	const op = "storage.memory.CreateTeam"
+
+	unlock := s.lock(ctx)
+	defer unlock()
+	st := s.orgState(ctx)
+
+	if _, ok := st.teams[team.Name]; ok {
+		return fmt.Errorf("%s: team %s already exists", op, team.Name)
+	}
+	if st.hasDeletedMembers(team) {
+		return fmt.Errorf("%s: %w", op, repository.ErrUserDeleted)
+	}
+
+	st.teams[team.Name] = 1
+	st.upsertTeamMembers(team)
+
+	return nil
+}
+
+// hasDeletedMembers reports whether the team lists a deleted user: IDs of offboarded
+// users stay retired.
+func (st *state) hasDeletedMembers(team *domains.Team) bool {
+	for _, member := range team.Members {
+		if user, ok := st.users[member.ID]; ok && user.DeletedAt != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// upsertTeamMembers creates the team members or moves existing users into the team,
+// overwriting their name, activity and role. An omitted role keeps the current one, so
+// re-posting a team does not demote its leads. Teams losing members get their version bumped.
+func (st *state) upsertTeamMembers(team *domains.Team) {
+	for _, member := range team.Members {
+		if existing, ok := st.users[member.ID]; ok && existing.TeamName != nil && *existing.TeamName != team.Name {
+			st.teams[*existing.TeamName]++
+		}
+	}
+
+	for _, member := range team.Members {
+		role := member.Role
+		if role == "" {
+			role = domains.RoleMember
+			if existing, ok := st.users[member.ID]; ok {
+				role = existing.Role
+			}
+		}
+
+		teamName := team.Name
+		st.users[member.ID] = &domains.User{
+			ID:       member.ID,
+			Name:     member.Name,
+			TeamName: &teamName,
+			IsActive: member.IsActive,
+			Role:     role,
+		}
+	}
+}
+
+func (s *Storage) TeamExists(ctx context.Context, name string) (bool, error) {
+	unlock := s.lock(ctx)
+	defer unlock()
+
+	_, ok := s.orgState(ctx).teams[name]
+	return ok, nil
+}
+
+func (s *Storage) UserIsTeamLead(ctx context.Context, userID, teamName string) (bool, error) {
+	unlock := s.lock(ctx)
+	defer unlock()
+
+	user, ok := s.orgState(ctx).users[userID]
+	if !ok {
+		return false, nil
+	}
+
+	return inTeam(user, teamName) && user.Role == domains.RoleLead && user.IsActive, nil
+}
+
+func (s *Storage) GetTeamByName(ctx context.Context, name string) (*domains.Team, error) {
+	unlock := s.lock(ctx)
+	defer unlock()
+	st := s.orgState(ctx)
+
+	version, ok := st.teams[name]
+	if !ok {
+		return nil, repository.ErrTeamNotFound
+	}
+
+	return &domains.Team{Name: name, Members: st.teamMembers(name), Version: version}, nil
+}
+
+// teamMembers returns copies of the team members ordered by id.
+func (st *state) teamMembers(teamName string) []*domains.User {
+	var members []*domains.User
+	for _, user := range st.users {
+		if inTeam(user, teamName) {
+			members = append(members, cloneUser(user))
+		}
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].ID < members[j].ID })
+
+	return members
+}
+
+// DeactivateTeamMembers deactivates the given members and moves their open reviews to the
+// remaining active members. A non-zero ifVersion must match the stored team version.
+func (s *Storage) DeactivateTeamMembers(
+	ctx context.Context,
+	teamName string,
+	userIDs []string,
+	ifVersion int64,
+) (*domains.Team, []*domains.ReassignedPR, error) {
+	unlock := s.lock(ctx)
+	defer unlock()
+
+	return s.orgState(ctx).deactivateTeamMembers(teamName, userIDs, ifVersion)
+}
+
+func (st *state) deactivateTeamMembers(
+	teamName string,
+	userIDs []string,
+	ifVersion int64,
+) (*domains.Team, []*domains.ReassignedPR, error) {
+	version, ok := st.teams[teamName]
+	if !ok {
+		return nil, nil, repository.ErrTeamNotFound
+	}
+	if ifVersion != 0 && version != ifVersion {
+		return nil, nil, repository.ErrVersionConflict
+	}
+
+	// Ensure all users belong to the team
+	deactivated := make(map[string]struct{}, len(userIDs))
+	for _, id := range userIDs {
+		if user, ok := st.users[id]; ok && inTeam(user, teamName) {
+			deactivated[id] = struct{}{}
+		}
+	}
+	if len(deactivated) != len(userIDs) {
+		return nil, nil, repository.ErrTeamCompatibility
+	}
+
+	for id := range deactivated {
+		st.users[id].IsActive = false
+	}
+
+	// Available candidates for reassignment (observers are never assigned)
+	var activeMembers []string
+	for _, user := range st.users {
+		if canReview(user, teamName) {
+			activeMembers = append(activeMembers, user.ID)
+		}
+	}
+	sort.Strings(activeMembers)
+
+	var reassigned []*domains.ReassignedPR
+
+	// Merged PRs keep their historical reviewers
+	for _, pr := range st.sortedPRs() {
+		if !pr.reassignable() {
+			continue
+		}
+
+		for _, id := range userIDs {
+			if !pr.hasReviewer(id) {
+				continue
+			}
+			pr.version++
+
+			// New reviewer should not be the PR author or an existing reviewer
+			candidates := make([]string, 0, len(activeMembers))
+			for _, member := range activeMembers {
+				if member != pr.authorID && !pr.hasReviewer(member) {
+					candidates = append(candidates, member)
+				}
+			}
+
+			pr.removeReviewer(id)
+			if len(candidates) == 0 {
+				// no suitable candidates, just remove reviewer
+				continue
+			}
+
+			newReviewer := candidates[rand.Intn(len(candidates))]
+			pr.reviewers = append(pr.reviewers, reviewer{userID: newReviewer, assignedAt: now()})
+
+			reassigned = append(reassigned, &domains.ReassignedPR{
+				PrID:      pr.id,
+				OldUserID: id,
+				NewUserID: newReviewer,
+			})
+		}
+	}
+
+	st.teams[teamName]++
+
+	return &domains.Team{Name: teamName, Members: st.teamMembers(teamName), Version: version + 1}, reassigned, nil
+}
+
+func (s *Storage) ListTeams(ctx context.Context, filter domains.TeamFilter) (*domains.TeamPage, error) {
+	unlock := s.lock(ctx)
+	defer unlock()
+	st := s.orgState(ctx)
+
+	prefix := strings.ToLower(filter.NamePrefix)
+
+	names := make([]string, 0, len(st.teams))
+	for name := range st.teams {
+		if !strings.HasPrefix(strings.ToLower(name), prefix) {
+			continue
+		}
+		if filter.After != nil && !after(name, filter.After.Key, filter.Desc) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return after(names[j], names[i], filter.Desc) })
+
+	page := &domains.TeamPage{Teams: make([]*domains.TeamSummary, 0, filter.Limit)}
+	for _, name := range names {
+		summary := &domains.TeamSummary{Name: name}
+		for _, user := range st.users {
+			if !inTeam(user, name) {
+				continue
+			}
+			summary.MembersCount++
+			if user.IsActive {
+				summary.ActiveMembers++
+			}
+		}
+		if filter.HasActive != nil && (summary.ActiveMembers > 0) != *filter.HasActive {
+			continue
+		}
+
+		page.Teams = append(page.Teams, summary)
+		if len(page.Teams) > filter.Limit {
+			break
+		}
+	}
+
+	if len(page.Teams) > filter.Limit {
+		page.Teams = page.Teams[:filter.Limit]
+		last := page.Teams[len(page.Teams)-1].Name
+		page.Next = &domains.Cursor{Key: last, ID: last}
+	}
+
+	return page, nil
+}
@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Deymos01/pr-review-manager/internal/lib/tenant"
+)
+
+type txKey struct{}
+
+// WithinTx runs fn with the storage locked: every Storage call made with the context passed
+// to fn joins the unit of work, and all changes are rolled back if fn returns an error.
+// Nested calls join the outer unit of work. Only the organization of ctx is rolled back.
+func (s *Storage) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if s.inTx(ctx) {
+		return fn(ctx)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	org := tenant.Org(ctx)
+	backup := s.orgState(ctx).clone()
+	if err := fn(context.WithValue(ctx, txKey{}, s)); err != nil {
+		s.orgs[org] = backup
+		return err
+	}
+
+	return nil
+}
+
+// lock acquires the storage mutex unless ctx already holds it through WithinTx,
+// and returns the matching unlock function.
+func (s *Storage) lock(ctx context.Context) func() {
+	if s.inTx(ctx) {
+		return func() {}
+	}
+
+	s.mu.Lock()
+	return s.mu.Unlock
+}
+
+func (s *Storage) inTx(ctx context.Context) bool {
+	owner, ok := ctx.Value(txKey{}).(*Storage)
+	return ok && owner == s
+}
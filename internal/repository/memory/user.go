@@ -0,0 +1,338 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/repository"
+)
+
+func (s *Storage) UserExists(ctx context.Context, userID string) (bool, error) {
+	unlock := s.lock(ctx)
+	defer unlock()
+
+	_, ok := s.orgState(ctx).liveUser(userID)
+	return ok, nil
+}
+
+func (s *Storage) UserHasActiveTeam(ctx context.Context, userID string) (bool, error) {
+	unlock := s.lock(ctx)
+	defer unlock()
+
+	user, ok := s.orgState(ctx).users[userID]
+	return ok && user.TeamName != nil, nil
+}
+
+func (s *Storage) GetUserByID(ctx context.Context, userID string) (*domains.User, error) {
+	unlock := s.lock(ctx)
+	defer unlock()
+
+	user, ok := s.orgState(ctx).liveUser(userID)
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+
+	return cloneUser(user), nil
+}
+
+func (s *Storage) SetUserStatus(ctx context.Context, userID string, isActive bool) (*domains.User, error) {
+	const op = "repository.memory.user.SetUserIsActive"
+
+	unlock := s.lock(ctx)
+	defer unlock()
+	st := s.orgState(ctx)
+
+	user, ok := st.liveUser(userID)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, repository.ErrUserNotFound)
+	}
+
+	user.IsActive = isActive
+	if user.TeamName != nil {
+		st.teams[*user.TeamName]++
+	}
+
+	return cloneUser(user), nil
+}
+
+// UsersReview returns a page of the user's review assignments ordered by assigned_at.
+func (s *Storage) UsersReview(ctx context.Context, userID string, filter domains.ReviewFilter) (*domains.ReviewPage, error) {
+	var cursorTime time.Time
+	if filter.After != nil {
+		var err error
+		cursorTime, err = time.Parse(time.RFC3339Nano, filter.After.Key)
+		if err != nil {
+			return nil, repository.ErrInvalidCursor
+		}
+	}
+
+	unlock := s.lock(ctx)
+	defer unlock()
+
+	var reviews []*domains.Review
+	for _, pr := range s.orgState(ctx).prs {
+		for _, r := range pr.reviewers {
+			if r.userID != userID {
+				continue
+			}
+			if filter.Status != "" && pr.status != filter.Status {
+				continue
+			}
+			if filter.Since != nil && r.assignedAt.Before(*filter.Since) {
+				continue
+			}
+			if filter.AuthorID != "" && pr.authorID != filter.AuthorID {
+				continue
+			}
+			if filter.After != nil && !afterTime(r.assignedAt, pr.id, cursorTime, filter.After.ID, filter.Desc) {
+				continue
+			}
+
+			review := &domains.Review{
+				PullRequest: &domains.PullRequest{
+					ID:        pr.id,
+					Name:      pr.name,
+					Author:    &domains.User{ID: pr.authorID},
+					Status:    pr.status,
+					CreatedAt: pr.createdAt,
+				},
+				AssignedAt: r.assignedAt,
+			}
+			if pr.mergedAt != nil {
+				mergedAt := *pr.mergedAt
+				review.PullRequest.MergedAt = &mergedAt
+			}
+			reviews = append(reviews, review)
+		}
+	}
+	sort.Slice(reviews, func(i, j int) bool {
+		a, b := reviews[i], reviews[j]
+		return afterTime(b.AssignedAt, b.PullRequest.ID, a.AssignedAt, a.PullRequest.ID, filter.Desc)
+	})
+
+	page := &domains.ReviewPage{Reviews: make([]*domains.Review, 0, filter.Limit)}
+	if len(reviews) > filter.Limit {
+		reviews = reviews[:filter.Limit]
+		last := reviews[len(reviews)-1]
+		page.Next = &domains.Cursor{
+			Key: last.AssignedAt.Format(time.RFC3339Nano),
+			ID:  last.PullRequest.ID,
+		}
+	}
+	page.Reviews = append(page.Reviews, reviews...)
+
+	return page, nil
+}
+
+func (s *Storage) UserAssigned(ctx context.Context, prID, userID string) (bool, error) {
+	unlock := s.lock(ctx)
+	defer unlock()
+
+	pr, ok := s.orgState(ctx).prs[prID]
+	return ok && pr.hasReviewer(userID), nil
+}
+
+// RebalanceReviews moves a fair share of open review assignments from the most loaded
+// active teammates of userID to userID itself. Each move is returned as a reassignment.
+func (s *Storage) RebalanceReviews(ctx context.Context, userID string) ([]*domains.ReassignedPR, error) {
+	unlock := s.lock(ctx)
+	defer unlock()
+
+	st := s.orgState(ctx)
+
+	user, ok := st.users[userID]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+
+	// Observers never review, and users outside of a team have nobody to take load from
+	if user.TeamName == nil || !user.IsActive || user.Role == domains.RoleObserver {
+		return nil, nil
+	}
+	teamName := *user.TeamName
+
+	load := make(map[string]int)
+	for _, member := range st.users {
+		if canReview(member, teamName) {
+			load[member.ID] = 0
+		}
+	}
+
+	type assignment struct {
+		reviewerID string
+		pr         *pullRequest
+		assignedAt time.Time
+	}
+
+	// Open assignments of the team, newest first: those are the least likely to be in progress
+	var open []assignment
+	for _, pr := range st.prs {
+		if !pr.reassignable() {
+			continue
+		}
+		for _, r := range pr.reviewers {
+			if _, ok := load[r.userID]; ok {
+				open = append(open, assignment{reviewerID: r.userID, pr: pr, assignedAt: r.assignedAt})
+			}
+		}
+	}
+	sort.Slice(open, func(i, j int) bool {
+		if !open[i].assignedAt.Equal(open[j].assignedAt) {
+			return open[i].assignedAt.After(open[j].assignedAt)
+		}
+		return open[i].pr.id < open[j].pr.id
+	})
+
+	assignments := make(map[string][]*pullRequest)
+	for _, a := range open {
+		assignments[a.reviewerID] = append(assignments[a.reviewerID], a.pr)
+		load[a.reviewerID]++
+	}
+
+	fairShare := len(open) / len(load)
+
+	donors := make([]string, 0, len(load))
+	for id := range load {
+		if id != userID {
+			donors = append(donors, id)
+		}
+	}
+
+	var reassigned []*domains.ReassignedPR
+	for load[userID] < fairShare {
+		// the most loaded teammate gives away first
+		sort.Slice(donors, func(i, j int) bool {
+			if load[donors[i]] != load[donors[j]] {
+				return load[donors[i]] > load[donors[j]]
+			}
+			return donors[i] < donors[j]
+		})
+
+		moved := false
+		for _, donor := range donors {
+			// moving from a teammate not loaded more than the user would not make things fairer
+			if load[donor] <= load[userID]+1 {
+				break
+			}
+
+			for i, pr := range assignments[donor] {
+				if pr.hasReviewer(userID) || pr.authorID == userID {
+					continue
+				}
+
+				for k := range pr.reviewers {
+					if pr.reviewers[k].userID == donor {
+						pr.reviewers[k] = reviewer{userID: userID, assignedAt: now()}
+					}
+				}
+				pr.version++
+
+				assignments[donor] = append(assignments[donor][:i], assignments[donor][i+1:]...)
+				load[donor]--
+				load[userID]++
+
+				reassigned = append(reassigned, &domains.ReassignedPR{
+					PrID:      pr.id,
+					OldUserID: donor,
+					NewUserID: userID,
+				})
+				moved = true
+				break
+			}
+			if moved {
+				break
+			}
+		}
+		if !moved {
+			break
+		}
+	}
+
+	return reassigned, nil
+}
+
+func (s *Storage) SearchUsers(ctx context.Context, filter domains.UserFilter) (*domains.UserPage, error) {
+	unlock := s.lock(ctx)
+	defer unlock()
+
+	key := func(u *domains.User) string {
+		if filter.SortBy == domains.UserSortByName {
+			return u.Name
+		}
+		return u.ID
+	}
+	prefix := strings.ToLower(filter.NamePrefix)
+
+	var users []*domains.User
+	for _, u := range s.orgState(ctx).users {
+		if u.DeletedAt != nil {
+			continue
+		}
+		if filter.IsActive != nil && u.IsActive != *filter.IsActive {
+			continue
+		}
+		if filter.TeamName != nil && !inTeam(u, *filter.TeamName) {
+			continue
+		}
+		if !strings.HasPrefix(strings.ToLower(u.Name), prefix) {
+			continue
+		}
+		if filter.After != nil && !afterPair(key(u), u.ID, filter.After.Key, filter.After.ID, filter.Desc) {
+			continue
+		}
+		users = append(users, cloneUser(u))
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return afterPair(key(users[j]), users[j].ID, key(users[i]), users[i].ID, filter.Desc)
+	})
+
+	page := &domains.UserPage{Users: make([]*domains.User, 0, filter.Limit)}
+	if len(users) > filter.Limit {
+		users = users[:filter.Limit]
+		last := users[len(users)-1]
+		page.Next = &domains.Cursor{Key: key(last), ID: last.ID}
+	}
+	page.Users = append(page.Users, users...)
+
+	return page, nil
+}
+
+// SoftDeleteUser anonymizes the user and marks it deleted, keeping it for the history
+// of its pull requests and reviews. The user leaves its team, which takes it out of every
+// team query, and is dropped from the open reviews still assigned to it.
+func (s *Storage) SoftDeleteUser(ctx context.Context, userID string) (*domains.User, error) {
+	const op = "repository.memory.user.SoftDeleteUser"
+
+	unlock := s.lock(ctx)
+	defer unlock()
+	st := s.orgState(ctx)
+
+	user, ok := st.liveUser(userID)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, repository.ErrUserNotFound)
+	}
+
+	for _, pr := range st.prs {
+		if pr.reassignable() && pr.hasReviewer(userID) {
+			pr.removeReviewer(userID)
+			pr.needMoreReviewers = true
+			pr.version++
+		}
+	}
+
+	if user.TeamName != nil {
+		st.teams[*user.TeamName]++
+	}
+
+	deletedAt := now()
+	user.Name = domains.DeletedUserName
+	user.IsActive = false
+	user.TeamName = nil
+	user.DeletedAt = &deletedAt
+
+	return cloneUser(user), nil
+}
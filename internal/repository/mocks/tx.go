@@ -0,0 +1,20 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// PassThroughTx returns a TxManager that expects a single WithinTx call and runs its
+// function with the caller's context.
+func PassThroughTx(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TxManager {
+	txManager := NewTxManager(t)
+	txManager.On("WithinTx", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) }).
+		Once()
+	return txManager
+}
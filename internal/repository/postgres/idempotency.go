@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/lib/tenant"
+	"github.com/jackc/pgx/v5"
+)
+
+// maxReserveAttempts bounds how often ReserveIdempotencyKey retries a key released
+// between its insert and its read.
+const maxReserveAttempts = 3
+
+// ReserveIdempotencyKey claims key within scope for a new request and holds it for lease.
+// It returns nil when the caller now owns the key, or the record left by an earlier request
+// with the same key. Expired keys, and keys whose request let its lease run out without
+// completing, are reclaimed as if they never existed.
+func (s *Storage) ReserveIdempotencyKey(
+	ctx context.Context,
+	scope, key, requestHash string,
+	lease time.Duration,
+) (*domains.IdempotencyRecord, error) {
+	const op = "repository.postgres.ReserveIdempotencyKey"
+
+	// A key that is held when inserting may be released before it is read back, in which
+	// case the insert is tried again.
+	for attempt := 0; ; attempt++ {
+		var reserved bool
+		err := s.pool.QueryRow(ctx, `
+			INSERT INTO idempotency_keys (org, scope, key, request_hash, locked_until, expires_at)
+			VALUES ($5, $1, $2, $3, NOW() + $4 * INTERVAL '1 second', NOW() + $4 * INTERVAL '1 second')
+			ON CONFLICT (org, scope, key) DO UPDATE
+				SET request_hash     = EXCLUDED.request_hash,
+				    status_code      = NULL,
+				    response_headers = NULL,
+				    response_body    = NULL,
+				    created_at       = NOW(),
+				    locked_until     = EXCLUDED.locked_until,
+				    expires_at       = EXCLUDED.expires_at
+				WHERE idempotency_keys.expires_at <= NOW()
+				   OR (idempotency_keys.status_code IS NULL AND idempotency_keys.locked_until <= NOW())
+			RETURNING TRUE
+		`, scope, key, requestHash, lease.Seconds(), tenant.Org(ctx)).Scan(&reserved)
+		if err == nil {
+			return nil, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		var (
+			record     domains.IdempotencyRecord
+			statusCode sql.NullInt64
+		)
+		err = s.pool.QueryRow(ctx, `
+			SELECT request_hash, status_code, response_headers, response_body
+			FROM idempotency_keys
+			WHERE org = $3 AND scope = $1 AND key = $2
+		`, scope, key, tenant.Org(ctx)).Scan(&record.RequestHash, &statusCode, &record.Header, &record.Body)
+		if errors.Is(err, pgx.ErrNoRows) && attempt < maxReserveAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		record.Completed = statusCode.Valid
+		record.StatusCode = int(statusCode.Int64)
+
+		return &record, nil
+	}
+}
+
+// CompleteIdempotencyKey stores the response produced for a reserved key, to be replayed
+// for ttl.
+func (s *Storage) CompleteIdempotencyKey(
+	ctx context.Context,
+	scope, key string,
+	statusCode int,
+	header map[string]string,
+	body []byte,
+	ttl time.Duration,
+) error {
+	const op = "repository.postgres.CompleteIdempotencyKey"
+
+	_, err := s.pool.Exec(ctx, `
+		UPDATE idempotency_keys
+		SET status_code = $3, response_headers = $4, response_body = $5,
+		    locked_until = NULL, expires_at = NOW() + $7 * INTERVAL '1 second'
+		WHERE org = $6 AND scope = $1 AND key = $2
+	`, scope, key, statusCode, header, body, tenant.Org(ctx), ttl.Seconds())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ReleaseIdempotencyKey forgets a reserved key so that the request may be retried with it.
+func (s *Storage) ReleaseIdempotencyKey(ctx context.Context, scope, key string) error {
+	const op = "repository.postgres.ReleaseIdempotencyKey"
+
+	_, err := s.pool.Exec(ctx, `DELETE FROM idempotency_keys WHERE org = $3 AND scope = $1 AND key = $2`,
+		scope, key, tenant.Org(ctx))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// PurgeIdempotencyKeys deletes expired keys of every organization and returns how many
+// were removed.
+func (s *Storage) PurgeIdempotencyKeys(ctx context.Context) (int64, error) {
+	const op = "repository.postgres.PurgeIdempotencyKeys"
+
+	res, err := s.pool.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res.RowsAffected(), nil
+}
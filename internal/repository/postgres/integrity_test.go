@@ -0,0 +1,169 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/repository/postgres"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	checkViolation      = "23514"
+	foreignKeyViolation = "23503"
+)
+
+// integritySnapshot has an open and a merged pull request by u1, both reviewed by u2.
+func integritySnapshot() *domains.Snapshot {
+	team := "backend"
+	mergedAt := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	users := make([]*domains.User, 0, 3)
+	for _, id := range []string{"u1", "u2", "u3"} {
+		users = append(users, &domains.User{ID: id, Name: id, TeamName: &team, IsActive: true, Role: domains.RoleMember})
+	}
+
+	return &domains.Snapshot{
+		Statuses: domains.PRStatuses,
+		Teams:    []string{team},
+		Users:    users,
+		PullRequests: []*domains.PullRequest{
+			{
+				ID:        "pr-merged",
+				Name:      "Merged",
+				Author:    &domains.User{ID: "u1"},
+				Status:    domains.PRStatusMerged,
+				CreatedAt: mergedAt.Add(-time.Hour),
+				MergedAt:  &mergedAt,
+				Reviewers: []*domains.Reviewer{{User: &domains.User{ID: "u2"}}},
+			},
+			{
+				ID:        "pr-open",
+				Name:      "Open",
+				Author:    &domains.User{ID: "u1"},
+				Status:    domains.PRStatusOpen,
+				CreatedAt: mergedAt,
+				Reviewers: []*domains.Reviewer{{User: &domains.User{ID: "u2"}}},
+			},
+		},
+	}
+}
+
+func TestIntegrityConstraints(t *testing.T) {
+	if os.Getenv(dsnEnv) == "" {
+		t.Skipf("%s is not set", dsnEnv)
+	}
+	t.Parallel()
+
+	cases := []struct {
+		name          string
+		query         string
+		expectedError string
+	}{
+		{
+			name:          "Author reviews own PR",
+			query:         `INSERT INTO reviewers (org, pull_request_id, user_id) VALUES ('default', 'pr-open', 'u1')`,
+			expectedError: checkViolation,
+		},
+		{
+			name:          "Reviewer becomes author",
+			query:         `UPDATE pull_requests SET author_id = 'u2' WHERE id = 'pr-open'`,
+			expectedError: checkViolation,
+		},
+		{
+			name:          "Reviewer added to merged PR",
+			query:         `INSERT INTO reviewers (org, pull_request_id, user_id) VALUES ('default', 'pr-merged', 'u3')`,
+			expectedError: checkViolation,
+		},
+		{
+			name:          "Reviewer replaced on merged PR",
+			query:         `UPDATE reviewers SET user_id = 'u3' WHERE pull_request_id = 'pr-merged'`,
+			expectedError: checkViolation,
+		},
+		{
+			name:          "Reviewer removed from merged PR",
+			query:         `DELETE FROM reviewers WHERE pull_request_id = 'pr-merged'`,
+			expectedError: checkViolation,
+		},
+		{
+			name:  "Reviewer added to open PR",
+			query: `INSERT INTO reviewers (org, pull_request_id, user_id) VALUES ('default', 'pr-open', 'u3')`,
+		},
+		{
+			name:          "Reviewer from another organization",
+			query:         `INSERT INTO reviewers (org, pull_request_id, user_id) VALUES ('other', 'pr-open', 'u3')`,
+			expectedError: foreignKeyViolation,
+		},
+		{
+			name:  "Merged PR deleted with its reviewers",
+			query: `DELETE FROM pull_requests WHERE id = 'pr-merged'`,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			dsn := migratedDatabase(t, 0)
+
+			s, err := postgres.New(postgresConfig(t, dsn))
+			require.NoError(t, err)
+			require.NoError(t, s.RestoreSnapshot(ctx, integritySnapshot()))
+			require.NoError(t, s.Close())
+
+			conn, err := pgx.Connect(ctx, dsn.String())
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = conn.Close(ctx) })
+
+			_, err = conn.Exec(ctx, tc.query)
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			var pgErr *pgconn.PgError
+			require.True(t, errors.As(err, &pgErr), "unexpected error: %v", err)
+			require.Equal(t, tc.expectedError, pgErr.Code)
+		})
+	}
+}
+
+func TestIntegrityMigrationReportsViolations(t *testing.T) {
+	if os.Getenv(dsnEnv) == "" {
+		t.Skipf("%s is not set", dsnEnv)
+	}
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// the last schema without the integrity constraints
+	dsn := migratedDatabase(t, 12)
+
+	conn, err := pgx.Connect(ctx, dsn.String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close(ctx) })
+
+	_, err = conn.Exec(ctx, `
+		INSERT INTO users (id, name, is_active) VALUES ('u1', 'Alice', NULL);
+		INSERT INTO pull_requests (id, name, author_id) VALUES ('pr1', 'Legacy', 'u1');
+		INSERT INTO reviewers (pull_request_id, user_id) VALUES ('pr1', 'u1');
+	`)
+	require.NoError(t, err)
+
+	m, err := migrate.New(migrationsPath, dsn.String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _, _ = m.Close() })
+
+	err = m.Up()
+	require.ErrorContains(t, err, "users with NULL is_active: u1")
+	require.ErrorContains(t, err, "pull requests reviewed by their author: pr1 (u1)")
+}
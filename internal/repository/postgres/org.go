@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/lib/tenant"
+	"github.com/jackc/pgx/v5"
+)
+
+// errDryRun rolls back the transaction of a dry-run import.
+var errDryRun = errors.New("dry run")
+
+// ImportTeams applies the whole org chart in one transaction using the same upsert
+// semantics as CreateTeam. With dryRun set the transaction is rolled back and only the report is returned.
+func (s *Storage) ImportTeams(ctx context.Context, teams []*domains.Team, dryRun bool) (*domains.ImportReport, error) {
+	var report *domains.ImportReport
+	err := s.inTx(ctx, func(tx pgx.Tx) error {
+		if !dryRun {
+			var err error
+			report, err = importTeams(ctx, tx, teams, false)
+			return err
+		}
+
+		// the savepoint keeps a dry run inside WithinTx from leaving changes in the outer transaction
+		sp, err := tx.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = sp.Rollback(ctx) }()
+
+		if report, err = importTeams(ctx, sp, teams, true); err != nil {
+			return err
+		}
+		return errDryRun
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func importTeams(ctx context.Context, tx pgx.Tx, teams []*domains.Team, dryRun bool) (*domains.ImportReport, error) {
+	const op = "storage.postgres.ImportTeams"
+
+	org := tenant.Org(ctx)
+	report := &domains.ImportReport{DryRun: dryRun}
+
+	var userIDs []string
+	for _, team := range teams {
+		for _, member := range team.Members {
+			userIDs = append(userIDs, member.ID)
+		}
+	}
+
+	// Snapshot of the users being imported, taken before any change
+	rows, err := tx.Query(ctx, `
+		SELECT id, name, team_name, is_active, role
+		FROM users
+		WHERE org = $1 AND id = ANY($2)
+		FOR UPDATE
+	`, org, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	existing := make(map[string]*domains.User, len(userIDs))
+	for rows.Next() {
+		var user domains.User
+		if err = rows.Scan(&user.ID, &user.Name, &user.TeamName, &user.IsActive, &user.Role); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		existing[user.ID] = &user
+	}
+	if err = rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	rows.Close()
+
+	for _, team := range teams {
+		var name string
+		err = tx.QueryRow(ctx,
+			`INSERT INTO teams (org, name) VALUES ($1, $2) ON CONFLICT (org, name) DO NOTHING RETURNING name`,
+			org, team.Name).
+			Scan(&name)
+		switch {
+		case err == nil:
+			report.CreatedTeams = append(report.CreatedTeams, team.Name)
+		case errors.Is(err, pgx.ErrNoRows):
+			// team already exists
+		default:
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err = upsertTeamMembers(ctx, tx, team); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if _, err = tx.Exec(ctx, queryBumpTeamVersion, org, team.Name); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		var deactivated []string
+		for _, member := range team.Members {
+			after := &domains.User{
+				ID:       member.ID,
+				Name:     member.Name,
+				TeamName: &team.Name,
+				IsActive: member.IsActive,
+				Role:     member.Role,
+			}
+
+			before, ok := existing[member.ID]
+			if after.Role == "" {
+				after.Role = domains.RoleMember
+				if ok {
+					after.Role = before.Role
+				}
+			}
+
+			if ok && before.IsActive && !after.IsActive {
+				deactivated = append(deactivated, member.ID)
+			}
+
+			switch {
+			case !ok:
+				report.Created = append(report.Created, after)
+			case before.TeamName == nil || *before.TeamName != team.Name:
+				report.Moved = append(report.Moved, &domains.UserChange{Before: before, After: after})
+			case before.Name != after.Name || before.IsActive != after.IsActive || before.Role != after.Role:
+				report.Updated = append(report.Updated, &domains.UserChange{Before: before, After: after})
+			default:
+				report.Unchanged++
+			}
+		}
+
+		// deactivated members release their open reviews, as with /team/deactivate
+		if len(deactivated) == 0 {
+			continue
+		}
+		_, reassigned, err := deactivateTeamMembers(ctx, tx, team.Name, deactivated, 0)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		report.Reassigned = append(report.Reassigned, reassigned...)
+	}
+
+	return report, nil
+}
@@ -1,32 +1,84 @@
 package postgres
 
 import (
-	"database/sql"
+	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/Deymos01/pr-review-manager/internal/config"
-
-	_ "github.com/lib/pq"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type Storage struct {
-	db *sql.DB
+	pool      *pgxpool.Pool
+	txMetrics txMetrics
 }
 
 func New(dbConfig config.PostgresConfig) (*Storage, error) {
 	const op = "storage.postgres.New"
 
-	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		dbConfig.Host, dbConfig.Port, dbConfig.User, dbConfig.Password, dbConfig.DBName, dbConfig.SSLMode)
+	poolConfig, err := pgxpool.ParseConfig(dbConfig.URL())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 
-	db, err := sql.Open("postgres", dsn)
+	poolConfig.MaxConns = dbConfig.MaxConns
+	poolConfig.MinConns = dbConfig.MinConns
+	poolConfig.MaxConnLifetime = dbConfig.MaxConnLifetime
+	poolConfig.ConnConfig.ConnectTimeout = dbConfig.ConnectTimeout
+	poolConfig.ConnConfig.RuntimeParams["application_name"] = dbConfig.ApplicationName
+	poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(dbConfig.StatementTimeout.Milliseconds(), 10)
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	if err := db.Ping(); err != nil {
+	ctx, cancel := context.WithTimeout(context.Background(), dbConfig.ConnectTimeout)
+	defer cancel()
+
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return &Storage{db: db}, nil
+	return &Storage{pool: pool}, nil
+}
+
+// PoolStats is a snapshot of the connection pool state.
+type PoolStats struct {
+	TotalConns           int32   `json:"total_conns"`
+	AcquiredConns        int32   `json:"acquired_conns"`
+	IdleConns            int32   `json:"idle_conns"`
+	ConstructingConns    int32   `json:"constructing_conns"`
+	MaxConns             int32   `json:"max_conns"`
+	AcquireCount         int64   `json:"acquire_count"`
+	EmptyAcquireCount    int64   `json:"empty_acquire_count"`
+	CanceledAcquireCount int64   `json:"canceled_acquire_count"`
+	AcquireDurationMs    float64 `json:"acquire_duration_ms"`
+	NewConnsCount        int64   `json:"new_conns_count"`
+}
+
+// PoolStats returns connection pool metrics.
+func (s *Storage) PoolStats() PoolStats {
+	stat := s.pool.Stat()
+
+	return PoolStats{
+		TotalConns:           stat.TotalConns(),
+		AcquiredConns:        stat.AcquiredConns(),
+		IdleConns:            stat.IdleConns(),
+		ConstructingConns:    stat.ConstructingConns(),
+		MaxConns:             stat.MaxConns(),
+		AcquireCount:         stat.AcquireCount(),
+		EmptyAcquireCount:    stat.EmptyAcquireCount(),
+		CanceledAcquireCount: stat.CanceledAcquireCount(),
+		AcquireDurationMs:    float64(stat.AcquireDuration().Microseconds()) / 1000,
+		NewConnsCount:        stat.NewConnsCount(),
+	}
+}
+
+// Close closes the connection pool.
+func (s *Storage) Close() error {
+	s.pool.Close()
+	return nil
 }
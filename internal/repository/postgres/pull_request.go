@@ -5,115 +5,164 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"math/rand"
+	"time"
 
 	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/lib/tenant"
 	"github.com/Deymos01/pr-review-manager/internal/repository"
+	"github.com/jackc/pgx/v5"
 )
 
-const (
-	numReviewersToAssign = 2
-)
+// queryBumpVersion marks a pull request as changed whenever its reviewers are modified.
+const queryBumpVersion = `UPDATE pull_requests SET version = version + 1 WHERE org = $1 AND id = $2`
+
+// CreatePullRequest stores an open pull request and assigns reviewers from the author's team.
+func (s *Storage) CreatePullRequest(ctx context.Context, prID, prName, authorID string, requireLead bool) ([]string, error) {
+	var reviewers []string
+	err := s.inTx(ctx, func(tx pgx.Tx) error {
+		var err error
+		reviewers, err = createPullRequest(ctx, tx, prID, prName, authorID, requireLead)
+		return err
+	})
 
-func (s *Storage) CreatePullRequest(ctx context.Context, prID, prName, authorID string) ([]string, error) {
+	return reviewers, err
+}
+
+func createPullRequest(
+	ctx context.Context,
+	tx pgx.Tx,
+	prID, prName, authorID string,
+	requireLead bool,
+) ([]string, error) {
 	const op = "repository.postgres.CreatePullRequest"
 
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
-	}
-	defer func() { _ = tx.Rollback() }()
+	var err error
+	org := tenant.Org(ctx)
 
-	queryPRExists := `SELECT EXISTS(SELECT 1 FROM pull_requests WHERE id = $1)`
+	queryPRExists := `SELECT EXISTS(SELECT 1 FROM pull_requests WHERE org = $1 AND id = $2)`
 	var exists bool
-	if err = tx.QueryRowContext(ctx, queryPRExists, prID).Scan(&exists); err != nil {
+	if err = tx.QueryRow(ctx, queryPRExists, org, prID).Scan(&exists); err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 	if exists {
 		return nil, repository.ErrPRAlreadyExists
 	}
 
-	queryGetMembers := `
-		SELECT id
-		FROM users
-		WHERE is_active = TRUE
-		  AND team_name = (
-				SELECT team_name
-				FROM users
-				WHERE id = $1
-			)
-		  AND id <> $1
-		FOR UPDATE
-	`
-	rows, err := tx.QueryContext(ctx, queryGetMembers, authorID)
+	members, leads, err := reviewerCandidates(ctx, tx, authorID)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	defer func() { _ = rows.Close() }()
-
-	var members []string
-	for rows.Next() {
-		var memberID string
-		if err := rows.Scan(&memberID); err != nil {
-			return nil, fmt.Errorf("%s: %w", op, err)
-		}
-		members = append(members, memberID)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
-	}
 
 	if len(members) == 0 {
 		return nil, fmt.Errorf("%s: no active teammates found for author %s", op, authorID)
 	}
 
-	numReviewers := numReviewersToAssign
-	if len(members) < numReviewers {
-		numReviewers = len(members)
-	}
-	rand.Shuffle(len(members), func(i, j int) {
-		members[i], members[j] = members[j], members[i]
-	})
-	reviewers := members[:numReviewers]
-
-	queryStatusOpen := `SELECT id FROM statuses WHERE name = 'OPEN'`
-	var statusID string
-	if err = tx.QueryRowContext(ctx, queryStatusOpen).Scan(&statusID); err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
+	reviewers, err := repository.SelectReviewers(members, leads, requireLead)
+	if err != nil {
+		return nil, err
 	}
 
 	queryCreatePR := `
-		INSERT INTO pull_requests (id, name, author_id, status_id)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO pull_requests (org, id, name, author_id, status)
+		VALUES ($1, $2, $3, $4, $5)
 	`
 
-	if _, err = tx.ExecContext(ctx, queryCreatePR, prID, prName, authorID, statusID); err != nil {
+	if _, err = tx.Exec(ctx, queryCreatePR, org, prID, prName, authorID, domains.PRStatusOpen); err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	queryAssignReviewer := `
-		INSERT INTO reviewers (pull_request_id, user_id)
-		VALUES ($1, $2)
-	`
+	if err = assignReviewers(ctx, tx, prID, reviewers); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return reviewers, nil
+}
+
+// assignReviewers inserts all reviewer rows in a single round trip.
+func assignReviewers(ctx context.Context, tx pgx.Tx, prID string, reviewers []string) error {
+	org := tenant.Org(ctx)
+
+	batch := &pgx.Batch{}
 	for _, reviewerID := range reviewers {
-		if _, err = tx.ExecContext(ctx, queryAssignReviewer, prID, reviewerID); err != nil {
-			return nil, fmt.Errorf("%s: %w", op, err)
+		batch.Queue(`INSERT INTO reviewers (org, pull_request_id, user_id) VALUES ($1, $2, $3)`, org, prID, reviewerID)
+	}
+
+	return tx.SendBatch(ctx, batch).Close()
+}
+
+// reviewerCandidates locks and returns the author's active teammates that may review
+// their pull request; leads holds the subset with the lead role.
+func reviewerCandidates(ctx context.Context, tx pgx.Tx, authorID string) (members, leads []string, err error) {
+	// Observers are never assigned as reviewers
+	queryGetMembers := `
+		SELECT id, role
+		FROM users
+		WHERE org = $2
+		  AND is_active = TRUE
+		  AND role <> 'observer'
+		  AND team_name = (
+				SELECT team_name
+				FROM users
+				WHERE org = $2 AND id = $1
+			)
+		  AND id <> $1
+		FOR UPDATE
+	`
+	rows, err := tx.Query(ctx, queryGetMembers, authorID, tenant.Org(ctx))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			memberID string
+			role     domains.Role
+		)
+		if err := rows.Scan(&memberID, &role); err != nil {
+			return nil, nil, err
 		}
+		if role == domains.RoleLead {
+			leads = append(leads, memberID)
+		}
+		members = append(members, memberID)
 	}
 
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
+	return members, leads, rows.Err()
+}
+
+// replacementReviewer returns a random active teammate of userID that neither authors nor
+// already reviews the pull request. It fails with ErrNoCandidate when there is none.
+func replacementReviewer(ctx context.Context, tx pgx.Tx, prID, userID string) (string, error) {
+	querySelect := `
+		SELECT u.id
+		FROM users u
+		WHERE u.org = $3 AND
+		      u.team_name = (SELECT team_name FROM users WHERE org = $3 AND id = $1) AND
+		      u.id != $1 AND u.is_active AND u.role <> 'observer' AND
+		      u.id NOT IN (SELECT author_id FROM pull_requests pr WHERE pr.org = $3 AND pr.id = $2) AND
+		      u.id NOT IN (SELECT user_id
+		                   FROM reviewers
+		                   WHERE org = $3 AND pull_request_id = $2)
+		ORDER BY RANDOM()
+		LIMIT 1;
+	`
+
+	var newUserID string
+	err := tx.QueryRow(ctx, querySelect, userID, prID, tenant.Org(ctx)).Scan(&newUserID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", repository.ErrNoCandidate
 	}
 
-	return reviewers, nil
+	return newUserID, err
 }
 
 func (s *Storage) PullRequestExists(ctx context.Context, prID string) (bool, error) {
 	const op = "repository.postgres.PullRequestExists"
 
 	var exists bool
-	query := `SELECT EXISTS(SELECT 1 FROM pull_requests WHERE id = $1)`
-	err := s.db.QueryRowContext(ctx, query, prID).Scan(&exists)
+	query := `SELECT EXISTS(SELECT 1 FROM pull_requests WHERE org = $1 AND id = $2)`
+	err := s.conn(ctx).QueryRow(ctx, query, tenant.Org(ctx), prID).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
@@ -125,109 +174,305 @@ func (s *Storage) PullRequestMerged(ctx context.Context, prID string) (bool, err
 	const op = "repository.postgres.PullRequestMerged"
 
 	var isMerged bool
-	query := `SELECT CASE WHEN st.name = 'MERGED' THEN TRUE ELSE FALSE END
-				FROM pull_requests pr
-				JOIN statuses st ON pr.status_id = st.id
-				WHERE pr.id = $1`
-	err := s.db.QueryRowContext(ctx, query, prID).Scan(&isMerged)
+	query := `SELECT status = $2 FROM pull_requests WHERE org = $3 AND id = $1`
+	err := s.conn(ctx).QueryRow(ctx, query, prID, domains.PRStatusMerged, tenant.Org(ctx)).Scan(&isMerged)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, fmt.Errorf("%s: %w", op, repository.ErrPRNotFound)
+		}
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return isMerged, nil
 }
 
-func (s *Storage) MergePullRequest(ctx context.Context, prID string) error {
+// MergePullRequest marks the pull request as merged. A non-zero ifVersion makes the merge
+// conditional on the stored version, as requested by an If-Match header.
+// Merging a merged pull request changes nothing.
+func (s *Storage) MergePullRequest(ctx context.Context, prID string, ifVersion int64) error {
 	const op = "repository.postgres.MergePullRequest"
 
 	query := `UPDATE pull_requests
-				SET status_id = (SELECT id FROM statuses WHERE name = 'MERGED'),
-				 	merged_at = NOW()
-				WHERE id = $1`
-	_, err := s.db.ExecContext(ctx, query, prID)
+				SET status = $3,
+				 	merged_at = NOW(),
+				 	version = version + 1
+				WHERE org = $4 AND id = $1 AND status <> $3 AND ($2::BIGINT = 0 OR version = $2::BIGINT)`
+	res, err := s.conn(ctx).Exec(ctx, query, prID, ifVersion, domains.PRStatusMerged, tenant.Org(ctx))
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	if res.RowsAffected() == 0 {
+		merged, err := s.PullRequestMerged(ctx, prID)
+		if err != nil {
+			if errors.Is(err, repository.ErrPRNotFound) {
+				return repository.ErrPRNotFound
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if merged {
+			// merging again keeps the original merged_at and version
+			return nil
+		}
+		return repository.ErrVersionConflict
+	}
+
 	return nil
 }
 
+// GetPullRequestByID loads the pull request with its author and reviewers in a single query:
+// every row carries the PR columns plus one reviewer, or NULLs when nobody is assigned.
 func (s *Storage) GetPullRequestByID(ctx context.Context, prID string) (*domains.PullRequest, error) {
 	const op = "repository.postgres.GetPullRequestByID"
 
-	tx, err := s.db.BeginTx(ctx, nil)
+	query := `SELECT pr.id, pr.name, pr.description, pr.labels, pr.version,
+					pr.author_id, a.name, a.team_name, a.is_active, a.role, pr.status,
+					pr.need_more_reviewers, pr.created_at, pr.merged_at,
+					u.id, u.name, u.team_name, u.is_active, u.role, r.assigned_at
+				FROM pull_requests pr
+				JOIN users a ON a.org = pr.org AND a.id = pr.author_id
+				LEFT JOIN reviewers r ON r.org = pr.org AND r.pull_request_id = pr.id
+				LEFT JOIN users u ON u.org = r.org AND u.id = r.user_id
+				WHERE pr.org = $1 AND pr.id = $2
+				ORDER BY r.assigned_at, u.id`
+
+	rows, err := s.conn(ctx).Query(ctx, query, tenant.Org(ctx), prID)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	defer func() { _ = tx.Rollback() }()
+	defer rows.Close()
 
-	queryPR := `SELECT pr.id, pr.name, pr.author_id, st.name, pr.merged_at
-				FROM pull_requests pr
-				JOIN statuses st ON pr.status_id = st.id
-				WHERE pr.id = $1`
+	var pr *domains.PullRequest
+	for rows.Next() {
+		var (
+			row        domains.PullRequest
+			author     domains.User
+			reviewerID sql.NullString
+			name       sql.NullString
+			teamName   sql.NullString
+			isActive   sql.NullBool
+			role       sql.NullString
+			assignedAt sql.NullTime
+		)
+		err := rows.Scan(&row.ID, &row.Name, &row.Description, &row.Labels, &row.Version, &author.ID, &author.Name, &author.TeamName, &author.IsActive, &author.Role,
+			&row.Status, &row.NeedMoreReviewers, &row.CreatedAt, &row.MergedAt,
+			&reviewerID, &name, &teamName, &isActive, &role, &assignedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
 
-	var pr domains.PullRequest
-	pr.Author = &domains.User{}
-	err = tx.QueryRowContext(ctx, queryPR, prID).
-		Scan(&pr.ID, &pr.Name, &pr.Author.ID, &pr.Status, &pr.MergedAt)
-	if err != nil {
+		if pr == nil {
+			row.Author = &author
+			row.Reviewers = []*domains.Reviewer{}
+			pr = &row
+		}
+		if !reviewerID.Valid {
+			continue
+		}
+
+		reviewer := &domains.Reviewer{
+			User: &domains.User{
+				ID:       reviewerID.String,
+				Name:     name.String,
+				IsActive: isActive.Bool,
+				Role:     domains.Role(role.String),
+			},
+			AssignedAt: assignedAt.Time,
+		}
+		if teamName.Valid {
+			reviewer.User.TeamName = &teamName.String
+		}
+		pr.Reviewers = append(pr.Reviewers, reviewer)
+	}
+
+	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	queryReviewers := `SELECT user_id FROM reviewers WHERE pull_request_id = $1`
-	rows, err := tx.QueryContext(ctx, queryReviewers, prID)
+	if pr == nil {
+		return nil, repository.ErrPRNotFound
+	}
+
+	return pr, nil
+}
+
+// ListPullRequests returns a page of pull requests ordered by creation time.
+func (s *Storage) ListPullRequests(ctx context.Context, filter domains.PullRequestFilter) (*domains.PullRequestPage, error) {
+	const op = "repository.postgres.ListPullRequests"
+
+	direction, cmp := keysetPage(filter.Desc)
+
+	var b whereBuilder
+	b.add("pr.org = " + b.arg(tenant.Org(ctx)))
+	if filter.Status != "" {
+		b.add("pr.status = " + b.arg(filter.Status))
+	}
+	if filter.TeamName != "" {
+		b.add("a.team_name = " + b.arg(filter.TeamName))
+	}
+	if filter.AuthorID != "" {
+		b.add("pr.author_id = " + b.arg(filter.AuthorID))
+	}
+	if filter.ReviewerID != "" {
+		b.add("EXISTS (SELECT 1 FROM reviewers r WHERE r.org = pr.org AND r.pull_request_id = pr.id AND r.user_id = " +
+			b.arg(filter.ReviewerID) + ")")
+	}
+	if filter.CreatedFrom != nil {
+		b.add("pr.created_at >= " + b.arg(*filter.CreatedFrom))
+	}
+	if filter.CreatedTo != nil {
+		b.add("pr.created_at < " + b.arg(*filter.CreatedTo))
+	}
+	if filter.MergedFrom != nil {
+		b.add("pr.merged_at >= " + b.arg(*filter.MergedFrom))
+	}
+	if filter.MergedTo != nil {
+		b.add("pr.merged_at < " + b.arg(*filter.MergedTo))
+	}
+	if filter.NeedMoreReviewers != nil {
+		b.add("pr.need_more_reviewers = " + b.arg(*filter.NeedMoreReviewers))
+	}
+	if filter.After != nil {
+		createdAt, err := time.Parse(time.RFC3339Nano, filter.After.Key)
+		if err != nil {
+			return nil, repository.ErrInvalidCursor
+		}
+		b.add(fmt.Sprintf("(pr.created_at, pr.id) %s (%s, %s)", cmp, b.arg(createdAt), b.arg(filter.After.ID)))
+	}
+
+	query := `
+		SELECT pr.id, pr.name, pr.description, pr.labels, pr.version, pr.author_id, a.name, a.team_name, pr.status,
+			pr.need_more_reviewers, pr.created_at, pr.merged_at
+		FROM pull_requests pr
+		JOIN users a ON a.org = pr.org AND a.id = pr.author_id` + b.where() +
+		fmt.Sprintf(" ORDER BY pr.created_at %s, pr.id %s LIMIT %s", direction, direction, b.arg(filter.Limit+1))
+
+	rows, err := s.conn(ctx).Query(ctx, query, b.args...)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	defer func() { _ = rows.Close() }()
+	defer rows.Close()
 
-	var reviewers []*domains.Reviewer
+	page := &domains.PullRequestPage{PullRequests: make([]*domains.PullRequest, 0, filter.Limit)}
 	for rows.Next() {
-		var reviewerID string
-		if err := rows.Scan(&reviewerID); err != nil {
+		var pr domains.PullRequest
+		pr.Author = &domains.User{}
+		err := rows.Scan(&pr.ID, &pr.Name, &pr.Description, &pr.Labels, &pr.Version,
+			&pr.Author.ID, &pr.Author.Name, &pr.Author.TeamName, &pr.Status,
+			&pr.NeedMoreReviewers, &pr.CreatedAt, &pr.MergedAt)
+		if err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
-		reviewers = append(reviewers, &domains.Reviewer{User: &domains.User{ID: reviewerID}})
+		page.PullRequests = append(page.PullRequests, &pr)
 	}
+
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	pr.Reviewers = reviewers
 
-	if err := tx.Commit(); err != nil {
+	if len(page.PullRequests) > filter.Limit {
+		page.PullRequests = page.PullRequests[:filter.Limit]
+		last := page.PullRequests[len(page.PullRequests)-1]
+		page.Next = &domains.Cursor{Key: last.CreatedAt.Format(time.RFC3339Nano), ID: last.ID}
+	}
+
+	if err := loadReviewers(ctx, s.conn(ctx), page.PullRequests); err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return &pr, nil
+	return page, nil
 }
 
-func (s *Storage) ReassignReviewer(ctx context.Context, prID, oldUserID string) (string, error) {
+// loadReviewers fills Reviewers of the given pull requests in assignment order.
+func loadReviewers(ctx context.Context, q querier, prs []*domains.PullRequest) error {
+	if len(prs) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*domains.PullRequest, len(prs))
+	ids := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		pr.Reviewers = []*domains.Reviewer{}
+		byID[pr.ID] = pr
+		ids = append(ids, pr.ID)
+	}
+
+	rows, err := q.Query(ctx, `
+		SELECT r.pull_request_id, u.id, u.name, u.team_name, u.is_active, u.role, r.assigned_at
+		FROM reviewers r
+		JOIN users u ON u.org = r.org AND u.id = r.user_id
+		WHERE r.org = $1 AND r.pull_request_id = ANY($2)
+		ORDER BY r.assigned_at, u.id
+	`, tenant.Org(ctx), ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			prID     string
+			reviewer domains.Reviewer
+		)
+		reviewer.User = &domains.User{}
+		u := reviewer.User
+		if err := rows.Scan(&prID, &u.ID, &u.Name, &u.TeamName, &u.IsActive, &u.Role, &reviewer.AssignedAt); err != nil {
+			return err
+		}
+		byID[prID].Reviewers = append(byID[prID].Reviewers, &reviewer)
+	}
+
+	return rows.Err()
+}
+
+// ReassignReviewer replaces oldUserID with a random eligible member of their team. The PR row
+// is locked for the whole transaction, so concurrent reassignments and merges are serialized;
+// a non-zero ifVersion must match the stored version.
+func (s *Storage) ReassignReviewer(ctx context.Context, prID, oldUserID string, ifVersion int64) (string, error) {
+	var newUserID string
+	err := s.inTx(ctx, func(tx pgx.Tx) error {
+		var err error
+		newUserID, err = reassignReviewer(ctx, tx, prID, oldUserID, ifVersion)
+		return err
+	})
+
+	return newUserID, err
+}
+
+func reassignReviewer(ctx context.Context, tx pgx.Tx, prID, oldUserID string, ifVersion int64) (string, error) {
 	const op = "repository.postgres.user.ReassignReviewer"
 
-	tx, err := s.db.BeginTx(ctx, nil)
+	var err error
+	org := tenant.Org(ctx)
+
+	var (
+		status  domains.PRStatus
+		version int64
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT status, version
+		FROM pull_requests
+		WHERE org = $1 AND id = $2
+		FOR UPDATE
+	`, org, prID).Scan(&status, &version)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", repository.ErrPRNotFound
+		}
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
-	defer func() { _ = tx.Rollback() }()
 
-	querySelect := `
-		SELECT u.id
-		FROM users u
-		WHERE u.team_name = (SELECT team_name FROM users WHERE id = $1) AND
-		      u.id != $1 AND u.is_active AND
-		      u.id NOT IN (SELECT author_id FROM pull_requests pr WHERE pr.id = $2) AND 
-		      u.id NOT IN (SELECT user_id 
-    						FROM reviewers 
-    						WHERE pull_request_id = $2)
-		ORDER BY RANDOM()
-		LIMIT 1;
-	`
+	if ifVersion != 0 && version != ifVersion {
+		return "", repository.ErrVersionConflict
+	}
+	if status == domains.PRStatusMerged {
+		return "", repository.ErrPRMerged
+	}
 
-	var newUserID string
-	err = tx.QueryRowContext(ctx, querySelect, oldUserID, prID).Scan(&newUserID)
+	newUserID, err := replacementReviewer(ctx, tx, prID, oldUserID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return "", repository.ErrNoCandidate
+		if errors.Is(err, repository.ErrNoCandidate) {
+			return "", err
 		}
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
@@ -236,17 +481,113 @@ func (s *Storage) ReassignReviewer(ctx context.Context, prID, oldUserID string)
 		UPDATE reviewers
 		SET user_id = $1,
 		 	assigned_at = NOW()
-		WHERE pull_request_id = $2 AND user_id = $3;
+		WHERE org = $4 AND pull_request_id = $2 AND user_id = $3;
 	`
 
-	_, err = tx.ExecContext(ctx, queryUpdate, newUserID, prID, oldUserID)
+	_, err = tx.Exec(ctx, queryUpdate, newUserID, prID, oldUserID, org)
 	if err != nil {
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
-	if err := tx.Commit(); err != nil {
+	if _, err = tx.Exec(ctx, queryBumpVersion, org, prID); err != nil {
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
 	return newUserID, nil
 }
+
+// UpdatePullRequest applies upd to an open pull request if upd.Version matches the stored
+// version. When the PR moves to an author who reviews it, their review goes to a teammate of
+// the new author; the other reviewers are kept.
+func (s *Storage) UpdatePullRequest(ctx context.Context, prID string, upd domains.PullRequestUpdate) error {
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		return updatePullRequest(ctx, tx, prID, upd)
+	})
+}
+
+func updatePullRequest(ctx context.Context, tx pgx.Tx, prID string, upd domains.PullRequestUpdate) error {
+	const op = "repository.postgres.UpdatePullRequest"
+
+	var err error
+	org := tenant.Org(ctx)
+
+	var (
+		authorID string
+		status   domains.PRStatus
+		version  int64
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT author_id, status, version
+		FROM pull_requests
+		WHERE org = $1 AND id = $2
+		FOR UPDATE
+	`, org, prID).Scan(&authorID, &status, &version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repository.ErrPRNotFound
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if version != upd.Version {
+		return repository.ErrVersionConflict
+	}
+	if status == domains.PRStatusMerged {
+		return repository.ErrPRMerged
+	}
+
+	// The new author must never review their own PR. Only their review is dropped; the
+	// other reviewers keep their assignment.
+	authorChanged := upd.AuthorID != nil && *upd.AuthorID != authorID
+	var slotOpened bool
+	if authorChanged {
+		tag, err := tx.Exec(ctx, `
+			DELETE FROM reviewers WHERE org = $1 AND pull_request_id = $2 AND user_id = $3
+		`, org, prID, *upd.AuthorID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		slotOpened = tag.RowsAffected() > 0
+	}
+
+	var labels any
+	if upd.Labels != nil {
+		labels = *upd.Labels
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE pull_requests
+		SET name = COALESCE($2, name),
+		    description = COALESCE($3, description),
+		    labels = COALESCE($4::text[], labels),
+		    author_id = COALESCE($5, author_id),
+		    version = version + 1
+		WHERE org = $6 AND id = $1
+	`, prID, upd.Name, upd.Description, labels, upd.AuthorID, org)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if slotOpened {
+		// the PR already has its new author, so the replacement comes from their team
+		reviewerID, err := replacementReviewer(ctx, tx, prID, *upd.AuthorID)
+		switch {
+		case errors.Is(err, repository.ErrNoCandidate):
+			// nobody can take the review over, so the PR waits for another reviewer
+			_, err = tx.Exec(ctx, `
+				UPDATE pull_requests SET need_more_reviewers = TRUE WHERE org = $1 AND id = $2
+			`, org, prID)
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		case err != nil:
+			return fmt.Errorf("%s: %w", op, err)
+		default:
+			if err = assignReviewers(ctx, tx, prID, []string{reviewerID}); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+	}
+
+	return nil
+}
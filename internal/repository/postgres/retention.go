@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/lib/tenant"
+	"github.com/jackc/pgx/v5"
+)
+
+// PruneMergedPullRequests removes up to limit pull requests merged before mergedBefore from
+// the live tables, oldest first, and reports how many pull requests and reviews it removed.
+// Their authors and reviewers keep the counts in review_stats; in archive mode the rows are
+// also copied into the archive tables. Rows locked by another run are skipped.
+func (s *Storage) PruneMergedPullRequests(
+	ctx context.Context,
+	mergedBefore time.Time,
+	mode domains.RetentionMode,
+	limit int,
+) (int, int, error) {
+	const op = "repository.postgres.PruneMergedPullRequests"
+
+	org := tenant.Org(ctx)
+
+	var prs, reviews int
+	err := s.inTx(ctx, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			SELECT id FROM pull_requests
+			WHERE org = $4 AND status = $1 AND merged_at < $2
+			ORDER BY merged_at, id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		`, domains.PRStatusMerged, mergedBefore, limit, org)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		defer rows.Close()
+
+		var ids []string
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+			ids = append(ids, id)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		rows.Close()
+
+		prs = len(ids)
+		if prs == 0 {
+			return nil
+		}
+
+		if err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM reviewers WHERE org = $2 AND pull_request_id = ANY($1)`, ids, org).
+			Scan(&reviews); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO review_stats (org, user_id, authored)
+			SELECT org, author_id, COUNT(*) FROM pull_requests WHERE org = $2 AND id = ANY($1) GROUP BY org, author_id
+			ON CONFLICT (org, user_id) DO UPDATE SET authored = review_stats.authored + EXCLUDED.authored
+		`, ids, org)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO review_stats (org, user_id, reviewed)
+			SELECT org, user_id, COUNT(*) FROM reviewers WHERE org = $2 AND pull_request_id = ANY($1) GROUP BY org, user_id
+			ON CONFLICT (org, user_id) DO UPDATE SET reviewed = review_stats.reviewed + EXCLUDED.reviewed
+		`, ids, org)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if mode == domains.RetentionArchive {
+			_, err = tx.Exec(ctx, `
+				INSERT INTO pull_requests_archive
+					(org, id, name, description, labels, author_id, created_at, merged_at, archived_at)
+				SELECT org, id, name, description, labels, author_id, created_at, merged_at, NOW()
+				FROM pull_requests
+				WHERE org = $2 AND id = ANY($1)
+			`, ids, org)
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			_, err = tx.Exec(ctx, `
+				INSERT INTO reviewers_archive (org, user_id, pull_request_id, archived_at, assigned_at)
+				SELECT org, user_id, pull_request_id, NOW(), assigned_at
+				FROM reviewers
+				WHERE org = $2 AND pull_request_id = ANY($1)
+			`, ids, org)
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+
+		// reviewers go with the pull requests: the merged-PR trigger only guards direct changes
+		if _, err = tx.Exec(ctx, `DELETE FROM pull_requests WHERE org = $2 AND id = ANY($1)`, ids, org); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return prs, reviews, nil
+}
+
+// GetReviewStats counts the pull requests the user authored and reviewed, adding those
+// already removed by retention.
+func (s *Storage) GetReviewStats(ctx context.Context, userID string) (*domains.ReviewStats, error) {
+	const op = "repository.postgres.GetReviewStats"
+
+	stats := domains.ReviewStats{UserID: userID}
+	err := s.conn(ctx).QueryRow(ctx, `
+		SELECT (SELECT COUNT(*) FROM pull_requests WHERE org = $2 AND author_id = $1)
+		           + COALESCE((SELECT authored FROM review_stats WHERE org = $2 AND user_id = $1), 0),
+		       (SELECT COUNT(*) FROM reviewers WHERE org = $2 AND user_id = $1)
+		           + COALESCE((SELECT reviewed FROM review_stats WHERE org = $2 AND user_id = $1), 0)
+	`, userID, tenant.Org(ctx)).Scan(&stats.Authored, &stats.Reviewed)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &stats, nil
+}
@@ -0,0 +1,371 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/lib/tenant"
+	"github.com/Deymos01/pr-review-manager/internal/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+// ExportSnapshot reads the whole state of the organization, including the archive and the
+// review stats, within one read-only transaction so the snapshot is consistent.
+func (s *Storage) ExportSnapshot(ctx context.Context) (*domains.Snapshot, error) {
+	const op = "repository.postgres.ExportSnapshot"
+
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	org := tenant.Org(ctx)
+	snap := &domains.Snapshot{Statuses: domains.PRStatuses}
+
+	if err = tx.QueryRow(ctx, `SELECT NOW()`).Scan(&snap.CreatedAt); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	snap.Teams, err = queryStrings(ctx, tx, `SELECT name FROM teams WHERE org = $1 ORDER BY name`, org)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT id, name, team_name, is_active, role, deleted_at
+		FROM users
+		WHERE org = $1
+		ORDER BY id
+	`, org)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for rows.Next() {
+		var user domains.User
+		if err = rows.Scan(&user.ID, &user.Name, &user.TeamName, &user.IsActive, &user.Role, &user.DeletedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		snap.Users = append(snap.Users, &user)
+	}
+	if err = rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	rows.Close()
+
+	rows, err = tx.Query(ctx, `
+		SELECT pr.id, pr.name, pr.description, pr.labels, pr.author_id, pr.status,
+			pr.need_more_reviewers, pr.created_at, pr.merged_at
+		FROM pull_requests pr
+		WHERE pr.org = $1
+		ORDER BY pr.id
+	`, org)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	byID := make(map[string]*domains.PullRequest)
+	for rows.Next() {
+		var pr domains.PullRequest
+		pr.Author = &domains.User{}
+		err = rows.Scan(&pr.ID, &pr.Name, &pr.Description, &pr.Labels, &pr.Author.ID, &pr.Status, &pr.NeedMoreReviewers, &pr.CreatedAt, &pr.MergedAt)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		snap.PullRequests = append(snap.PullRequests, &pr)
+		byID[pr.ID] = &pr
+	}
+	if err = rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	rows.Close()
+
+	rows, err = tx.Query(ctx, `
+		SELECT pull_request_id, user_id, assigned_at
+		FROM reviewers
+		WHERE org = $1
+		ORDER BY pull_request_id, assigned_at, user_id
+	`, org)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for rows.Next() {
+		var (
+			prID     string
+			reviewer domains.Reviewer
+		)
+		reviewer.User = &domains.User{}
+		if err = rows.Scan(&prID, &reviewer.User.ID, &reviewer.AssignedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if pr, ok := byID[prID]; ok {
+			pr.Reviewers = append(pr.Reviewers, &reviewer)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	rows.Close()
+
+	if snap.ArchivedPullRequests, err = exportArchive(ctx, tx); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if snap.ReviewStats, err = exportReviewStats(ctx, tx); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return snap, nil
+}
+
+// archiveKey identifies an archived pull request: the same id may be archived more than once.
+type archiveKey struct {
+	id         string
+	archivedAt int64
+}
+
+func exportArchive(ctx context.Context, tx pgx.Tx) ([]*domains.ArchivedPullRequest, error) {
+	org := tenant.Org(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT id, name, description, labels, author_id, created_at, merged_at, archived_at
+		FROM pull_requests_archive
+		WHERE org = $1
+		ORDER BY archived_at, id
+	`, org)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var archive []*domains.ArchivedPullRequest
+	byKey := make(map[archiveKey]*domains.PullRequest)
+	for rows.Next() {
+		var (
+			archived domains.ArchivedPullRequest
+			pr       = &domains.PullRequest{Author: &domains.User{}, Status: domains.PRStatusMerged}
+		)
+		err = rows.Scan(&pr.ID, &pr.Name, &pr.Description, &pr.Labels, &pr.Author.ID, &pr.CreatedAt, &pr.MergedAt, &archived.ArchivedAt)
+		if err != nil {
+			return nil, err
+		}
+		archived.PullRequest = pr
+		archive = append(archive, &archived)
+		byKey[archiveKey{id: pr.ID, archivedAt: archived.ArchivedAt.UnixNano()}] = pr
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	rows, err = tx.Query(ctx, `
+		SELECT pull_request_id, archived_at, user_id, assigned_at
+		FROM reviewers_archive
+		WHERE org = $1
+		ORDER BY pull_request_id, archived_at, assigned_at, user_id
+	`, org)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var (
+			prID       string
+			archivedAt time.Time
+			reviewer   = domains.Reviewer{User: &domains.User{}}
+		)
+		if err = rows.Scan(&prID, &archivedAt, &reviewer.User.ID, &reviewer.AssignedAt); err != nil {
+			return nil, err
+		}
+		if pr, ok := byKey[archiveKey{id: prID, archivedAt: archivedAt.UnixNano()}]; ok {
+			pr.Reviewers = append(pr.Reviewers, &reviewer)
+		}
+	}
+
+	return archive, rows.Err()
+}
+
+func exportReviewStats(ctx context.Context, tx pgx.Tx) ([]*domains.ReviewStats, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT user_id, authored, reviewed FROM review_stats WHERE org = $1 ORDER BY user_id
+	`, tenant.Org(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*domains.ReviewStats
+	for rows.Next() {
+		var stats domains.ReviewStats
+		if err := rows.Scan(&stats.UserID, &stats.Authored, &stats.Reviewed); err != nil {
+			return nil, err
+		}
+		out = append(out, &stats)
+	}
+
+	return out, rows.Err()
+}
+
+// RestoreSnapshot loads the snapshot, including the archive and the review stats, into an
+// empty organization in one transaction, retried on serialization failures. It returns
+// repository.ErrStorageNotEmpty if the organization already has any team, user or pull request.
+func (s *Storage) RestoreSnapshot(ctx context.Context, snap *domains.Snapshot) error {
+	const op = "repository.postgres.RestoreSnapshot"
+
+	err := s.inTx(ctx, func(tx pgx.Tx) error {
+		return restoreSnapshot(ctx, tx, snap)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func restoreSnapshot(ctx context.Context, tx pgx.Tx, snap *domains.Snapshot) error {
+	// Block concurrent writers while the emptiness check and the restore run
+	_, err := tx.Exec(ctx, `
+		LOCK TABLE teams, users, pull_requests, reviewers,
+			pull_requests_archive, reviewers_archive, review_stats IN EXCLUSIVE MODE
+	`)
+	if err != nil {
+		return err
+	}
+
+	org := tenant.Org(ctx)
+
+	var notEmpty bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM teams WHERE org = $1)
+		    OR EXISTS(SELECT 1 FROM users WHERE org = $1)
+		    OR EXISTS(SELECT 1 FROM pull_requests WHERE org = $1)
+		    OR EXISTS(SELECT 1 FROM pull_requests_archive WHERE org = $1)
+	`, org).Scan(&notEmpty)
+	if err != nil {
+		return err
+	}
+	if notEmpty {
+		return repository.ErrStorageNotEmpty
+	}
+
+	for _, team := range snap.Teams {
+		if _, err = tx.Exec(ctx, `INSERT INTO teams (org, name) VALUES ($1, $2)`, org, team); err != nil {
+			return err
+		}
+	}
+
+	for _, user := range snap.Users {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO users (org, id, name, team_name, is_active, role, deleted_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, org, user.ID, user.Name, user.TeamName, user.IsActive, user.Role, user.DeletedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Reviewers of a merged PR are frozen, so every PR is inserted as OPEN
+	// and gets its final status once the reviewers are in place.
+	for _, pr := range snap.PullRequests {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO pull_requests (org, id, name, description, labels, author_id, status,
+			                           need_more_reviewers, created_at, merged_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		`, org, pr.ID, pr.Name, pr.Description, labelsOrEmpty(pr.Labels), pr.Author.ID, domains.PRStatusOpen,
+			pr.NeedMoreReviewers, pr.CreatedAt, pr.MergedAt)
+		if err != nil {
+			return err
+		}
+
+		for _, reviewer := range pr.Reviewers {
+			assignedAt := reviewer.AssignedAt
+			if assignedAt.IsZero() {
+				assignedAt = time.Now()
+			}
+			_, err = tx.Exec(ctx, `
+				INSERT INTO reviewers (org, pull_request_id, user_id, assigned_at)
+				VALUES ($1, $2, $3, $4)
+			`, org, pr.ID, reviewer.User.ID, assignedAt)
+			if err != nil {
+				return err
+			}
+		}
+
+		if pr.Status != domains.PRStatusOpen {
+			_, err = tx.Exec(ctx, `UPDATE pull_requests SET status = $2 WHERE org = $3 AND id = $1`, pr.ID, pr.Status, org)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, archived := range snap.ArchivedPullRequests {
+		pr := archived.PullRequest
+		_, err = tx.Exec(ctx, `
+			INSERT INTO pull_requests_archive
+				(org, id, name, description, labels, author_id, created_at, merged_at, archived_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, org, pr.ID, pr.Name, pr.Description, labelsOrEmpty(pr.Labels), pr.Author.ID, pr.CreatedAt,
+			pr.MergedAt, archived.ArchivedAt)
+		if err != nil {
+			return err
+		}
+
+		for _, reviewer := range pr.Reviewers {
+			_, err = tx.Exec(ctx, `
+				INSERT INTO reviewers_archive (org, user_id, pull_request_id, archived_at, assigned_at)
+				VALUES ($1, $2, $3, $4, $5)
+			`, org, reviewer.User.ID, pr.ID, archived.ArchivedAt, reviewer.AssignedAt)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, stats := range snap.ReviewStats {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO review_stats (org, user_id, authored, reviewed) VALUES ($1, $2, $3, $4)
+		`, org, stats.UserID, stats.Authored, stats.Reviewed)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func queryStrings(ctx context.Context, tx pgx.Tx, query string, args ...any) ([]string, error) {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+
+	return out, rows.Err()
+}
+
+// labelsOrEmpty keeps the NOT NULL labels column satisfied for snapshots without labels.
+func labelsOrEmpty(labels []string) []string {
+	if labels == nil {
+		return []string{}
+	}
+	return labels
+}
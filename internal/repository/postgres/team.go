@@ -2,51 +2,87 @@ package postgres
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"fmt"
 	"math/rand"
 
 	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/lib/tenant"
 	"github.com/Deymos01/pr-review-manager/internal/repository"
-	"github.com/lib/pq"
+	"github.com/jackc/pgx/v5"
 )
 
+// queryBumpTeamVersion marks a team as changed whenever its members are modified.
+const queryBumpTeamVersion = `UPDATE teams SET version = version + 1 WHERE org = $1 AND name = $2`
+
 func (s *Storage) CreateTeam(ctx context.Context, team *domains.Team) error {
 	const op = "storage.postgres.CreateTeam"
 
-	tx, err := s.db.BeginTx(ctx, nil)
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		query := `INSERT INTO teams (org, name) VALUES ($1, $2)`
+		if _, err := tx.Exec(ctx, query, tenant.Org(ctx), team.Name); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := upsertTeamMembers(ctx, tx, team); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	})
+}
+
+// upsertTeamMembers creates the team members or moves existing users into the team,
+// overwriting their name, activity and role. An omitted role keeps the current one, so
+// re-posting a team does not demote its leads. Teams losing members get their version bumped.
+// Deleted users cannot be brought back and fail the upsert with repository.ErrUserDeleted.
+func upsertTeamMembers(ctx context.Context, tx pgx.Tx, team *domains.Team) error {
+	org := tenant.Org(ctx)
+	ids := make([]string, 0, len(team.Members))
+	for _, member := range team.Members {
+		ids = append(ids, member.ID)
+	}
+
+	// IDs of offboarded users stay retired
+	var deleted bool
+	err := tx.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM users WHERE org = $1 AND id = ANY($2) AND deleted_at IS NOT NULL)
+	`, org, ids).Scan(&deleted)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return err
+	}
+	if deleted {
+		return repository.ErrUserDeleted
 	}
-	defer func() { _ = tx.Rollback() }()
 
-	query := `INSERT INTO teams (name) VALUES ($1)`
-	_, err = tx.ExecContext(ctx, query, team.Name)
+	_, err = tx.Exec(ctx, `
+		UPDATE teams SET version = version + 1
+		WHERE org = $3 AND name IN (SELECT team_name FROM users WHERE org = $3 AND id = ANY($1) AND team_name <> $2)
+	`, ids, team.Name, org)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return err
 	}
 
+	// an omitted role keeps the current one, new users default to member
+	query := `INSERT INTO users (org, id, name, is_active, team_name, role)
+				VALUES ($1, $2, $3, $4, $5, COALESCE(NULLIF($6::text, ''), 'member'))
+				ON CONFLICT (org, id) DO UPDATE SET name = EXCLUDED.name, is_active = EXCLUDED.is_active,
+				    team_name = EXCLUDED.team_name, role = COALESCE(NULLIF($6::text, ''), users.role)`
+
 	for _, member := range team.Members {
-		query = `INSERT INTO users (id, name, is_active, team_name) VALUES ($1, $2, $3, $4)
-					ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, is_active = EXCLUDED.is_active, team_name = EXCLUDED.team_name`
-		_, err := tx.ExecContext(ctx, query, member.ID, member.Name, member.IsActive, member.TeamName)
+		_, err := tx.Exec(ctx, query, org, member.ID, member.Name, member.IsActive, team.Name, string(member.Role))
 		if err != nil {
-			return fmt.Errorf("%s: %w", op, err)
+			return err
 		}
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("%s: %w", op, err)
-	}
-
 	return nil
 }
 
 func (s *Storage) TeamExists(ctx context.Context, name string) (bool, error) {
 	var exists bool
-	query := `SELECT EXISTS(SELECT 1 FROM teams WHERE name = $1)`
-	err := s.db.QueryRowContext(ctx, query, name).Scan(&exists)
+	query := `SELECT EXISTS(SELECT 1 FROM teams WHERE org = $1 AND name = $2)`
+	err := s.conn(ctx).QueryRow(ctx, query, tenant.Org(ctx), name).Scan(&exists)
 	if err != nil {
 		return false, repository.ErrTeamNotFound
 	}
@@ -54,26 +90,54 @@ func (s *Storage) TeamExists(ctx context.Context, name string) (bool, error) {
 	return exists, nil
 }
 
+func (s *Storage) UserIsTeamLead(ctx context.Context, userID, teamName string) (bool, error) {
+	const op = "storage.postgres.UserIsTeamLead"
+
+	query := `SELECT EXISTS(
+				SELECT 1 FROM users
+				WHERE org = $3 AND id = $1 AND team_name = $2 AND role = 'lead' AND is_active = TRUE
+			)`
+
+	var isLead bool
+	err := s.conn(ctx).QueryRow(ctx, query, userID, teamName, tenant.Org(ctx)).Scan(&isLead)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return isLead, nil
+}
+
 func (s *Storage) GetTeamByName(ctx context.Context, name string) (*domains.Team, error) {
 	const op = "storage.postgres.GetTeamByName"
 
+	org := tenant.Org(ctx)
+
 	var team domains.Team
-	query := `SELECT users.id, users.name, users.is_active FROM teams
-				JOIN users ON teams.name = users.team_name
-				WHERE teams.name = $1`
-	rows, err := s.db.QueryContext(ctx, query, name)
+	err := s.conn(ctx).QueryRow(ctx, `SELECT version FROM teams WHERE org = $1 AND name = $2`, org, name).
+		Scan(&team.Version)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, repository.ErrTeamNotFound
 		}
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	defer func() { _ = rows.Close() }()
+
+	query := `SELECT users.id, users.name, users.is_active, users.role FROM teams
+				JOIN users ON teams.org = users.org AND teams.name = users.team_name
+				WHERE teams.org = $1 AND teams.name = $2`
+	rows, err := s.conn(ctx).Query(ctx, query, org, name)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrTeamNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
 
 	var users []*domains.User
 	for rows.Next() {
 		var user domains.User
-		if err := rows.Scan(&user.ID, &user.Name, &user.IsActive); err != nil {
+		if err := rows.Scan(&user.ID, &user.Name, &user.IsActive, &user.Role); err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
 		user.TeamName = &name
@@ -89,25 +153,62 @@ func (s *Storage) GetTeamByName(ctx context.Context, name string) (*domains.Team
 	return &team, nil
 }
 
+// DeactivateTeamMembers deactivates the given members and moves their open reviews to the
+// remaining active members. The team row is locked for the whole transaction; a non-zero
+// ifVersion must match the stored team version.
 func (s *Storage) DeactivateTeamMembers(
 	ctx context.Context,
 	teamName string,
 	userIDs []string,
+	ifVersion int64,
+) (*domains.Team, []*domains.ReassignedPR, error) {
+	var (
+		team       *domains.Team
+		reassigned []*domains.ReassignedPR
+	)
+	err := s.inTx(ctx, func(tx pgx.Tx) error {
+		var err error
+		team, reassigned, err = deactivateTeamMembers(ctx, tx, teamName, userIDs, ifVersion)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return team, reassigned, nil
+}
+
+func deactivateTeamMembers(
+	ctx context.Context,
+	tx pgx.Tx,
+	teamName string,
+	userIDs []string,
+	ifVersion int64,
 ) (*domains.Team, []*domains.ReassignedPR, error) {
 	const op = "storage.postgres.DeactivateTeamMembers"
 
-	tx, err := s.db.BeginTx(ctx, nil)
+	var err error
+	org := tenant.Org(ctx)
+
+	var version int64
+	err = tx.QueryRow(ctx, `SELECT version FROM teams WHERE org = $1 AND name = $2 FOR UPDATE`, org, teamName).
+		Scan(&version)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, repository.ErrTeamNotFound
+		}
 		return nil, nil, fmt.Errorf("%s: %w", op, err)
 	}
-	defer func() { _ = tx.Rollback() }()
+	if ifVersion != 0 && version != ifVersion {
+		return nil, nil, repository.ErrVersionConflict
+	}
 
-	userIDsPq := pq.Array(userIDs)
+	userIDsPq := userIDs
 	// Ensure all users belong to the team
 	query := `SELECT id FROM users
-				WHERE team_name = $1 AND id = ANY($2)`
+				WHERE org = $3 AND team_name = $1 AND id = ANY($2)`
 
-	rows, err := tx.QueryContext(ctx, query, teamName, userIDsPq)
+	rows, err := tx.Query(ctx, query, teamName, userIDsPq, org)
 	if err != nil {
 		return nil, nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -120,25 +221,25 @@ func (s *Storage) DeactivateTeamMembers(
 		}
 		found = append(found, id)
 	}
-	_ = rows.Close()
+	rows.Close()
 
 	if len(found) != len(userIDs) {
 		return nil, nil, repository.ErrTeamCompatibility
 	}
 
 	// Deactivate users
-	_, err = tx.ExecContext(ctx,
+	_, err = tx.Exec(ctx,
 		`UPDATE users
 				SET is_active = FALSE
-				WHERE id = ANY($1)`, userIDsPq)
+				WHERE org = $2 AND id = ANY($1)`, userIDsPq, org)
 	if err != nil {
 		return nil, nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	// Get available candidates for reassignment
-	rows, err = tx.QueryContext(ctx,
+	// Get available candidates for reassignment (observers are never assigned)
+	rows, err = tx.Query(ctx,
 		`SELECT id FROM users
-				WHERE team_name = $1 AND is_active = TRUE`, teamName)
+				WHERE org = $2 AND team_name = $1 AND is_active = TRUE AND role <> 'observer'`, teamName, org)
 	if err != nil {
 		return nil, nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -151,12 +252,14 @@ func (s *Storage) DeactivateTeamMembers(
 		}
 		activeMembers = append(activeMembers, id)
 	}
-	_ = rows.Close()
-
-	// Find PRs where deactivated users are reviewers
-	rows, err = tx.QueryContext(ctx,
-		`SELECT user_id, pull_request_id FROM reviewers
-				WHERE user_id = ANY($1)`, userIDsPq)
+	rows.Close()
+
+	// Find PRs where deactivated users are reviewers; merged PRs keep their historical reviewers
+	rows, err = tx.Query(ctx,
+		`SELECT rev.user_id, rev.pull_request_id FROM reviewers rev
+				JOIN pull_requests pr ON rev.org = pr.org AND rev.pull_request_id = pr.id
+				WHERE rev.org = $3 AND rev.user_id = ANY($1) AND pr.status = ANY($2)`,
+		userIDsPq, repository.ReassignableStatuses, org)
 	if err != nil {
 		return nil, nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -174,18 +277,22 @@ func (s *Storage) DeactivateTeamMembers(
 		}
 		affected = append(affected, p)
 	}
-	_ = rows.Close()
+	rows.Close()
 
 	var reassigned []*domains.ReassignedPR
 
 	// Reassign PRs
 	for _, a := range affected {
+		if _, err = tx.Exec(ctx, queryBumpVersion, org, a.prID); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+
 		if len(activeMembers) == 0 {
 			// no active members → just remove reviewer
-			_, err = tx.ExecContext(ctx, `
+			_, err = tx.Exec(ctx, `
                 DELETE FROM reviewers
-                WHERE user_id = $1 AND pull_request_id = $2
-            `, a.userID, a.prID)
+                WHERE org = $3 AND user_id = $1 AND pull_request_id = $2
+            `, a.userID, a.prID, org)
 			if err != nil {
 				return nil, nil, fmt.Errorf("%s: %w", op, err)
 			}
@@ -194,10 +301,10 @@ func (s *Storage) DeactivateTeamMembers(
 
 		// other reviewers in this PR
 		otherReviewers := make(map[string]struct{})
-		rows, err = tx.QueryContext(ctx, `
+		rows, err = tx.Query(ctx, `
 			SELECT user_id FROM reviewers
-			WHERE pull_request_id = $1 AND user_id != $2
-		`, a.prID, a.userID)
+			WHERE org = $3 AND pull_request_id = $1 AND user_id != $2
+		`, a.prID, a.userID, org)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -209,14 +316,14 @@ func (s *Storage) DeactivateTeamMembers(
 			}
 			otherReviewers[id] = struct{}{}
 		}
-		_ = rows.Close()
+		rows.Close()
 
 		// Get author of the PR
 		var prAuthor string
-		err = tx.QueryRowContext(ctx, `
+		err = tx.QueryRow(ctx, `
 			SELECT author_id FROM pull_requests
-			WHERE id = $1
-		`, a.prID).Scan(&prAuthor)
+			WHERE org = $2 AND id = $1
+		`, a.prID, org).Scan(&prAuthor)
 		if err != nil {
 			return nil, nil, fmt.Errorf("%s: %w", op, err)
 		}
@@ -230,10 +337,10 @@ func (s *Storage) DeactivateTeamMembers(
 		}
 		if len(candidates) == 0 {
 			// no suitable candidates, just remove reviewer
-			_, err = tx.ExecContext(ctx, `
+			_, err = tx.Exec(ctx, `
 				DELETE FROM reviewers
-				WHERE user_id = $1 AND pull_request_id = $2
-			`, a.userID, a.prID)
+				WHERE org = $3 AND user_id = $1 AND pull_request_id = $2
+			`, a.userID, a.prID, org)
 			if err != nil {
 				return nil, nil, fmt.Errorf("%s: %w", op, err)
 			}
@@ -243,19 +350,19 @@ func (s *Storage) DeactivateTeamMembers(
 		newReviewer := candidates[rand.Intn(len(candidates))]
 
 		// remove old reviewer
-		_, err = tx.ExecContext(ctx, `
+		_, err = tx.Exec(ctx, `
             DELETE FROM reviewers
-            WHERE user_id = $1 AND pull_request_id = $2
-        `, a.userID, a.prID)
+            WHERE org = $3 AND user_id = $1 AND pull_request_id = $2
+        `, a.userID, a.prID, org)
 		if err != nil {
 			return nil, nil, err
 		}
 
 		// add new reviewer
-		_, err = tx.ExecContext(ctx, `
-            INSERT INTO reviewers (user_id, pull_request_id)
-            VALUES ($1, $2)
-        `, newReviewer, a.prID)
+		_, err = tx.Exec(ctx, `
+            INSERT INTO reviewers (org, user_id, pull_request_id)
+            VALUES ($3, $1, $2)
+        `, newReviewer, a.prID, org)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -267,12 +374,16 @@ func (s *Storage) DeactivateTeamMembers(
 		})
 	}
 
+	if _, err = tx.Exec(ctx, queryBumpTeamVersion, org, teamName); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	// Get updated team
-	team := &domains.Team{Name: teamName}
+	team := &domains.Team{Name: teamName, Version: version + 1}
 
-	rows, err = tx.QueryContext(ctx,
-		`SELECT id, name, is_active FROM users
-				WHERE team_name = $1`, teamName)
+	rows, err = tx.Query(ctx,
+		`SELECT id, name, is_active, role FROM users
+				WHERE org = $2 AND team_name = $1`, teamName, org)
 	if err != nil {
 		return nil, nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -280,18 +391,68 @@ func (s *Storage) DeactivateTeamMembers(
 	var users []*domains.User
 	for rows.Next() {
 		var user domains.User
-		if err := rows.Scan(&user.ID, &user.Name, &user.IsActive); err != nil {
+		if err := rows.Scan(&user.ID, &user.Name, &user.IsActive, &user.Role); err != nil {
 			return nil, nil, fmt.Errorf("%s: %w", op, err)
 		}
 		users = append(users, &user)
 	}
-	_ = rows.Close()
+	rows.Close()
 
 	team.Members = users
 
-	if err := tx.Commit(); err != nil {
-		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	return team, reassigned, nil
+}
+
+func (s *Storage) ListTeams(ctx context.Context, filter domains.TeamFilter) (*domains.TeamPage, error) {
+	const op = "repository.postgres.team.ListTeams"
+
+	direction, cmp := keysetPage(filter.Desc)
+
+	var b whereBuilder
+	b.add("t.org = " + b.arg(tenant.Org(ctx)))
+	if filter.NamePrefix != "" {
+		b.add("lower(t.name) LIKE " + b.arg(likePrefix(filter.NamePrefix)))
+	}
+	if filter.After != nil {
+		b.add(fmt.Sprintf("t.name %s %s", cmp, b.arg(filter.After.Key)))
 	}
 
-	return team, reassigned, nil
+	having := ""
+	if filter.HasActive != nil {
+		having = " HAVING (COUNT(u.id) FILTER (WHERE u.is_active) > 0) = " + b.arg(*filter.HasActive)
+	}
+
+	query := `
+		SELECT t.name, COUNT(u.id), COUNT(u.id) FILTER (WHERE u.is_active)
+		FROM teams t
+		LEFT JOIN users u ON u.org = t.org AND u.team_name = t.name` + b.where() + `
+		GROUP BY t.name` + having +
+		fmt.Sprintf(" ORDER BY t.name %s LIMIT %s", direction, b.arg(filter.Limit+1))
+
+	rows, err := s.conn(ctx).Query(ctx, query, b.args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	page := &domains.TeamPage{Teams: make([]*domains.TeamSummary, 0, filter.Limit)}
+	for rows.Next() {
+		var t domains.TeamSummary
+		if err := rows.Scan(&t.Name, &t.MembersCount, &t.ActiveMembers); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		page.Teams = append(page.Teams, &t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(page.Teams) > filter.Limit {
+		page.Teams = page.Teams[:filter.Limit]
+		last := page.Teams[len(page.Teams)-1].Name
+		page.Next = &domains.Cursor{Key: last, ID: last}
+	}
+
+	return page, nil
 }
@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	// codeSerializationFailure and codeDeadlockDetected abort a transaction that is safe to rerun.
+	codeSerializationFailure = "40001"
+	codeDeadlockDetected     = "40P01"
+
+	maxTxAttempts = 5
+	txRetryDelay  = 10 * time.Millisecond
+)
+
+// TxStats counts transactions run by inTx since the storage was opened.
+type TxStats struct {
+	Started   uint64 `json:"started"`
+	Retries   uint64 `json:"retries"`
+	Exhausted uint64 `json:"exhausted"`
+}
+
+type txMetrics struct {
+	started   atomic.Uint64
+	retries   atomic.Uint64
+	exhausted atomic.Uint64
+}
+
+// TxStats returns transaction retry metrics.
+func (s *Storage) TxStats() TxStats {
+	return TxStats{
+		Started:   s.txMetrics.started.Load(),
+		Retries:   s.txMetrics.retries.Load(),
+		Exhausted: s.txMetrics.exhausted.Load(),
+	}
+}
+
+type txKey struct{}
+
+// WithinTx runs fn in a SERIALIZABLE transaction: every Storage call made with the context
+// passed to fn joins it, and the transaction commits once fn returns nil. On a serialization
+// failure or deadlock fn is rerun from scratch. Nested calls join the outer transaction.
+func (s *Storage) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// conn returns the transaction bound to ctx by WithinTx, or the connection pool.
+func (s *Storage) conn(ctx context.Context) querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+
+	return s.pool
+}
+
+// inTx runs fn in a SERIALIZABLE transaction and commits it. When PostgreSQL aborts the
+// transaction with a serialization failure or a deadlock, fn is rerun from scratch in a new
+// transaction up to maxTxAttempts times, so fn must not leak state between attempts.
+// Inside WithinTx fn runs in the already open transaction instead.
+func (s *Storage) inTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(tx)
+	}
+
+	s.txMetrics.started.Add(1)
+
+	return retryTx(ctx, &s.txMetrics, maxTxAttempts, func() error {
+		return s.runTx(ctx, fn)
+	})
+}
+
+func (s *Storage) runTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	const op = "repository.postgres.runTx"
+
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// retryTx calls run until it succeeds, fails with a non-retryable error or attempts run out,
+// sleeping with jittered exponential backoff between attempts.
+func retryTx(ctx context.Context, metrics *txMetrics, attempts int, run func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := run()
+		if err == nil || !isRetryable(err) {
+			return err
+		}
+		if attempt >= attempts {
+			metrics.exhausted.Add(1)
+			return err
+		}
+		metrics.retries.Add(1)
+
+		delay := txRetryDelay << (attempt - 1)
+		delay += time.Duration(rand.Int63n(int64(delay)))
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+func isRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	return pgErr.Code == codeSerializationFailure || pgErr.Code == codeDeadlockDetected
+}
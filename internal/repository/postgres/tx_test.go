@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryTx(t *testing.T) {
+	serialization := fmt.Errorf("op: %w", &pgconn.PgError{Code: codeSerializationFailure})
+	deadlock := &pgconn.PgError{Code: codeDeadlockDetected}
+	uniqueViolation := &pgconn.PgError{Code: "23505"}
+
+	type testCase struct {
+		name             string
+		errs             []error
+		expectedCalls    int
+		expectedErr      error
+		expectedRetries  uint64
+		expectedExhausts uint64
+	}
+
+	cases := []testCase{
+		{
+			name:          "Success on first attempt",
+			errs:          []error{nil},
+			expectedCalls: 1,
+		},
+		{
+			name:            "Retried after serialization failure and deadlock",
+			errs:            []error{serialization, deadlock, nil},
+			expectedCalls:   3,
+			expectedRetries: 2,
+		},
+		{
+			name:          "Other errors are not retried",
+			errs:          []error{uniqueViolation},
+			expectedCalls: 1,
+			expectedErr:   uniqueViolation,
+		},
+		{
+			name:             "Attempts exhausted",
+			errs:             []error{serialization, serialization, serialization},
+			expectedCalls:    3,
+			expectedErr:      serialization,
+			expectedRetries:  2,
+			expectedExhausts: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				metrics txMetrics
+				calls   int
+			)
+			err := retryTx(context.Background(), &metrics, 3, func() error {
+				err := tc.errs[calls]
+				calls++
+				return err
+			})
+
+			require.Equal(t, tc.expectedCalls, calls)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.expectedRetries, metrics.retries.Load())
+			require.Equal(t, tc.expectedExhausts, metrics.exhausted.Load())
+		})
+	}
+}
+
+func TestRetryTx_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var metrics txMetrics
+	calls := 0
+	err := retryTx(ctx, &metrics, maxTxAttempts, func() error {
+		calls++
+		return &pgconn.PgError{Code: codeSerializationFailure}
+	})
+
+	require.Error(t, err)
+	require.Equal(t, 1, calls)
+	require.False(t, isRetryable(errors.New("plain")))
+}
@@ -2,9 +2,16 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/lib/tenant"
+	"github.com/Deymos01/pr-review-manager/internal/repository"
+	"github.com/jackc/pgx/v5"
 )
 
 func (s *Storage) UserExists(ctx context.Context, userID string) (bool, error) {
@@ -14,12 +21,12 @@ func (s *Storage) UserExists(ctx context.Context, userID string) (bool, error) {
 		SELECT EXISTS (
 			SELECT 1
 			FROM users
-			WHERE id = $1
+			WHERE org = $1 AND id = $2 AND deleted_at IS NULL
 		);
 	`
 
 	var exists bool
-	err := s.db.QueryRowContext(ctx, query, userID).Scan(&exists)
+	err := s.conn(ctx).QueryRow(ctx, query, tenant.Org(ctx), userID).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
@@ -34,12 +41,12 @@ func (s *Storage) UserHasActiveTeam(ctx context.Context, userID string) (bool, e
 		SELECT EXISTS (
 			SELECT 1
 			FROM users
-			WHERE id = $1 AND team_name IS NOT NULL
+			WHERE org = $1 AND id = $2 AND team_name IS NOT NULL
 		)
 	`
 
 	var exists bool
-	err := s.db.QueryRowContext(ctx, query, userID).Scan(&exists)
+	err := s.conn(ctx).QueryRow(ctx, query, tenant.Org(ctx), userID).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
@@ -47,56 +54,125 @@ func (s *Storage) UserHasActiveTeam(ctx context.Context, userID string) (bool, e
 	return exists, nil
 }
 
+func (s *Storage) GetUserByID(ctx context.Context, userID string) (*domains.User, error) {
+	const op = "repository.postgres.user.GetUserByID"
+
+	query := `
+		SELECT id, name, team_name, is_active, role
+		FROM users
+		WHERE org = $1 AND id = $2 AND deleted_at IS NULL
+	`
+
+	var user domains.User
+	err := s.conn(ctx).QueryRow(ctx, query, tenant.Org(ctx), userID).
+		Scan(&user.ID, &user.Name, &user.TeamName, &user.IsActive, &user.Role)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &user, nil
+}
+
 func (s *Storage) SetUserStatus(ctx context.Context, userID string, isActive bool) (*domains.User, error) {
 	const op = "repository.postgres.user.SetUserIsActive"
 
 	query := `
-		UPDATE users
-		SET is_active = $1
-		WHERE id = $2
-		RETURNING id, name, team_name, is_active
+		WITH updated AS (
+			UPDATE users
+			SET is_active = $1
+			WHERE org = $3 AND id = $2 AND deleted_at IS NULL
+			RETURNING id, name, team_name, is_active
+		), bumped AS (
+			UPDATE teams
+			SET version = version + 1
+			WHERE org = $3 AND name = (SELECT team_name FROM updated)
+		)
+		SELECT id, name, team_name, is_active FROM updated
 	`
 
 	var user domains.User
-	err := s.db.QueryRowContext(ctx, query, isActive, userID).
+	err := s.conn(ctx).QueryRow(ctx, query, isActive, userID, tenant.Org(ctx)).
 		Scan(&user.ID, &user.Name, &user.TeamName, &user.IsActive)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, repository.ErrUserNotFound)
+		}
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return &user, nil
 }
 
-func (s *Storage) UsersReview(ctx context.Context, userID string) ([]*domains.PullRequest, error) {
+// UsersReview returns a page of the user's review assignments ordered by assigned_at.
+func (s *Storage) UsersReview(ctx context.Context, userID string, filter domains.ReviewFilter) (*domains.ReviewPage, error) {
 	const op = "repository.postgres.user.GetUsersReview"
 
-	query := `SELECT pr.id, pr.name, pr.author_id, st.name
-				FROM pull_requests pr
-				JOIN statuses st ON pr.status_id = st.id
-				JOIN reviewers rev ON pr.id = rev.pull_request_id
-				WHERE rev.user_id = $1`
+	direction, cmp := keysetPage(filter.Desc)
 
-	rows, err := s.db.QueryContext(ctx, query, userID)
+	var b whereBuilder
+	b.add("rev.org = " + b.arg(tenant.Org(ctx)))
+	b.add("rev.user_id = " + b.arg(userID))
+	if filter.Status != "" {
+		b.add("pr.status = " + b.arg(filter.Status))
+	}
+	if filter.Since != nil {
+		b.add("rev.assigned_at >= " + b.arg(*filter.Since))
+	}
+	if filter.AuthorID != "" {
+		b.add("pr.author_id = " + b.arg(filter.AuthorID))
+	}
+	if filter.After != nil {
+		assignedAt, err := time.Parse(time.RFC3339Nano, filter.After.Key)
+		if err != nil {
+			return nil, repository.ErrInvalidCursor
+		}
+		b.add(fmt.Sprintf("(rev.assigned_at, pr.id) %s (%s, %s)", cmp, b.arg(assignedAt), b.arg(filter.After.ID)))
+	}
+
+	query := `
+		SELECT pr.id, pr.name, pr.author_id, pr.status, pr.created_at, pr.merged_at, rev.assigned_at
+		FROM reviewers rev
+		JOIN pull_requests pr ON pr.org = rev.org AND pr.id = rev.pull_request_id` + b.where() +
+		fmt.Sprintf(" ORDER BY rev.assigned_at %s, pr.id %s LIMIT %s", direction, direction, b.arg(filter.Limit+1))
+
+	rows, err := s.conn(ctx).Query(ctx, query, b.args...)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	defer func() { _ = rows.Close() }()
+	defer rows.Close()
 
-	var reviews []*domains.PullRequest
+	page := &domains.ReviewPage{Reviews: make([]*domains.Review, 0, filter.Limit)}
 	for rows.Next() {
-		var pr domains.PullRequest
+		var (
+			pr     domains.PullRequest
+			review domains.Review
+		)
 		pr.Author = &domains.User{}
-		if err := rows.Scan(&pr.ID, &pr.Name, &pr.Author.ID, &pr.Status); err != nil {
+		err := rows.Scan(&pr.ID, &pr.Name, &pr.Author.ID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &review.AssignedAt)
+		if err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
-		reviews = append(reviews, &pr)
+		review.PullRequest = &pr
+		page.Reviews = append(page.Reviews, &review)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return reviews, nil
+	if len(page.Reviews) > filter.Limit {
+		page.Reviews = page.Reviews[:filter.Limit]
+		last := page.Reviews[len(page.Reviews)-1]
+		page.Next = &domains.Cursor{
+			Key: last.AssignedAt.Format(time.RFC3339Nano),
+			ID:  last.PullRequest.ID,
+		}
+	}
+
+	return page, nil
 }
 
 func (s *Storage) UserAssigned(ctx context.Context, prID, userID string) (bool, error) {
@@ -106,15 +182,324 @@ func (s *Storage) UserAssigned(ctx context.Context, prID, userID string) (bool,
 		SELECT EXISTS (
 			SELECT 1
 			FROM reviewers
-			WHERE pull_request_id = $1 AND user_id = $2
+			WHERE org = $1 AND pull_request_id = $2 AND user_id = $3
 		);
 	`
 
 	var exists bool
-	err := s.db.QueryRowContext(ctx, query, prID, userID).Scan(&exists)
+	err := s.conn(ctx).QueryRow(ctx, query, tenant.Org(ctx), prID, userID).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return exists, nil
 }
+
+// RebalanceReviews moves a fair share of open review assignments from the most loaded
+// active teammates of userID to userID itself. Each move is returned as a reassignment.
+func (s *Storage) RebalanceReviews(ctx context.Context, userID string) ([]*domains.ReassignedPR, error) {
+	var reassigned []*domains.ReassignedPR
+	err := s.inTx(ctx, func(tx pgx.Tx) error {
+		var err error
+		reassigned, err = rebalanceReviews(ctx, tx, userID)
+		return err
+	})
+
+	return reassigned, err
+}
+
+func rebalanceReviews(ctx context.Context, tx pgx.Tx, userID string) ([]*domains.ReassignedPR, error) {
+	const op = "repository.postgres.user.RebalanceReviews"
+
+	var err error
+	org := tenant.Org(ctx)
+
+	var (
+		teamName sql.NullString
+		role     domains.Role
+		isActive bool
+	)
+	err = tx.QueryRow(ctx, `SELECT team_name, role, is_active FROM users WHERE org = $1 AND id = $2`, org, userID).
+		Scan(&teamName, &role, &isActive)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Observers never review, and users outside of a team have nobody to take load from
+	if !teamName.Valid || !isActive || role == domains.RoleObserver {
+		return nil, nil
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT id FROM users
+		WHERE org = $1 AND team_name = $2 AND is_active = TRUE AND role <> 'observer'
+		FOR UPDATE
+	`, org, teamName.String)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	load := make(map[string]int)
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		load[id] = 0
+	}
+	if err = rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	rows.Close()
+
+	// Open assignments of the team, newest first: those are the least likely to be in progress
+	rows, err = tx.Query(ctx, `
+		SELECT rev.user_id, rev.pull_request_id, pr.author_id
+		FROM reviewers rev
+		JOIN pull_requests pr ON rev.org = pr.org AND rev.pull_request_id = pr.id
+		JOIN users u ON rev.org = u.org AND rev.user_id = u.id
+		WHERE rev.org = $3 AND pr.status = ANY($2) AND u.team_name = $1 AND u.is_active = TRUE
+		  AND u.role <> 'observer'
+		ORDER BY rev.assigned_at DESC, rev.pull_request_id
+	`, teamName.String, repository.ReassignableStatuses, org)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	type assignment struct {
+		prID     string
+		authorID string
+	}
+	assignments := make(map[string][]assignment)
+	reviewing := make(map[string]map[string]struct{})
+	total := 0
+	for rows.Next() {
+		var reviewerID string
+		var a assignment
+		if err = rows.Scan(&reviewerID, &a.prID, &a.authorID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		assignments[reviewerID] = append(assignments[reviewerID], a)
+		if reviewing[a.prID] == nil {
+			reviewing[a.prID] = make(map[string]struct{})
+		}
+		reviewing[a.prID][reviewerID] = struct{}{}
+		load[reviewerID]++
+		total++
+	}
+	if err = rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	rows.Close()
+
+	fairShare := total / len(load)
+
+	donors := make([]string, 0, len(load))
+	for id := range load {
+		if id != userID {
+			donors = append(donors, id)
+		}
+	}
+
+	var reassigned []*domains.ReassignedPR
+	for load[userID] < fairShare {
+		// the most loaded teammate gives away first
+		sort.Slice(donors, func(i, j int) bool {
+			if load[donors[i]] != load[donors[j]] {
+				return load[donors[i]] > load[donors[j]]
+			}
+			return donors[i] < donors[j]
+		})
+
+		moved := false
+		for _, donor := range donors {
+			// moving from a teammate not loaded more than the user would not make things fairer
+			if load[donor] <= load[userID]+1 {
+				break
+			}
+
+			for i, a := range assignments[donor] {
+				if _, ok := reviewing[a.prID][userID]; ok || a.authorID == userID {
+					continue
+				}
+
+				_, err = tx.Exec(ctx, `
+					UPDATE reviewers
+					SET user_id = $1,
+					    assigned_at = NOW()
+					WHERE org = $4 AND pull_request_id = $2 AND user_id = $3
+				`, userID, a.prID, donor, org)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", op, err)
+				}
+				if _, err = tx.Exec(ctx, queryBumpVersion, org, a.prID); err != nil {
+					return nil, fmt.Errorf("%s: %w", op, err)
+				}
+
+				assignments[donor] = append(assignments[donor][:i], assignments[donor][i+1:]...)
+				delete(reviewing[a.prID], donor)
+				reviewing[a.prID][userID] = struct{}{}
+				load[donor]--
+				load[userID]++
+
+				reassigned = append(reassigned, &domains.ReassignedPR{
+					PrID:      a.prID,
+					OldUserID: donor,
+					NewUserID: userID,
+				})
+				moved = true
+				break
+			}
+			if moved {
+				break
+			}
+		}
+		if !moved {
+			break
+		}
+	}
+
+	return reassigned, nil
+}
+
+func (s *Storage) SearchUsers(ctx context.Context, filter domains.UserFilter) (*domains.UserPage, error) {
+	const op = "repository.postgres.user.SearchUsers"
+
+	column := "id"
+	if filter.SortBy == domains.UserSortByName {
+		column = "name"
+	}
+	direction, cmp := keysetPage(filter.Desc)
+
+	var b whereBuilder
+	b.add("org = " + b.arg(tenant.Org(ctx)))
+	b.add("deleted_at IS NULL")
+	if filter.IsActive != nil {
+		b.add("is_active = " + b.arg(*filter.IsActive))
+	}
+	if filter.TeamName != nil {
+		b.add("team_name = " + b.arg(*filter.TeamName))
+	}
+	if filter.NamePrefix != "" {
+		b.add("lower(name) LIKE " + b.arg(likePrefix(filter.NamePrefix)))
+	}
+	if filter.After != nil {
+		b.add(fmt.Sprintf("(%s, id) %s (%s, %s)", column, cmp, b.arg(filter.After.Key), b.arg(filter.After.ID)))
+	}
+
+	query := `SELECT id, name, team_name, is_active, role FROM users` + b.where() +
+		fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT %s", column, direction, direction, b.arg(filter.Limit+1))
+
+	rows, err := s.conn(ctx).Query(ctx, query, b.args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	page := &domains.UserPage{Users: make([]*domains.User, 0, filter.Limit)}
+	for rows.Next() {
+		var u domains.User
+		if err := rows.Scan(&u.ID, &u.Name, &u.TeamName, &u.IsActive, &u.Role); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		page.Users = append(page.Users, &u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(page.Users) > filter.Limit {
+		page.Users = page.Users[:filter.Limit]
+		last := page.Users[len(page.Users)-1]
+		page.Next = &domains.Cursor{Key: last.ID, ID: last.ID}
+		if filter.SortBy == domains.UserSortByName {
+			page.Next.Key = last.Name
+		}
+	}
+
+	return page, nil
+}
+
+// SoftDeleteUser anonymizes the user and marks it deleted, keeping the row for the history
+// of its pull requests and reviews. The user leaves its team, which takes it out of every
+// team query, and is dropped from the open reviews still assigned to it.
+func (s *Storage) SoftDeleteUser(ctx context.Context, userID string) (*domains.User, error) {
+	var user *domains.User
+	err := s.inTx(ctx, func(tx pgx.Tx) error {
+		var err error
+		user, err = softDeleteUser(ctx, tx, userID)
+		return err
+	})
+
+	return user, err
+}
+
+func softDeleteUser(ctx context.Context, tx pgx.Tx, userID string) (*domains.User, error) {
+	const op = "repository.postgres.user.SoftDeleteUser"
+
+	org := tenant.Org(ctx)
+
+	var teamName sql.NullString
+	err := tx.QueryRow(ctx, `
+		SELECT team_name FROM users WHERE org = $1 AND id = $2 AND deleted_at IS NULL FOR UPDATE
+	`, org, userID).
+		Scan(&teamName)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, repository.ErrUserNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE pull_requests
+		SET need_more_reviewers = TRUE,
+		    version = version + 1
+		WHERE org = $3 AND status = ANY($2)
+		  AND id IN (SELECT pull_request_id FROM reviewers WHERE org = $3 AND user_id = $1)
+	`, userID, repository.ReassignableStatuses, org)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM reviewers rev
+		USING pull_requests pr
+		WHERE pr.org = rev.org AND pr.id = rev.pull_request_id
+		  AND rev.org = $3 AND rev.user_id = $1 AND pr.status = ANY($2)
+	`, userID, repository.ReassignableStatuses, org)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var user domains.User
+	err = tx.QueryRow(ctx, `
+		UPDATE users
+		SET name = $2,
+		    is_active = FALSE,
+		    team_name = NULL,
+		    deleted_at = NOW()
+		WHERE org = $3 AND id = $1
+		RETURNING id, name, team_name, is_active, role, deleted_at
+	`, userID, domains.DeletedUserName, org).
+		Scan(&user.ID, &user.Name, &user.TeamName, &user.IsActive, &user.Role, &user.DeletedAt)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if teamName.Valid {
+		if _, err = tx.Exec(ctx, queryBumpTeamVersion, org, teamName.String); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return &user, nil
+}
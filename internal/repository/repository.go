@@ -5,6 +5,13 @@ import "errors"
 var (
 	ErrPRAlreadyExists   = errors.New("pull request already exists")
 	ErrTeamNotFound      = errors.New("team not found")
+	ErrUserNotFound      = errors.New("user not found")
+	ErrPRNotFound        = errors.New("pull request not found")
 	ErrNoCandidate       = errors.New("no available candidate for reassignment")
 	ErrTeamCompatibility = errors.New("some users do not belong to the team")
+	ErrStorageNotEmpty   = errors.New("storage is not empty")
+	ErrInvalidCursor     = errors.New("invalid cursor")
+	ErrPRMerged          = errors.New("pull request is merged")
+	ErrVersionConflict   = errors.New("version conflict")
+	ErrUserDeleted       = errors.New("user is deleted")
 )
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"math/rand"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+)
+
+// NumReviewersToAssign is how many reviewers a new pull request gets when the team allows it.
+const NumReviewersToAssign = 2
+
+// ReassignableStatuses lists PR statuses whose reviewers may still be replaced.
+// A DRAFT status belongs here once it is introduced.
+var ReassignableStatuses = []domains.PRStatus{domains.PRStatusOpen}
+
+// SelectReviewers picks up to NumReviewersToAssign random reviewers from members.
+// If requireLead is set, one of them is guaranteed to be a team lead.
+func SelectReviewers(members, leads []string, requireLead bool) ([]string, error) {
+	reviewers := make([]string, 0, NumReviewersToAssign)
+
+	// candidates is shuffled, so the caller's slice is left untouched
+	candidates := make([]string, 0, len(members))
+	if requireLead {
+		if len(leads) == 0 {
+			return nil, ErrNoCandidate
+		}
+		lead := leads[rand.Intn(len(leads))]
+		reviewers = append(reviewers, lead)
+
+		for _, id := range members {
+			if id != lead {
+				candidates = append(candidates, id)
+			}
+		}
+	} else {
+		candidates = append(candidates, members...)
+	}
+
+	rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	for _, id := range candidates {
+		if len(reviewers) == NumReviewersToAssign {
+			break
+		}
+		reviewers = append(reviewers, id)
+	}
+
+	return reviewers, nil
+}
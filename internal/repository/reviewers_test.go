@@ -0,0 +1,21 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/Deymos01/pr-review-manager/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectReviewersKeepsMembers(t *testing.T) {
+	for _, requireLead := range []bool{false, true} {
+		members := []string{"u1", "u2", "u3", "u4", "u5"}
+
+		for i := 0; i < 20; i++ {
+			reviewers, err := repository.SelectReviewers(members, []string{"u3"}, requireLead)
+			require.NoError(t, err)
+			require.Len(t, reviewers, repository.NumReviewersToAssign)
+			require.Equal(t, []string{"u1", "u2", "u3", "u4", "u5"}, members, "the members are not reordered")
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/lib/tenant"
+)
+
+// ReserveIdempotencyKey claims key within scope for a new request and holds it for lease.
+// It returns nil when the caller now owns the key, or the record left by an earlier request
+// with the same key. Expired keys, and keys whose request let its lease run out without
+// completing, are reclaimed as if they never existed.
+func (s *Storage) ReserveIdempotencyKey(
+	ctx context.Context,
+	scope, key, requestHash string,
+	lease time.Duration,
+) (*domains.IdempotencyRecord, error) {
+	const op = "repository.sqlite.ReserveIdempotencyKey"
+
+	createdAt := now()
+
+	var reserved bool
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO idempotency_keys (org, scope, key, request_hash, created_at, locked_until, expires_at)
+		VALUES (?6, ?1, ?2, ?3, ?5, ?4, ?4)
+		ON CONFLICT (org, scope, key) DO UPDATE
+			SET request_hash     = excluded.request_hash,
+			    status_code      = NULL,
+			    response_headers = NULL,
+			    response_body    = NULL,
+			    created_at       = ?5,
+			    locked_until     = excluded.locked_until,
+			    expires_at       = excluded.expires_at
+			WHERE idempotency_keys.expires_at <= ?5
+			   OR (idempotency_keys.status_code IS NULL AND idempotency_keys.locked_until <= ?5)
+		RETURNING TRUE
+	`, scope, key, requestHash, createdAt.Add(lease), createdAt, tenant.Org(ctx)).Scan(&reserved)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var (
+		record     domains.IdempotencyRecord
+		statusCode sql.NullInt64
+		header     sql.NullString
+	)
+	err = s.db.QueryRowContext(ctx, `
+		SELECT request_hash, status_code, response_headers, response_body
+		FROM idempotency_keys
+		WHERE org = ? AND scope = ? AND key = ?
+	`, tenant.Org(ctx), scope, key).Scan(&record.RequestHash, &statusCode, &header, &record.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if header.Valid {
+		if err := json.Unmarshal([]byte(header.String), &record.Header); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	record.Completed = statusCode.Valid
+	record.StatusCode = int(statusCode.Int64)
+
+	return &record, nil
+}
+
+// CompleteIdempotencyKey stores the response produced for a reserved key, to be replayed
+// for ttl.
+func (s *Storage) CompleteIdempotencyKey(
+	ctx context.Context,
+	scope, key string,
+	statusCode int,
+	header map[string]string,
+	body []byte,
+	ttl time.Duration,
+) error {
+	const op = "repository.sqlite.CompleteIdempotencyKey"
+
+	rawHeader, err := json.Marshal(header)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		UPDATE idempotency_keys
+		SET status_code = ?3, response_headers = ?4, response_body = ?5, locked_until = NULL, expires_at = ?7
+		WHERE org = ?6 AND scope = ?1 AND key = ?2
+	`, scope, key, statusCode, string(rawHeader), body, tenant.Org(ctx), now().Add(ttl))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ReleaseIdempotencyKey forgets a reserved key so that the request may be retried with it.
+func (s *Storage) ReleaseIdempotencyKey(ctx context.Context, scope, key string) error {
+	const op = "repository.sqlite.ReleaseIdempotencyKey"
+
+	_, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE org = ? AND scope = ? AND key = ?`,
+		tenant.Org(ctx), scope, key)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// PurgeIdempotencyKeys deletes expired keys of every organization and returns how many
+// were removed.
+func (s *Storage) PurgeIdempotencyKeys(ctx context.Context) (int64, error) {
+	const op = "repository.sqlite.PurgeIdempotencyKeys"
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= ?`, now())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return n, nil
+}
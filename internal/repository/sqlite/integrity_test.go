@@ -0,0 +1,155 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/config"
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/repository/sqlite"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// integritySnapshot has an open and a merged pull request by u1, both reviewed by u2.
+func integritySnapshot() *domains.Snapshot {
+	team := "backend"
+	mergedAt := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	users := make([]*domains.User, 0, 3)
+	for _, id := range []string{"u1", "u2", "u3"} {
+		users = append(users, &domains.User{ID: id, Name: id, TeamName: &team, IsActive: true, Role: domains.RoleMember})
+	}
+
+	return &domains.Snapshot{
+		Statuses: domains.PRStatuses,
+		Teams:    []string{team},
+		Users:    users,
+		PullRequests: []*domains.PullRequest{
+			{
+				ID:        "pr-merged",
+				Name:      "Merged",
+				Author:    &domains.User{ID: "u1"},
+				Status:    domains.PRStatusMerged,
+				CreatedAt: mergedAt.Add(-time.Hour),
+				MergedAt:  &mergedAt,
+				Reviewers: []*domains.Reviewer{{User: &domains.User{ID: "u2"}}},
+			},
+			{
+				ID:        "pr-open",
+				Name:      "Open",
+				Author:    &domains.User{ID: "u1"},
+				Status:    domains.PRStatusOpen,
+				CreatedAt: mergedAt,
+				Reviewers: []*domains.Reviewer{{User: &domains.User{ID: "u2"}}},
+			},
+		},
+	}
+}
+
+func TestIntegrityConstraints(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		query       string
+		expectedErr string
+	}{
+		{
+			name:        "Author reviews own PR",
+			query:       `INSERT INTO reviewers (org, pull_request_id, user_id, assigned_at) VALUES ('default', 'pr-open', 'u1', '2025-01-03')`,
+			expectedErr: "is the author of pull request pr-open",
+		},
+		{
+			name:        "Reviewer becomes author",
+			query:       `UPDATE pull_requests SET author_id = 'u2' WHERE id = 'pr-open'`,
+			expectedErr: "reviews pull request pr-open",
+		},
+		{
+			name:        "Reviewer added to merged PR",
+			query:       `INSERT INTO reviewers (org, pull_request_id, user_id, assigned_at) VALUES ('default', 'pr-merged', 'u3', '2025-01-03')`,
+			expectedErr: "pull request pr-merged is merged",
+		},
+		{
+			name:        "Reviewer replaced on merged PR",
+			query:       `UPDATE reviewers SET user_id = 'u3' WHERE pull_request_id = 'pr-merged'`,
+			expectedErr: "pull request pr-merged is merged",
+		},
+		{
+			name:        "Reviewer removed from merged PR",
+			query:       `DELETE FROM reviewers WHERE pull_request_id = 'pr-merged'`,
+			expectedErr: "pull request pr-merged is merged",
+		},
+		{
+			name:  "Reviewer added to open PR",
+			query: `INSERT INTO reviewers (org, pull_request_id, user_id, assigned_at) VALUES ('default', 'pr-open', 'u3', '2025-01-03')`,
+		},
+		{
+			name:        "Reviewer from another organization",
+			query:       `INSERT INTO reviewers (org, pull_request_id, user_id, assigned_at) VALUES ('other', 'pr-open', 'u3', '2025-01-03')`,
+			expectedErr: "FOREIGN KEY constraint failed",
+		},
+		{
+			name:  "Merged PR deleted with its reviewers",
+			query: `DELETE FROM pull_requests WHERE id = 'pr-merged'`,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			path := migratedDatabase(t)
+
+			s, err := sqlite.New(config.SQLiteConfig{Path: path})
+			require.NoError(t, err)
+			require.NoError(t, s.RestoreSnapshot(ctx, integritySnapshot()))
+			require.NoError(t, s.Close())
+
+			db, err := sql.Open("sqlite", sqlite.DSN(path))
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = db.Close() })
+
+			_, err = db.ExecContext(ctx, tc.query)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestIntegrityMigrationReportsViolations(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "pr_manager.db")
+
+	m, err := migrate.New(migrationsPath, "sqlite://"+path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _, _ = m.Close() })
+
+	// the last schema without the review triggers
+	require.NoError(t, m.Migrate(3))
+
+	db, err := sql.Open("sqlite", sqlite.DSN(path))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	_, err = db.Exec(`
+		INSERT INTO users (id, name) VALUES ('u1', 'Alice');
+		INSERT INTO pull_requests (id, name, author_id, status, created_at)
+		VALUES ('pr1', 'Legacy', 'u1', 'OPEN', '2025-01-01');
+		INSERT INTO reviewers (pull_request_id, user_id, assigned_at) VALUES ('pr1', 'u1', '2025-01-01');
+	`)
+	require.NoError(t, err)
+
+	err = m.Up()
+	require.ErrorContains(t, err, "pull requests reviewed by their author: pr1 (u1)")
+}
@@ -0,0 +1,565 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/lib/tenant"
+	"github.com/Deymos01/pr-review-manager/internal/repository"
+)
+
+// queryBumpVersion marks a pull request as changed whenever its reviewers are modified.
+const queryBumpVersion = `UPDATE pull_requests SET version = version + 1 WHERE org = ? AND id = ?`
+
+// CreatePullRequest stores an open pull request and assigns reviewers from the author's team.
+func (s *Storage) CreatePullRequest(ctx context.Context, prID, prName, authorID string, requireLead bool) ([]string, error) {
+	var reviewers []string
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		reviewers, err = createPullRequest(ctx, tx, prID, prName, authorID, requireLead)
+		return err
+	})
+
+	return reviewers, err
+}
+
+func createPullRequest(
+	ctx context.Context,
+	tx *sql.Tx,
+	prID, prName, authorID string,
+	requireLead bool,
+) ([]string, error) {
+	const op = "repository.sqlite.CreatePullRequest"
+
+	var err error
+	org := tenant.Org(ctx)
+
+	var exists bool
+	err = tx.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM pull_requests WHERE org = ? AND id = ?)`, org, prID).
+		Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if exists {
+		return nil, repository.ErrPRAlreadyExists
+	}
+
+	members, leads, err := reviewerCandidates(ctx, tx, authorID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(members) == 0 {
+		return nil, fmt.Errorf("%s: no active teammates found for author %s", op, authorID)
+	}
+
+	reviewers, err := repository.SelectReviewers(members, leads, requireLead)
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt := now()
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO pull_requests (org, id, name, author_id, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, org, prID, prName, authorID, domains.PRStatusOpen, createdAt)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = assignReviewers(ctx, tx, prID, reviewers, createdAt); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return reviewers, nil
+}
+
+func assignReviewers(ctx context.Context, tx *sql.Tx, prID string, reviewers []string, assignedAt time.Time) error {
+	for _, reviewerID := range reviewers {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO reviewers (org, pull_request_id, user_id, assigned_at)
+			VALUES (?, ?, ?, ?)
+		`, tenant.Org(ctx), prID, reviewerID, assignedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reviewerCandidates returns the author's active teammates that may review their pull
+// request; leads holds the subset with the lead role.
+func reviewerCandidates(ctx context.Context, tx *sql.Tx, authorID string) (members, leads []string, err error) {
+	// Observers are never assigned as reviewers
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, role
+		FROM users
+		WHERE org = ?2
+		  AND is_active = TRUE
+		  AND role <> 'observer'
+		  AND team_name = (
+				SELECT team_name
+				FROM users
+				WHERE org = ?2 AND id = ?1
+			)
+		  AND id <> ?1
+		ORDER BY id
+	`, authorID, tenant.Org(ctx))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var (
+			memberID string
+			role     domains.Role
+		)
+		if err := rows.Scan(&memberID, &role); err != nil {
+			return nil, nil, err
+		}
+		if role == domains.RoleLead {
+			leads = append(leads, memberID)
+		}
+		members = append(members, memberID)
+	}
+
+	return members, leads, rows.Err()
+}
+
+// replacementReviewer returns a random active teammate of userID that neither authors nor
+// already reviews the pull request. It fails with ErrNoCandidate when there is none.
+func replacementReviewer(ctx context.Context, tx *sql.Tx, prID, userID string) (string, error) {
+	querySelect := `
+		SELECT u.id
+		FROM users u
+		WHERE u.org = ?3 AND
+		      u.team_name = (SELECT team_name FROM users WHERE org = ?3 AND id = ?1) AND
+		      u.id <> ?1 AND u.is_active AND u.role <> 'observer' AND
+		      u.id NOT IN (SELECT author_id FROM pull_requests pr WHERE pr.org = ?3 AND pr.id = ?2) AND
+		      u.id NOT IN (SELECT user_id
+		                   FROM reviewers
+		                   WHERE org = ?3 AND pull_request_id = ?2)
+		ORDER BY RANDOM()
+		LIMIT 1
+	`
+
+	var newUserID string
+	err := tx.QueryRowContext(ctx, querySelect, userID, prID, tenant.Org(ctx)).Scan(&newUserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", repository.ErrNoCandidate
+	}
+
+	return newUserID, err
+}
+
+func (s *Storage) PullRequestExists(ctx context.Context, prID string) (bool, error) {
+	const op = "repository.sqlite.PullRequestExists"
+
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM pull_requests WHERE org = ? AND id = ?)`
+	err := s.conn(ctx).QueryRowContext(ctx, query, tenant.Org(ctx), prID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return exists, nil
+}
+
+func (s *Storage) PullRequestMerged(ctx context.Context, prID string) (bool, error) {
+	const op = "repository.sqlite.PullRequestMerged"
+
+	var isMerged bool
+	query := `SELECT status = ? FROM pull_requests WHERE org = ? AND id = ?`
+	err := s.conn(ctx).QueryRowContext(ctx, query, domains.PRStatusMerged, tenant.Org(ctx), prID).Scan(&isMerged)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, fmt.Errorf("%s: %w", op, repository.ErrPRNotFound)
+		}
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return isMerged, nil
+}
+
+// MergePullRequest marks the pull request as merged. A non-zero ifVersion makes the merge
+// conditional on the stored version, as requested by an If-Match header.
+// Merging a merged pull request changes nothing.
+func (s *Storage) MergePullRequest(ctx context.Context, prID string, ifVersion int64) error {
+	const op = "repository.sqlite.MergePullRequest"
+
+	query := `UPDATE pull_requests
+				SET status = ?4,
+				 	merged_at = ?3,
+				 	version = version + 1
+				WHERE org = ?5 AND id = ?1 AND status <> ?4 AND (?2 = 0 OR version = ?2)`
+	res, err := s.conn(ctx).ExecContext(ctx, query, prID, ifVersion, now(), domains.PRStatusMerged, tenant.Org(ctx))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n == 0 {
+		merged, err := s.PullRequestMerged(ctx, prID)
+		if err != nil {
+			if errors.Is(err, repository.ErrPRNotFound) {
+				return repository.ErrPRNotFound
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if merged {
+			// merging again keeps the original merged_at and version
+			return nil
+		}
+		return repository.ErrVersionConflict
+	}
+
+	return nil
+}
+
+// GetPullRequestByID loads the pull request with its author and reviewers.
+func (s *Storage) GetPullRequestByID(ctx context.Context, prID string) (*domains.PullRequest, error) {
+	const op = "repository.sqlite.GetPullRequestByID"
+
+	var (
+		pr     domains.PullRequest
+		author domains.User
+		labels string
+	)
+	err := s.conn(ctx).QueryRowContext(ctx, `
+		SELECT pr.id, pr.name, pr.description, pr.labels, pr.version,
+			pr.author_id, a.name, a.team_name, a.is_active, a.role, pr.status,
+			pr.need_more_reviewers, pr.created_at, pr.merged_at
+		FROM pull_requests pr
+		JOIN users a ON a.org = pr.org AND a.id = pr.author_id
+		WHERE pr.org = ? AND pr.id = ?
+	`, tenant.Org(ctx), prID).Scan(&pr.ID, &pr.Name, &pr.Description, &labels, &pr.Version,
+		&author.ID, &author.Name, &author.TeamName, &author.IsActive, &author.Role, &pr.Status,
+		&pr.NeedMoreReviewers, &pr.CreatedAt, &pr.MergedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrPRNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if pr.Labels, err = decodeLabels(labels); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	pr.Author = &author
+
+	if err := loadReviewers(ctx, s.conn(ctx), []*domains.PullRequest{&pr}); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &pr, nil
+}
+
+// ListPullRequests returns a page of pull requests ordered by creation time.
+func (s *Storage) ListPullRequests(ctx context.Context, filter domains.PullRequestFilter) (*domains.PullRequestPage, error) {
+	const op = "repository.sqlite.ListPullRequests"
+
+	direction, cmp := keysetPage(filter.Desc)
+
+	var b whereBuilder
+	b.add("pr.org = " + b.arg(tenant.Org(ctx)))
+	if filter.Status != "" {
+		b.add("pr.status = " + b.arg(filter.Status))
+	}
+	if filter.TeamName != "" {
+		b.add("a.team_name = " + b.arg(filter.TeamName))
+	}
+	if filter.AuthorID != "" {
+		b.add("pr.author_id = " + b.arg(filter.AuthorID))
+	}
+	if filter.ReviewerID != "" {
+		b.add("EXISTS (SELECT 1 FROM reviewers r WHERE r.org = pr.org AND r.pull_request_id = pr.id AND r.user_id = " +
+			b.arg(filter.ReviewerID) + ")")
+	}
+	if filter.CreatedFrom != nil {
+		b.add("pr.created_at >= " + b.arg(filter.CreatedFrom.UTC()))
+	}
+	if filter.CreatedTo != nil {
+		b.add("pr.created_at < " + b.arg(filter.CreatedTo.UTC()))
+	}
+	if filter.MergedFrom != nil {
+		b.add("pr.merged_at >= " + b.arg(filter.MergedFrom.UTC()))
+	}
+	if filter.MergedTo != nil {
+		b.add("pr.merged_at < " + b.arg(filter.MergedTo.UTC()))
+	}
+	if filter.NeedMoreReviewers != nil {
+		b.add("pr.need_more_reviewers = " + b.arg(*filter.NeedMoreReviewers))
+	}
+	if filter.After != nil {
+		createdAt, err := time.Parse(time.RFC3339Nano, filter.After.Key)
+		if err != nil {
+			return nil, repository.ErrInvalidCursor
+		}
+		b.add(fmt.Sprintf("(pr.created_at, pr.id) %s (%s, %s)", cmp, b.arg(createdAt.UTC()), b.arg(filter.After.ID)))
+	}
+
+	query := `
+		SELECT pr.id, pr.name, pr.description, pr.labels, pr.version, pr.author_id, a.name, a.team_name, pr.status,
+			pr.need_more_reviewers, pr.created_at, pr.merged_at
+		FROM pull_requests pr
+		JOIN users a ON a.org = pr.org AND a.id = pr.author_id` + b.where() +
+		fmt.Sprintf(" ORDER BY pr.created_at %s, pr.id %s LIMIT %s", direction, direction, b.arg(filter.Limit+1))
+
+	rows, err := s.conn(ctx).QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	page := &domains.PullRequestPage{PullRequests: make([]*domains.PullRequest, 0, filter.Limit)}
+	for rows.Next() {
+		var (
+			pr     domains.PullRequest
+			labels string
+		)
+		pr.Author = &domains.User{}
+		err := rows.Scan(&pr.ID, &pr.Name, &pr.Description, &labels, &pr.Version,
+			&pr.Author.ID, &pr.Author.Name, &pr.Author.TeamName, &pr.Status,
+			&pr.NeedMoreReviewers, &pr.CreatedAt, &pr.MergedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if pr.Labels, err = decodeLabels(labels); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		page.PullRequests = append(page.PullRequests, &pr)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	_ = rows.Close()
+
+	if len(page.PullRequests) > filter.Limit {
+		page.PullRequests = page.PullRequests[:filter.Limit]
+		last := page.PullRequests[len(page.PullRequests)-1]
+		page.Next = &domains.Cursor{Key: last.CreatedAt.Format(time.RFC3339Nano), ID: last.ID}
+	}
+
+	if err := loadReviewers(ctx, s.conn(ctx), page.PullRequests); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return page, nil
+}
+
+// loadReviewers fills Reviewers of the given pull requests in assignment order.
+func loadReviewers(ctx context.Context, q querier, prs []*domains.PullRequest) error {
+	if len(prs) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*domains.PullRequest, len(prs))
+	ids := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		pr.Reviewers = []*domains.Reviewer{}
+		byID[pr.ID] = pr
+		ids = append(ids, pr.ID)
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT r.pull_request_id, u.id, u.name, u.team_name, u.is_active, u.role, r.assigned_at
+		FROM reviewers r
+		JOIN users u ON u.org = r.org AND u.id = r.user_id
+		WHERE r.org = ? AND r.pull_request_id IN (SELECT value FROM json_each(?))
+		ORDER BY r.assigned_at, u.id
+	`, tenant.Org(ctx), jsonArray(ids))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var (
+			prID     string
+			reviewer domains.Reviewer
+		)
+		reviewer.User = &domains.User{}
+		u := reviewer.User
+		if err := rows.Scan(&prID, &u.ID, &u.Name, &u.TeamName, &u.IsActive, &u.Role, &reviewer.AssignedAt); err != nil {
+			return err
+		}
+		byID[prID].Reviewers = append(byID[prID].Reviewers, &reviewer)
+	}
+
+	return rows.Err()
+}
+
+// ReassignReviewer replaces oldUserID with a random eligible member of their team.
+// A non-zero ifVersion must match the stored version.
+func (s *Storage) ReassignReviewer(ctx context.Context, prID, oldUserID string, ifVersion int64) (string, error) {
+	var newUserID string
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		newUserID, err = reassignReviewer(ctx, tx, prID, oldUserID, ifVersion)
+		return err
+	})
+
+	return newUserID, err
+}
+
+func reassignReviewer(ctx context.Context, tx *sql.Tx, prID, oldUserID string, ifVersion int64) (string, error) {
+	const op = "repository.sqlite.ReassignReviewer"
+
+	var err error
+	org := tenant.Org(ctx)
+
+	var (
+		status  domains.PRStatus
+		version int64
+	)
+	err = tx.QueryRowContext(ctx, `SELECT status, version FROM pull_requests WHERE org = ? AND id = ?`, org, prID).
+		Scan(&status, &version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", repository.ErrPRNotFound
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if ifVersion != 0 && version != ifVersion {
+		return "", repository.ErrVersionConflict
+	}
+	if status == domains.PRStatusMerged {
+		return "", repository.ErrPRMerged
+	}
+
+	newUserID, err := replacementReviewer(ctx, tx, prID, oldUserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNoCandidate) {
+			return "", err
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE reviewers
+		SET user_id = ?,
+		    assigned_at = ?
+		WHERE org = ? AND pull_request_id = ? AND user_id = ?
+	`, newUserID, now(), org, prID, oldUserID)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err = tx.ExecContext(ctx, queryBumpVersion, org, prID); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return newUserID, nil
+}
+
+// UpdatePullRequest applies upd to an open pull request if upd.Version matches the stored
+// version. When the PR moves to an author who reviews it, their review goes to a teammate of
+// the new author; the other reviewers are kept.
+func (s *Storage) UpdatePullRequest(ctx context.Context, prID string, upd domains.PullRequestUpdate) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		return updatePullRequest(ctx, tx, prID, upd)
+	})
+}
+
+func updatePullRequest(ctx context.Context, tx *sql.Tx, prID string, upd domains.PullRequestUpdate) error {
+	const op = "repository.sqlite.UpdatePullRequest"
+
+	var err error
+	org := tenant.Org(ctx)
+
+	var (
+		authorID string
+		status   domains.PRStatus
+		version  int64
+	)
+	err = tx.QueryRowContext(ctx, `SELECT author_id, status, version FROM pull_requests WHERE org = ? AND id = ?`, org, prID).
+		Scan(&authorID, &status, &version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.ErrPRNotFound
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if version != upd.Version {
+		return repository.ErrVersionConflict
+	}
+	if status == domains.PRStatusMerged {
+		return repository.ErrPRMerged
+	}
+
+	// The new author must never review their own PR. Only their review is dropped; the
+	// other reviewers keep their assignment.
+	authorChanged := upd.AuthorID != nil && *upd.AuthorID != authorID
+	var slotOpened bool
+	if authorChanged {
+		res, err := tx.ExecContext(ctx, `
+			DELETE FROM reviewers WHERE org = ? AND pull_request_id = ? AND user_id = ?
+		`, org, prID, *upd.AuthorID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		slotOpened = n > 0
+	}
+
+	var labels any
+	if upd.Labels != nil {
+		raw, err := json.Marshal(*upd.Labels)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		labels = string(raw)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE pull_requests
+		SET name = COALESCE(?2, name),
+		    description = COALESCE(?3, description),
+		    labels = COALESCE(?4, labels),
+		    author_id = COALESCE(?5, author_id),
+		    version = version + 1
+		WHERE org = ?6 AND id = ?1
+	`, prID, upd.Name, upd.Description, labels, upd.AuthorID, org)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if slotOpened {
+		// the PR already has its new author, so the replacement comes from their team
+		reviewerID, err := replacementReviewer(ctx, tx, prID, *upd.AuthorID)
+		switch {
+		case errors.Is(err, repository.ErrNoCandidate):
+			// nobody can take the review over, so the PR waits for another reviewer
+			_, err = tx.ExecContext(ctx, `
+				UPDATE pull_requests SET need_more_reviewers = TRUE WHERE org = ? AND id = ?
+			`, org, prID)
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		case err != nil:
+			return fmt.Errorf("%s: %w", op, err)
+		default:
+			if err = assignReviewers(ctx, tx, prID, []string{reviewerID}, now()); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+	}
+
+	return nil
+}
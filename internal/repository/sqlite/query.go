@@ -0,0 +1,81 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// querier is satisfied by both *sql.DB and *sql.Tx.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// whereBuilder collects optional filter conditions together with their positional arguments.
+type whereBuilder struct {
+	conds []string
+	args  []any
+}
+
+// arg registers v as the next positional argument and returns its placeholder.
+func (b *whereBuilder) arg(v any) string {
+	b.args = append(b.args, v)
+	return "?"
+}
+
+func (b *whereBuilder) add(cond string) {
+	b.conds = append(b.conds, cond)
+}
+
+func (b *whereBuilder) where() string {
+	if len(b.conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(b.conds, " AND ")
+}
+
+// keysetPage returns the ORDER BY clause and the comparison operator used to continue
+// a listing after a cursor in the requested direction.
+func keysetPage(desc bool) (direction, cmp string) {
+	if desc {
+		return "DESC", "<"
+	}
+	return "ASC", ">"
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// likePrefix turns a user supplied prefix into a case-insensitive LIKE pattern matched
+// against lower(column); the query must declare ESCAPE '\'.
+func likePrefix(prefix string) string {
+	return likeEscaper.Replace(strings.ToLower(prefix)) + "%"
+}
+
+// jsonArray encodes values for `IN (SELECT value FROM json_each(?))`, the SQLite
+// counterpart of `= ANY($1)`.
+func jsonArray[T ~string](values []T) string {
+	if values == nil {
+		values = []T{}
+	}
+	b, _ := json.Marshal(values)
+	return string(b)
+}
+
+// decodeLabels parses the JSON encoded labels column.
+func decodeLabels(raw string) ([]string, error) {
+	labels := []string{}
+	if err := json.Unmarshal([]byte(raw), &labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// now is the timestamp stored by writes. Times are kept in UTC so that their text form
+// sorts chronologically.
+func now() time.Time {
+	return time.Now().UTC()
+}
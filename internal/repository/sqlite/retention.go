@@ -0,0 +1,145 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/lib/tenant"
+)
+
+// PruneMergedPullRequests removes up to limit pull requests merged before mergedBefore from
+// the live tables, oldest first, and reports how many pull requests and reviews it removed.
+// Their authors and reviewers keep the counts in review_stats; in archive mode the rows are
+// also copied into the archive tables.
+func (s *Storage) PruneMergedPullRequests(
+	ctx context.Context,
+	mergedBefore time.Time,
+	mode domains.RetentionMode,
+	limit int,
+) (int, int, error) {
+	const op = "repository.sqlite.PruneMergedPullRequests"
+
+	org := tenant.Org(ctx)
+
+	var prs, reviews int
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, `
+			SELECT id FROM pull_requests
+			WHERE org = ? AND status = ? AND merged_at < ?
+			ORDER BY merged_at, id
+			LIMIT ?
+		`, org, domains.PRStatusMerged, mergedBefore.UTC(), limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		var ids []string
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		rows.Close()
+
+		prs = len(ids)
+		if prs == 0 {
+			return nil
+		}
+
+		pending := jsonArray(ids)
+
+		err = tx.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM reviewers
+			WHERE org = ?2 AND pull_request_id IN (SELECT value FROM json_each(?1))
+		`, pending, org).Scan(&reviews)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO review_stats (org, user_id, authored)
+			SELECT org, author_id, COUNT(*) FROM pull_requests
+			WHERE org = ?2 AND id IN (SELECT value FROM json_each(?1))
+			GROUP BY author_id
+			ON CONFLICT (org, user_id) DO UPDATE SET authored = review_stats.authored + excluded.authored
+		`, pending, org)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO review_stats (org, user_id, reviewed)
+			SELECT org, user_id, COUNT(*) FROM reviewers
+			WHERE org = ?2 AND pull_request_id IN (SELECT value FROM json_each(?1))
+			GROUP BY user_id
+			ON CONFLICT (org, user_id) DO UPDATE SET reviewed = review_stats.reviewed + excluded.reviewed
+		`, pending, org)
+		if err != nil {
+			return err
+		}
+
+		if mode == domains.RetentionArchive {
+			archivedAt := now()
+
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO pull_requests_archive
+					(org, id, name, description, labels, author_id, created_at, merged_at, archived_at)
+				SELECT org, id, name, description, labels, author_id, created_at, merged_at, ?2
+				FROM pull_requests
+				WHERE org = ?3 AND id IN (SELECT value FROM json_each(?1))
+			`, pending, archivedAt, org)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO reviewers_archive (org, user_id, pull_request_id, archived_at, assigned_at)
+				SELECT org, user_id, pull_request_id, ?2, assigned_at
+				FROM reviewers
+				WHERE org = ?3 AND pull_request_id IN (SELECT value FROM json_each(?1))
+			`, pending, archivedAt, org)
+			if err != nil {
+				return err
+			}
+		}
+
+		// reviewers go with the pull requests: the merged-PR trigger only guards direct changes
+		_, err = tx.ExecContext(ctx, `
+			DELETE FROM pull_requests WHERE org = ?2 AND id IN (SELECT value FROM json_each(?1))
+		`, pending, org)
+		return err
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return prs, reviews, nil
+}
+
+// GetReviewStats counts the pull requests the user authored and reviewed, adding those
+// already removed by retention.
+func (s *Storage) GetReviewStats(ctx context.Context, userID string) (*domains.ReviewStats, error) {
+	const op = "repository.sqlite.GetReviewStats"
+
+	stats := domains.ReviewStats{UserID: userID}
+	err := s.conn(ctx).QueryRowContext(ctx, `
+		SELECT (SELECT COUNT(*) FROM pull_requests WHERE org = ?2 AND author_id = ?1)
+		           + COALESCE((SELECT authored FROM review_stats WHERE org = ?2 AND user_id = ?1), 0),
+		       (SELECT COUNT(*) FROM reviewers WHERE org = ?2 AND user_id = ?1)
+		           + COALESCE((SELECT reviewed FROM review_stats WHERE org = ?2 AND user_id = ?1), 0)
+	`, userID, tenant.Org(ctx)).Scan(&stats.Authored, &stats.Reviewed)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &stats, nil
+}
@@ -0,0 +1,375 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/lib/tenant"
+	"github.com/Deymos01/pr-review-manager/internal/repository"
+)
+
+// ExportSnapshot reads the whole state of the organization, including the archive and the
+// review stats, within one transaction so the snapshot is consistent.
+func (s *Storage) ExportSnapshot(ctx context.Context) (*domains.Snapshot, error) {
+	const op = "repository.sqlite.ExportSnapshot"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	org := tenant.Org(ctx)
+	snap := &domains.Snapshot{
+		CreatedAt: now(),
+		Statuses:  domains.PRStatuses,
+	}
+
+	snap.Teams, err = queryStrings(ctx, tx, `SELECT name FROM teams WHERE org = ? ORDER BY name`, org)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, name, team_name, is_active, role, deleted_at
+		FROM users
+		WHERE org = ?
+		ORDER BY id
+	`, org)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for rows.Next() {
+		var user domains.User
+		if err = rows.Scan(&user.ID, &user.Name, &user.TeamName, &user.IsActive, &user.Role, &user.DeletedAt); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		snap.Users = append(snap.Users, &user)
+	}
+	if err = rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	_ = rows.Close()
+
+	rows, err = tx.QueryContext(ctx, `
+		SELECT id, name, description, labels, author_id, status,
+			need_more_reviewers, created_at, merged_at
+		FROM pull_requests
+		WHERE org = ?
+		ORDER BY id
+	`, org)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	byID := make(map[string]*domains.PullRequest)
+	for rows.Next() {
+		var (
+			pr     domains.PullRequest
+			labels string
+		)
+		pr.Author = &domains.User{}
+		err = rows.Scan(&pr.ID, &pr.Name, &pr.Description, &labels, &pr.Author.ID, &pr.Status, &pr.NeedMoreReviewers, &pr.CreatedAt, &pr.MergedAt)
+		if err == nil {
+			pr.Labels, err = decodeLabels(labels)
+		}
+		if err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		snap.PullRequests = append(snap.PullRequests, &pr)
+		byID[pr.ID] = &pr
+	}
+	if err = rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	_ = rows.Close()
+
+	rows, err = tx.QueryContext(ctx, `
+		SELECT pull_request_id, user_id, assigned_at
+		FROM reviewers
+		WHERE org = ?
+		ORDER BY pull_request_id, assigned_at, user_id
+	`, org)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for rows.Next() {
+		var (
+			prID     string
+			reviewer domains.Reviewer
+		)
+		reviewer.User = &domains.User{}
+		if err = rows.Scan(&prID, &reviewer.User.ID, &reviewer.AssignedAt); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if pr, ok := byID[prID]; ok {
+			pr.Reviewers = append(pr.Reviewers, &reviewer)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	_ = rows.Close()
+
+	if snap.ArchivedPullRequests, err = exportArchive(ctx, tx); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if snap.ReviewStats, err = exportReviewStats(ctx, tx); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return snap, nil
+}
+
+// archiveKey identifies an archived pull request: the same id may be archived more than once.
+type archiveKey struct {
+	id         string
+	archivedAt int64
+}
+
+func exportArchive(ctx context.Context, tx *sql.Tx) ([]*domains.ArchivedPullRequest, error) {
+	org := tenant.Org(ctx)
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, name, description, labels, author_id, created_at, merged_at, archived_at
+		FROM pull_requests_archive
+		WHERE org = ?
+		ORDER BY archived_at, id
+	`, org)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var archive []*domains.ArchivedPullRequest
+	byKey := make(map[archiveKey]*domains.PullRequest)
+	for rows.Next() {
+		var (
+			archived domains.ArchivedPullRequest
+			pr       = &domains.PullRequest{Author: &domains.User{}, Status: domains.PRStatusMerged}
+			labels   string
+		)
+		err = rows.Scan(&pr.ID, &pr.Name, &pr.Description, &labels, &pr.Author.ID, &pr.CreatedAt, &pr.MergedAt, &archived.ArchivedAt)
+		if err != nil {
+			return nil, err
+		}
+		if pr.Labels, err = decodeLabels(labels); err != nil {
+			return nil, err
+		}
+		archived.PullRequest = pr
+		archive = append(archive, &archived)
+		byKey[archiveKey{id: pr.ID, archivedAt: archived.ArchivedAt.UnixNano()}] = pr
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	_ = rows.Close()
+
+	rows, err = tx.QueryContext(ctx, `
+		SELECT pull_request_id, archived_at, user_id, assigned_at
+		FROM reviewers_archive
+		WHERE org = ?
+		ORDER BY pull_request_id, archived_at, assigned_at, user_id
+	`, org)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var (
+			prID       string
+			archivedAt time.Time
+			reviewer   = domains.Reviewer{User: &domains.User{}}
+		)
+		if err = rows.Scan(&prID, &archivedAt, &reviewer.User.ID, &reviewer.AssignedAt); err != nil {
+			return nil, err
+		}
+		if pr, ok := byKey[archiveKey{id: prID, archivedAt: archivedAt.UnixNano()}]; ok {
+			pr.Reviewers = append(pr.Reviewers, &reviewer)
+		}
+	}
+
+	return archive, rows.Err()
+}
+
+func exportReviewStats(ctx context.Context, tx *sql.Tx) ([]*domains.ReviewStats, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT user_id, authored, reviewed FROM review_stats WHERE org = ? ORDER BY user_id
+	`, tenant.Org(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []*domains.ReviewStats
+	for rows.Next() {
+		var stats domains.ReviewStats
+		if err := rows.Scan(&stats.UserID, &stats.Authored, &stats.Reviewed); err != nil {
+			return nil, err
+		}
+		out = append(out, &stats)
+	}
+
+	return out, rows.Err()
+}
+
+// RestoreSnapshot loads the snapshot, including the archive and the review stats, into an
+// empty organization in one transaction. It returns repository.ErrStorageNotEmpty if the
+// organization already has any team, user or pull request.
+func (s *Storage) RestoreSnapshot(ctx context.Context, snap *domains.Snapshot) error {
+	const op = "repository.sqlite.RestoreSnapshot"
+
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		return restoreSnapshot(ctx, tx, snap)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func restoreSnapshot(ctx context.Context, tx *sql.Tx, snap *domains.Snapshot) error {
+	var err error
+	org := tenant.Org(ctx)
+
+	var notEmpty bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM teams WHERE org = ?1)
+		    OR EXISTS(SELECT 1 FROM users WHERE org = ?1)
+		    OR EXISTS(SELECT 1 FROM pull_requests WHERE org = ?1)
+		    OR EXISTS(SELECT 1 FROM pull_requests_archive WHERE org = ?1)
+	`, org).Scan(&notEmpty)
+	if err != nil {
+		return err
+	}
+	if notEmpty {
+		return repository.ErrStorageNotEmpty
+	}
+
+	for _, team := range snap.Teams {
+		if _, err = tx.ExecContext(ctx, `INSERT INTO teams (org, name) VALUES (?, ?)`, org, team); err != nil {
+			return err
+		}
+	}
+
+	for _, user := range snap.Users {
+		var deletedAt any
+		if user.DeletedAt != nil {
+			deletedAt = user.DeletedAt.UTC()
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO users (org, id, name, team_name, is_active, role, deleted_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, org, user.ID, user.Name, user.TeamName, user.IsActive, user.Role, deletedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Reviewers of a merged PR are frozen, so every PR is inserted as OPEN
+	// and gets its final status once the reviewers are in place.
+	for _, pr := range snap.PullRequests {
+		labels, err := json.Marshal(labelsOrEmpty(pr.Labels))
+		if err != nil {
+			return err
+		}
+
+		var mergedAt any
+		if pr.MergedAt != nil {
+			mergedAt = pr.MergedAt.UTC()
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO pull_requests (org, id, name, description, labels, author_id, status,
+			                           need_more_reviewers, created_at, merged_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, org, pr.ID, pr.Name, pr.Description, string(labels), pr.Author.ID, domains.PRStatusOpen,
+			pr.NeedMoreReviewers, pr.CreatedAt.UTC(), mergedAt)
+		if err != nil {
+			return err
+		}
+
+		for _, reviewer := range pr.Reviewers {
+			assignedAt := reviewer.AssignedAt
+			if assignedAt.IsZero() {
+				assignedAt = time.Now()
+			}
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO reviewers (org, pull_request_id, user_id, assigned_at)
+				VALUES (?, ?, ?, ?)
+			`, org, pr.ID, reviewer.User.ID, assignedAt.UTC())
+			if err != nil {
+				return err
+			}
+		}
+
+		if pr.Status != domains.PRStatusOpen {
+			_, err = tx.ExecContext(ctx, `UPDATE pull_requests SET status = ? WHERE org = ? AND id = ?`, pr.Status, org, pr.ID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, archived := range snap.ArchivedPullRequests {
+		pr := archived.PullRequest
+		labels, err := json.Marshal(labelsOrEmpty(pr.Labels))
+		if err != nil {
+			return err
+		}
+
+		archivedAt := archived.ArchivedAt.UTC()
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO pull_requests_archive
+				(org, id, name, description, labels, author_id, created_at, merged_at, archived_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, org, pr.ID, pr.Name, pr.Description, string(labels), pr.Author.ID, pr.CreatedAt.UTC(),
+			pr.MergedAt.UTC(), archivedAt)
+		if err != nil {
+			return err
+		}
+
+		for _, reviewer := range pr.Reviewers {
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO reviewers_archive (org, user_id, pull_request_id, archived_at, assigned_at)
+				VALUES (?, ?, ?, ?, ?)
+			`, org, reviewer.User.ID, pr.ID, archivedAt, reviewer.AssignedAt.UTC())
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, stats := range snap.ReviewStats {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO review_stats (org, user_id, authored, reviewed) VALUES (?, ?, ?, ?)
+		`, org, stats.UserID, stats.Authored, stats.Reviewed)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// labelsOrEmpty keeps the NOT NULL labels column satisfied for snapshots without labels.
+func labelsOrEmpty(labels []string) []string {
+	if labels == nil {
+		return []string{}
+	}
+	return labels
+}
@@ -0,0 +1,54 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+
+	"github.com/Deymos01/pr-review-manager/internal/config"
+
+	_ "modernc.org/sqlite"
+)
+
+// Storage keeps the service state in a single SQLite database file. All queries share one
+// connection, so write transactions never conflict and need no retries.
+type Storage struct {
+	db *sql.DB
+}
+
+func New(dbConfig config.SQLiteConfig) (*Storage, error) {
+	const op = "storage.sqlite.New"
+
+	db, err := sql.Open("sqlite", DSN(dbConfig.Path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// SQLite allows a single writer; one connection serializes transactions instead of
+	// failing them with SQLITE_BUSY
+	db.SetMaxOpenConns(1)
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Storage{db: db}, nil
+}
+
+// DSN builds the connection string for the database file at path with foreign keys enforced.
+func DSN(path string) string {
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Set("_txlock", "immediate")
+	// times are written as "2006-01-02 15:04:05.999999999-07:00", which sorts like the instants do
+	params.Set("_time_format", "sqlite")
+
+	return "file:" + path + "?" + params.Encode()
+}
+
+// Close releases the database file.
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
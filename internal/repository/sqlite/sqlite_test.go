@@ -0,0 +1,49 @@
+package sqlite_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/Deymos01/pr-review-manager/internal/config"
+	"github.com/Deymos01/pr-review-manager/internal/repository/conformance"
+	"github.com/Deymos01/pr-review-manager/internal/repository/sqlite"
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/stretchr/testify/require"
+)
+
+const migrationsPath = "file://../../../migrations/sqlite"
+
+func newStorage(t *testing.T) conformance.Storage {
+	t.Helper()
+
+	s, err := sqlite.New(config.SQLiteConfig{Path: migratedDatabase(t)})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+
+	return s
+}
+
+// migratedDatabase creates a database file with every migration applied and returns its path.
+func migratedDatabase(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "pr_manager.db")
+
+	m, err := migrate.New(migrationsPath, "sqlite://"+path)
+	require.NoError(t, err)
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		require.NoError(t, err)
+	}
+	srcErr, dbErr := m.Close()
+	require.NoError(t, srcErr)
+	require.NoError(t, dbErr)
+
+	return path
+}
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, newStorage)
+}
@@ -0,0 +1,421 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/lib/tenant"
+	"github.com/Deymos01/pr-review-manager/internal/repository"
+)
+
+// queryBumpTeamVersion marks a team as changed whenever its members are modified.
+const queryBumpTeamVersion = `UPDATE teams SET version = version + 1 WHERE org = ? AND name = ?`
+
+func (s *Storage) CreateTeam(ctx context.Context, team *domains.Team) error {
+	const op = "storage.sqlite.CreateTeam"
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		query := `INSERT INTO teams (org, name) VALUES (?, ?)`
+		if _, err := tx.ExecContext(ctx, query, tenant.Org(ctx), team.Name); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := upsertTeamMembers(ctx, tx, team); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	})
+}
+
+// upsertTeamMembers creates the team members or moves existing users into the team,
+// overwriting their name, activity and role. An omitted role keeps the current one, so
+// re-posting a team does not demote its leads. Teams losing members get their version bumped.
+// Deleted users cannot be brought back and fail the upsert with repository.ErrUserDeleted.
+func upsertTeamMembers(ctx context.Context, tx *sql.Tx, team *domains.Team) error {
+	org := tenant.Org(ctx)
+	ids := make([]string, 0, len(team.Members))
+	for _, member := range team.Members {
+		ids = append(ids, member.ID)
+	}
+
+	// IDs of offboarded users stay retired
+	var deleted bool
+	err := tx.QueryRowContext(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM users
+			WHERE org = ? AND id IN (SELECT value FROM json_each(?)) AND deleted_at IS NOT NULL
+		)
+	`, org, jsonArray(ids)).Scan(&deleted)
+	if err != nil {
+		return err
+	}
+	if deleted {
+		return repository.ErrUserDeleted
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE teams SET version = version + 1
+		WHERE org = ?1 AND name IN (
+			SELECT team_name FROM users
+			WHERE org = ?1 AND id IN (SELECT value FROM json_each(?2)) AND team_name <> ?3
+		)
+	`, org, jsonArray(ids), team.Name)
+	if err != nil {
+		return err
+	}
+
+	// an omitted role keeps the current one, new users default to member
+	query := `INSERT INTO users (org, id, name, is_active, team_name, role)
+				VALUES (?1, ?2, ?3, ?4, ?5, COALESCE(NULLIF(?6, ''), 'member'))
+				ON CONFLICT (org, id) DO UPDATE SET name = excluded.name, is_active = excluded.is_active,
+				    team_name = excluded.team_name, role = COALESCE(NULLIF(?6, ''), users.role)`
+
+	for _, member := range team.Members {
+		_, err := tx.ExecContext(ctx, query, org, member.ID, member.Name, member.IsActive, team.Name, string(member.Role))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Storage) TeamExists(ctx context.Context, name string) (bool, error) {
+	const op = "storage.sqlite.TeamExists"
+
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM teams WHERE org = ? AND name = ?)`
+	err := s.conn(ctx).QueryRowContext(ctx, query, tenant.Org(ctx), name).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return exists, nil
+}
+
+func (s *Storage) UserIsTeamLead(ctx context.Context, userID, teamName string) (bool, error) {
+	const op = "storage.sqlite.UserIsTeamLead"
+
+	query := `SELECT EXISTS(
+				SELECT 1 FROM users
+				WHERE org = ? AND id = ? AND team_name = ? AND role = 'lead' AND is_active = TRUE
+			)`
+
+	var isLead bool
+	err := s.conn(ctx).QueryRowContext(ctx, query, tenant.Org(ctx), userID, teamName).Scan(&isLead)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return isLead, nil
+}
+
+func (s *Storage) GetTeamByName(ctx context.Context, name string) (*domains.Team, error) {
+	const op = "storage.sqlite.GetTeamByName"
+
+	var team domains.Team
+	err := s.conn(ctx).QueryRowContext(ctx, `SELECT version FROM teams WHERE org = ? AND name = ?`, tenant.Org(ctx), name).
+		Scan(&team.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrTeamNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	members, err := teamMembers(ctx, s.conn(ctx), name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	team.Name = name
+	team.Members = members
+
+	return &team, nil
+}
+
+// teamMembers returns the members of the team ordered by id.
+func teamMembers(ctx context.Context, q querier, teamName string) ([]*domains.User, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT id, name, is_active, role FROM users
+		WHERE org = ? AND team_name = ?
+		ORDER BY id
+	`, tenant.Org(ctx), teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var users []*domains.User
+	for rows.Next() {
+		var user domains.User
+		if err := rows.Scan(&user.ID, &user.Name, &user.IsActive, &user.Role); err != nil {
+			return nil, err
+		}
+		user.TeamName = &teamName
+		users = append(users, &user)
+	}
+
+	return users, rows.Err()
+}
+
+// DeactivateTeamMembers deactivates the given members and moves their open reviews to the
+// remaining active members. A non-zero ifVersion must match the stored team version.
+func (s *Storage) DeactivateTeamMembers(
+	ctx context.Context,
+	teamName string,
+	userIDs []string,
+	ifVersion int64,
+) (*domains.Team, []*domains.ReassignedPR, error) {
+	var (
+		team       *domains.Team
+		reassigned []*domains.ReassignedPR
+	)
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		team, reassigned, err = deactivateTeamMembers(ctx, tx, teamName, userIDs, ifVersion)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return team, reassigned, nil
+}
+
+func deactivateTeamMembers(
+	ctx context.Context,
+	tx *sql.Tx,
+	teamName string,
+	userIDs []string,
+	ifVersion int64,
+) (*domains.Team, []*domains.ReassignedPR, error) {
+	const op = "storage.sqlite.DeactivateTeamMembers"
+
+	var err error
+	org := tenant.Org(ctx)
+
+	var version int64
+	err = tx.QueryRowContext(ctx, `SELECT version FROM teams WHERE org = ? AND name = ?`, org, teamName).Scan(&version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, repository.ErrTeamNotFound
+		}
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if ifVersion != 0 && version != ifVersion {
+		return nil, nil, repository.ErrVersionConflict
+	}
+
+	ids := jsonArray(userIDs)
+
+	// Ensure all users belong to the team
+	found, err := queryStrings(ctx, tx, `
+		SELECT id FROM users
+		WHERE org = ? AND team_name = ? AND id IN (SELECT value FROM json_each(?))
+	`, org, teamName, ids)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(found) != len(userIDs) {
+		return nil, nil, repository.ErrTeamCompatibility
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE users
+		SET is_active = FALSE
+		WHERE org = ? AND id IN (SELECT value FROM json_each(?))
+	`, org, ids)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Available candidates for reassignment (observers are never assigned)
+	activeMembers, err := queryStrings(ctx, tx, `
+		SELECT id FROM users
+		WHERE org = ? AND team_name = ? AND is_active = TRUE AND role <> 'observer'
+		ORDER BY id
+	`, org, teamName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Find PRs where deactivated users are reviewers; merged PRs keep their historical reviewers
+	rows, err := tx.QueryContext(ctx, `
+		SELECT rev.user_id, rev.pull_request_id, pr.author_id FROM reviewers rev
+		JOIN pull_requests pr ON rev.org = pr.org AND rev.pull_request_id = pr.id
+		WHERE rev.org = ? AND rev.user_id IN (SELECT value FROM json_each(?))
+		  AND pr.status IN (SELECT value FROM json_each(?))
+		ORDER BY rev.pull_request_id, rev.user_id
+	`, org, ids, jsonArray(repository.ReassignableStatuses))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	type userPR struct {
+		userID   string
+		prID     string
+		authorID string
+	}
+	var affected []userPR
+
+	for rows.Next() {
+		var p userPR
+		if err = rows.Scan(&p.userID, &p.prID, &p.authorID); err != nil {
+			_ = rows.Close()
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+		affected = append(affected, p)
+	}
+	if err = rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	_ = rows.Close()
+
+	var reassigned []*domains.ReassignedPR
+
+	for _, a := range affected {
+		if _, err = tx.ExecContext(ctx, queryBumpVersion, org, a.prID); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		// other reviewers in this PR
+		others, err := queryStrings(ctx, tx, `
+			SELECT user_id FROM reviewers
+			WHERE org = ? AND pull_request_id = ? AND user_id <> ?
+		`, org, a.prID, a.userID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+		otherReviewers := make(map[string]struct{}, len(others))
+		for _, id := range others {
+			otherReviewers[id] = struct{}{}
+		}
+
+		// New reviewer should not be the PR author or an existing reviewer
+		candidates := make([]string, 0, len(activeMembers))
+		for _, member := range activeMembers {
+			if _, ok := otherReviewers[member]; member != a.authorID && !ok {
+				candidates = append(candidates, member)
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			DELETE FROM reviewers
+			WHERE org = ? AND user_id = ? AND pull_request_id = ?
+		`, org, a.userID, a.prID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if len(candidates) == 0 {
+			// no suitable candidates, just remove reviewer
+			continue
+		}
+
+		newReviewer := candidates[rand.Intn(len(candidates))]
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO reviewers (org, user_id, pull_request_id, assigned_at)
+			VALUES (?, ?, ?, ?)
+		`, org, newReviewer, a.prID, now())
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		reassigned = append(reassigned, &domains.ReassignedPR{
+			PrID:      a.prID,
+			OldUserID: a.userID,
+			NewUserID: newReviewer,
+		})
+	}
+
+	if _, err = tx.ExecContext(ctx, queryBumpTeamVersion, org, teamName); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	members, err := teamMembers(ctx, tx, teamName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &domains.Team{Name: teamName, Members: members, Version: version + 1}, reassigned, nil
+}
+
+func (s *Storage) ListTeams(ctx context.Context, filter domains.TeamFilter) (*domains.TeamPage, error) {
+	const op = "repository.sqlite.team.ListTeams"
+
+	direction, cmp := keysetPage(filter.Desc)
+
+	var b whereBuilder
+	b.add("t.org = " + b.arg(tenant.Org(ctx)))
+	if filter.NamePrefix != "" {
+		b.add(`lower(t.name) LIKE ` + b.arg(likePrefix(filter.NamePrefix)) + ` ESCAPE '\'`)
+	}
+	if filter.After != nil {
+		b.add(fmt.Sprintf("t.name %s %s", cmp, b.arg(filter.After.Key)))
+	}
+
+	having := ""
+	if filter.HasActive != nil {
+		having = " HAVING (COUNT(u.id) FILTER (WHERE u.is_active) > 0) = " + b.arg(*filter.HasActive)
+	}
+
+	query := `
+		SELECT t.name, COUNT(u.id), COUNT(u.id) FILTER (WHERE u.is_active)
+		FROM teams t
+		LEFT JOIN users u ON u.org = t.org AND u.team_name = t.name` + b.where() + `
+		GROUP BY t.name` + having +
+		fmt.Sprintf(" ORDER BY t.name %s LIMIT %s", direction, b.arg(filter.Limit+1))
+
+	rows, err := s.conn(ctx).QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	page := &domains.TeamPage{Teams: make([]*domains.TeamSummary, 0, filter.Limit)}
+	for rows.Next() {
+		var t domains.TeamSummary
+		if err := rows.Scan(&t.Name, &t.MembersCount, &t.ActiveMembers); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		page.Teams = append(page.Teams, &t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(page.Teams) > filter.Limit {
+		page.Teams = page.Teams[:filter.Limit]
+		last := page.Teams[len(page.Teams)-1].Name
+		page.Next = &domains.Cursor{Key: last, ID: last}
+	}
+
+	return page, nil
+}
+
+func queryStrings(ctx context.Context, q querier, query string, args ...any) ([]string, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+
+	return out, rows.Err()
+}
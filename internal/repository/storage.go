@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+)
+
+// Storage is the method set every backend implements: the repositories of all usecases,
+// the idempotency key store and the transaction manager. Decorators such as the cache
+// wrap a Storage without depending on the packages that consume it.
+type Storage interface {
+	TxManager
+
+	CreateTeam(ctx context.Context, team *domains.Team) error
+	TeamExists(ctx context.Context, name string) (bool, error)
+	GetTeamByName(ctx context.Context, name string) (*domains.Team, error)
+	UserIsTeamLead(ctx context.Context, userID, teamName string) (bool, error)
+	DeactivateTeamMembers(
+		ctx context.Context,
+		teamName string,
+		userIDs []string,
+		ifVersion int64,
+	) (*domains.Team, []*domains.ReassignedPR, error)
+	ListTeams(ctx context.Context, filter domains.TeamFilter) (*domains.TeamPage, error)
+
+	UserExists(ctx context.Context, userID string) (bool, error)
+	UserAssigned(ctx context.Context, prID, userID string) (bool, error)
+	UserHasActiveTeam(ctx context.Context, authorID string) (bool, error)
+	GetUserByID(ctx context.Context, userID string) (*domains.User, error)
+	SetUserStatus(ctx context.Context, userID string, isActive bool) (*domains.User, error)
+	UsersReview(ctx context.Context, userID string, filter domains.ReviewFilter) (*domains.ReviewPage, error)
+	RebalanceReviews(ctx context.Context, userID string) ([]*domains.ReassignedPR, error)
+	SearchUsers(ctx context.Context, filter domains.UserFilter) (*domains.UserPage, error)
+	SoftDeleteUser(ctx context.Context, userID string) (*domains.User, error)
+	GetReviewStats(ctx context.Context, userID string) (*domains.ReviewStats, error)
+
+	CreatePullRequest(ctx context.Context, prID, prName, authorID string, requireLead bool) ([]string, error)
+	PullRequestExists(ctx context.Context, prID string) (bool, error)
+	PullRequestMerged(ctx context.Context, prID string) (bool, error)
+	MergePullRequest(ctx context.Context, prID string, ifVersion int64) error
+	GetPullRequestByID(ctx context.Context, prID string) (*domains.PullRequest, error)
+	ReassignReviewer(ctx context.Context, prID, oldUserID string, ifVersion int64) (string, error)
+	ListPullRequests(ctx context.Context, filter domains.PullRequestFilter) (*domains.PullRequestPage, error)
+	UpdatePullRequest(ctx context.Context, prID string, upd domains.PullRequestUpdate) error
+
+	ImportTeams(ctx context.Context, teams []*domains.Team, dryRun bool) (*domains.ImportReport, error)
+	ExportSnapshot(ctx context.Context) (*domains.Snapshot, error)
+	RestoreSnapshot(ctx context.Context, snap *domains.Snapshot) error
+
+	PruneMergedPullRequests(
+		ctx context.Context,
+		mergedBefore time.Time,
+		mode domains.RetentionMode,
+		limit int,
+	) (int, int, error)
+
+	ReserveIdempotencyKey(
+		ctx context.Context,
+		scope, key, requestHash string,
+		lease time.Duration,
+	) (*domains.IdempotencyRecord, error)
+	CompleteIdempotencyKey(
+		ctx context.Context,
+		scope, key string,
+		statusCode int,
+		header map[string]string,
+		body []byte,
+		ttl time.Duration,
+	) error
+	ReleaseIdempotencyKey(ctx context.Context, scope, key string) error
+	PurgeIdempotencyKeys(ctx context.Context) (int64, error)
+}
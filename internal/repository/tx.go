@@ -0,0 +1,12 @@
+package repository
+
+import "context"
+
+// TxManager runs a unit of work atomically: repository calls made with the context passed
+// to fn take part in one transaction, committed when fn returns nil. fn may be rerun from
+// scratch when the transaction has to be retried, so it must not leak state between runs.
+//
+//go:generate go run github.com/vektra/mockery/v2@v2.53.5 --name=TxManager
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
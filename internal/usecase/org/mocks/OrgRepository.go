@@ -0,0 +1,107 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domains "github.com/Deymos01/pr-review-manager/internal/domains"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OrgRepository is an autogenerated mock type for the OrgRepository type
+type OrgRepository struct {
+	mock.Mock
+}
+
+// ExportSnapshot provides a mock function with given fields: ctx
+func (_m *OrgRepository) ExportSnapshot(ctx context.Context) (*domains.Snapshot, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportSnapshot")
+	}
+
+	var r0 *domains.Snapshot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*domains.Snapshot, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *domains.Snapshot); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.Snapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImportTeams provides a mock function with given fields: ctx, teams, dryRun
+func (_m *OrgRepository) ImportTeams(ctx context.Context, teams []*domains.Team, dryRun bool) (*domains.ImportReport, error) {
+	ret := _m.Called(ctx, teams, dryRun)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportTeams")
+	}
+
+	var r0 *domains.ImportReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*domains.Team, bool) (*domains.ImportReport, error)); ok {
+		return rf(ctx, teams, dryRun)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []*domains.Team, bool) *domains.ImportReport); ok {
+		r0 = rf(ctx, teams, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.ImportReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []*domains.Team, bool) error); ok {
+		r1 = rf(ctx, teams, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreSnapshot provides a mock function with given fields: ctx, snap
+func (_m *OrgRepository) RestoreSnapshot(ctx context.Context, snap *domains.Snapshot) error {
+	ret := _m.Called(ctx, snap)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreSnapshot")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domains.Snapshot) error); ok {
+		r0 = rf(ctx, snap)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewOrgRepository creates a new instance of OrgRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOrgRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OrgRepository {
+	mock := &OrgRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,202 @@
+package org
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/repository"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.53.5 --name=OrgRepository
+type OrgRepository interface {
+	ImportTeams(ctx context.Context, teams []*domains.Team, dryRun bool) (*domains.ImportReport, error)
+	ExportSnapshot(ctx context.Context) (*domains.Snapshot, error)
+	RestoreSnapshot(ctx context.Context, snap *domains.Snapshot) error
+}
+
+type Service struct {
+	log  *slog.Logger
+	repo OrgRepository
+}
+
+func New(log *slog.Logger, repo OrgRepository) *Service {
+	return &Service{repo: repo, log: log}
+}
+
+// Import validates the whole org chart before touching storage and applies it atomically.
+func (s *Service) Import(ctx context.Context, teams []*domains.Team, dryRun bool) (*domains.ImportReport, error) {
+	const op = "usecase.org.Import"
+
+	if problems := validateTeams(teams); len(problems) > 0 {
+		s.log.Warn("invalid org chart", slog.Int("problems", len(problems)))
+		return nil, fmt.Errorf("%w: %s", usecase.ErrInvalidImport, strings.Join(problems, "; "))
+	}
+
+	report, err := s.repo.ImportTeams(ctx, teams, dryRun)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserDeleted) {
+			s.log.Warn("org chart lists a deleted user")
+			return nil, usecase.ErrUserDeleted
+		}
+		s.log.Error("failed to import teams", slog.String("op", op), slog.String("err", err.Error()))
+		return nil, err
+	}
+
+	s.log.Info("org chart imported",
+		slog.Bool("dry_run", dryRun),
+		slog.Int("teams_created", len(report.CreatedTeams)),
+		slog.Int("users_created", len(report.Created)),
+		slog.Int("users_updated", len(report.Updated)),
+		slog.Int("users_moved", len(report.Moved)))
+	return report, nil
+}
+
+func (s *Service) Export(ctx context.Context) (*domains.Snapshot, error) {
+	const op = "usecase.org.Export"
+
+	snap, err := s.repo.ExportSnapshot(ctx)
+	if err != nil {
+		s.log.Error("failed to export snapshot", slog.String("op", op), slog.String("err", err.Error()))
+		return nil, err
+	}
+
+	s.log.Info("snapshot exported",
+		slog.Int("teams", len(snap.Teams)),
+		slog.Int("users", len(snap.Users)),
+		slog.Int("pull_requests", len(snap.PullRequests)))
+	return snap, nil
+}
+
+// Restore checks that the snapshot is self-consistent and loads it into an empty storage.
+func (s *Service) Restore(ctx context.Context, snap *domains.Snapshot) error {
+	const op = "usecase.org.Restore"
+
+	if problems := validateSnapshot(snap); len(problems) > 0 {
+		s.log.Warn("invalid snapshot", slog.Int("problems", len(problems)))
+		return fmt.Errorf("%w: %s", usecase.ErrInvalidSnapshot, strings.Join(problems, "; "))
+	}
+
+	if err := s.repo.RestoreSnapshot(ctx, snap); err != nil {
+		if errors.Is(err, repository.ErrStorageNotEmpty) {
+			s.log.Warn("refusing to restore into a non-empty storage")
+			return usecase.ErrStorageNotEmpty
+		}
+		s.log.Error("failed to restore snapshot", slog.String("op", op), slog.String("err", err.Error()))
+		return err
+	}
+
+	s.log.Info("snapshot restored",
+		slog.Int("teams", len(snap.Teams)),
+		slog.Int("users", len(snap.Users)),
+		slog.Int("pull_requests", len(snap.PullRequests)))
+	return nil
+}
+
+func validateSnapshot(snap *domains.Snapshot) []string {
+	var problems []string
+
+	for _, st := range snap.Statuses {
+		if !st.Valid() {
+			problems = append(problems, fmt.Sprintf("status %q: unknown status", st))
+		}
+	}
+
+	teams := make(map[string]struct{}, len(snap.Teams))
+	for _, team := range snap.Teams {
+		if _, ok := teams[team]; ok {
+			problems = append(problems, fmt.Sprintf("team %q: listed more than once", team))
+		}
+		teams[team] = struct{}{}
+	}
+
+	users := make(map[string]struct{}, len(snap.Users))
+	for _, user := range snap.Users {
+		if _, ok := users[user.ID]; ok {
+			problems = append(problems, fmt.Sprintf("user %q: listed more than once", user.ID))
+		}
+		users[user.ID] = struct{}{}
+
+		if user.TeamName != nil {
+			if _, ok := teams[*user.TeamName]; !ok {
+				problems = append(problems, fmt.Sprintf("user %q: unknown team %q", user.ID, *user.TeamName))
+			}
+		}
+		if !user.Role.Valid() {
+			problems = append(problems, fmt.Sprintf("user %q: unknown role %q", user.ID, user.Role))
+		}
+	}
+
+	prs := make(map[string]struct{}, len(snap.PullRequests))
+	for _, pr := range snap.PullRequests {
+		if _, ok := prs[pr.ID]; ok {
+			problems = append(problems, fmt.Sprintf("pull request %q: listed more than once", pr.ID))
+		}
+		prs[pr.ID] = struct{}{}
+
+		if _, ok := users[pr.Author.ID]; !ok {
+			problems = append(problems, fmt.Sprintf("pull request %q: unknown author %q", pr.ID, pr.Author.ID))
+		}
+		if !pr.Status.Valid() {
+			problems = append(problems, fmt.Sprintf("pull request %q: unknown status %q", pr.ID, pr.Status))
+		}
+
+		reviewers := make(map[string]struct{}, len(pr.Reviewers))
+		for _, r := range pr.Reviewers {
+			if _, ok := users[r.User.ID]; !ok {
+				problems = append(problems, fmt.Sprintf("pull request %q: unknown reviewer %q", pr.ID, r.User.ID))
+			}
+			if r.User.ID == pr.Author.ID {
+				problems = append(problems, fmt.Sprintf("pull request %q: author %q is also a reviewer", pr.ID, r.User.ID))
+			}
+			if _, ok := reviewers[r.User.ID]; ok {
+				problems = append(problems, fmt.Sprintf("pull request %q: reviewer %q listed more than once", pr.ID, r.User.ID))
+			}
+			reviewers[r.User.ID] = struct{}{}
+		}
+	}
+
+	return problems
+}
+
+func validateTeams(teams []*domains.Team) []string {
+	var problems []string
+
+	if len(teams) == 0 {
+		return []string{"no teams to import"}
+	}
+
+	seenTeams := make(map[string]struct{}, len(teams))
+	seenUsers := make(map[string]string)
+	for i, team := range teams {
+		if team.Name == "" {
+			problems = append(problems, fmt.Sprintf("team #%d: team_name is empty", i+1))
+		} else if _, ok := seenTeams[team.Name]; ok {
+			problems = append(problems, fmt.Sprintf("team %q: listed more than once", team.Name))
+		}
+		seenTeams[team.Name] = struct{}{}
+
+		for _, member := range team.Members {
+			if member.ID == "" {
+				problems = append(problems, fmt.Sprintf("team %q: member with empty user_id", team.Name))
+				continue
+			}
+			if member.Name == "" {
+				problems = append(problems, fmt.Sprintf("user %q: username is empty", member.ID))
+			}
+			if member.Role != "" && !member.Role.Valid() {
+				problems = append(problems, fmt.Sprintf("user %q: unknown role %q", member.ID, member.Role))
+			}
+			if other, ok := seenUsers[member.ID]; ok {
+				problems = append(problems, fmt.Sprintf("user %q: listed in teams %q and %q", member.ID, other, team.Name))
+			}
+			seenUsers[member.ID] = team.Name
+		}
+	}
+
+	return problems
+}
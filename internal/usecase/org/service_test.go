@@ -0,0 +1,321 @@
+package org
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/repository"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
+	"github.com/Deymos01/pr-review-manager/internal/usecase/org/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestService_Import(t *testing.T) {
+	validTeams := []*domains.Team{
+		{
+			Name: "backend",
+			Members: []*domains.User{
+				{ID: "u1", Name: "Alice", IsActive: true, Role: domains.RoleLead},
+				{ID: "u2", Name: "Bob", IsActive: true},
+			},
+		},
+		{
+			Name: "frontend",
+			Members: []*domains.User{
+				{ID: "u3", Name: "Carol", IsActive: false, Role: domains.RoleObserver},
+			},
+		},
+	}
+
+	type testCase struct {
+		name   string
+		teams  []*domains.Team
+		dryRun bool
+
+		callRepo   bool
+		mockReport *domains.ImportReport
+		mockErr    error
+
+		expectedErr     error
+		expectedProblem string
+	}
+
+	cases := []testCase{
+		{
+			name:       "Success",
+			teams:      validTeams,
+			callRepo:   true,
+			mockReport: &domains.ImportReport{CreatedTeams: []string{"backend", "frontend"}},
+		},
+		{
+			name:       "Dry run",
+			teams:      validTeams,
+			dryRun:     true,
+			callRepo:   true,
+			mockReport: &domains.ImportReport{DryRun: true},
+		},
+		{
+			name:        "ImportTeams returns error",
+			teams:       validTeams,
+			callRepo:    true,
+			mockErr:     errors.New("import error"),
+			expectedErr: errors.New("import error"),
+		},
+		{
+			name:        "Deleted user",
+			teams:       validTeams,
+			callRepo:    true,
+			mockErr:     repository.ErrUserDeleted,
+			expectedErr: usecase.ErrUserDeleted,
+		},
+		{
+			name:            "No teams",
+			expectedErr:     usecase.ErrInvalidImport,
+			expectedProblem: "no teams to import",
+		},
+		{
+			name: "Duplicate team",
+			teams: []*domains.Team{
+				{Name: "backend", Members: []*domains.User{{ID: "u1", Name: "Alice"}}},
+				{Name: "backend", Members: []*domains.User{{ID: "u2", Name: "Bob"}}},
+			},
+			expectedErr:     usecase.ErrInvalidImport,
+			expectedProblem: `team "backend": listed more than once`,
+		},
+		{
+			name: "User in two teams",
+			teams: []*domains.Team{
+				{Name: "backend", Members: []*domains.User{{ID: "u1", Name: "Alice"}}},
+				{Name: "frontend", Members: []*domains.User{{ID: "u1", Name: "Alice"}}},
+			},
+			expectedErr:     usecase.ErrInvalidImport,
+			expectedProblem: `user "u1": listed in teams "backend" and "frontend"`,
+		},
+		{
+			name: "Invalid member",
+			teams: []*domains.Team{
+				{Name: "", Members: []*domains.User{
+					{ID: "", Name: "Nobody"},
+					{ID: "u2", Name: "", Role: "owner"},
+				}},
+			},
+			expectedErr:     usecase.ErrInvalidImport,
+			expectedProblem: `team #1: team_name is empty; team "": member with empty user_id; user "u2": username is empty; user "u2": unknown role "owner"`,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := mocks.NewOrgRepository(t)
+
+			if tc.callRepo {
+				repo.
+					On("ImportTeams", mock.Anything, tc.teams, tc.dryRun).
+					Return(tc.mockReport, tc.mockErr).
+					Once()
+			}
+
+			svc := New(discardLogger(), repo)
+			report, err := svc.Import(context.Background(), tc.teams, tc.dryRun)
+
+			if tc.expectedProblem != "" {
+				require.ErrorIs(t, err, tc.expectedErr)
+				require.Contains(t, err.Error(), tc.expectedProblem)
+				return
+			}
+			if tc.expectedErr != nil {
+				require.Error(t, err)
+				require.Equal(t, tc.expectedErr, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.mockReport, report)
+		})
+	}
+}
+
+func TestService_Export(t *testing.T) {
+	type testCase struct {
+		name string
+
+		mockSnap *domains.Snapshot
+		mockErr  error
+
+		expectedErr error
+	}
+
+	cases := []testCase{
+		{
+			name:     "Success",
+			mockSnap: &domains.Snapshot{Teams: []string{"backend"}},
+		},
+		{
+			name:        "ExportSnapshot returns error",
+			mockErr:     errors.New("export error"),
+			expectedErr: errors.New("export error"),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := mocks.NewOrgRepository(t)
+			repo.On("ExportSnapshot", mock.Anything).Return(tc.mockSnap, tc.mockErr).Once()
+
+			svc := New(discardLogger(), repo)
+			snap, err := svc.Export(context.Background())
+
+			if tc.expectedErr != nil {
+				require.Error(t, err)
+				require.Equal(t, tc.expectedErr, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.mockSnap, snap)
+		})
+	}
+}
+
+func TestService_Restore(t *testing.T) {
+	team := "backend"
+	unknownTeam := "frontend"
+
+	validSnap := &domains.Snapshot{
+		Statuses: domains.PRStatuses,
+		Teams:    []string{team},
+		Users: []*domains.User{
+			{ID: "u1", Name: "Alice", TeamName: &team, IsActive: true, Role: domains.RoleLead},
+			{ID: "u2", Name: "Bob", TeamName: &team, IsActive: true, Role: domains.RoleMember},
+		},
+		PullRequests: []*domains.PullRequest{
+			{
+				ID:        "pr1",
+				Author:    &domains.User{ID: "u1"},
+				Status:    "OPEN",
+				Reviewers: []*domains.Reviewer{{User: &domains.User{ID: "u2"}}},
+			},
+		},
+	}
+
+	type testCase struct {
+		name string
+		snap *domains.Snapshot
+
+		callRepo bool
+		mockErr  error
+
+		expectedErr     error
+		expectedProblem string
+	}
+
+	cases := []testCase{
+		{
+			name:     "Success",
+			snap:     validSnap,
+			callRepo: true,
+		},
+		{
+			name:        "Storage not empty",
+			snap:        validSnap,
+			callRepo:    true,
+			mockErr:     repository.ErrStorageNotEmpty,
+			expectedErr: usecase.ErrStorageNotEmpty,
+		},
+		{
+			name:        "RestoreSnapshot returns error",
+			snap:        validSnap,
+			callRepo:    true,
+			mockErr:     errors.New("restore error"),
+			expectedErr: errors.New("restore error"),
+		},
+		{
+			name: "Dangling references",
+			snap: &domains.Snapshot{
+				Statuses: []domains.PRStatus{domains.PRStatusOpen},
+				Users: []*domains.User{
+					{ID: "u1", Name: "Alice", TeamName: &unknownTeam, Role: domains.RoleMember},
+				},
+				PullRequests: []*domains.PullRequest{
+					{
+						ID:        "pr1",
+						Author:    &domains.User{ID: "u9"},
+						Status:    "DRAFT",
+						Reviewers: []*domains.Reviewer{{User: &domains.User{ID: "u8"}}},
+					},
+				},
+			},
+			expectedErr: usecase.ErrInvalidSnapshot,
+			expectedProblem: `user "u1": unknown team "frontend"; ` +
+				`pull request "pr1": unknown author "u9"; ` +
+				`pull request "pr1": unknown status "DRAFT"; ` +
+				`pull request "pr1": unknown reviewer "u8"`,
+		},
+		{
+			name: "Author reviews own PR",
+			snap: &domains.Snapshot{
+				Statuses: domains.PRStatuses,
+				Users: []*domains.User{
+					{ID: "u1", Name: "Alice", Role: domains.RoleMember},
+				},
+				PullRequests: []*domains.PullRequest{
+					{
+						ID:        "pr1",
+						Author:    &domains.User{ID: "u1"},
+						Status:    domains.PRStatusMerged,
+						Reviewers: []*domains.Reviewer{{User: &domains.User{ID: "u1"}}},
+					},
+				},
+			},
+			expectedErr:     usecase.ErrInvalidSnapshot,
+			expectedProblem: `pull request "pr1": author "u1" is also a reviewer`,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := mocks.NewOrgRepository(t)
+
+			if tc.callRepo {
+				repo.On("RestoreSnapshot", mock.Anything, tc.snap).Return(tc.mockErr).Once()
+			}
+
+			svc := New(discardLogger(), repo)
+			err := svc.Restore(context.Background(), tc.snap)
+
+			if tc.expectedProblem != "" {
+				require.ErrorIs(t, err, tc.expectedErr)
+				require.Contains(t, err.Error(), tc.expectedProblem)
+				return
+			}
+			if tc.expectedErr != nil {
+				require.Error(t, err)
+				require.Equal(t, tc.expectedErr, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
@@ -14,9 +14,9 @@ type PullRequestRepository struct {
 	mock.Mock
 }
 
-// CreatePullRequest provides a mock function with given fields: ctx, prID, prName, authorID
-func (_m *PullRequestRepository) CreatePullRequest(ctx context.Context, prID string, prName string, authorID string) ([]string, error) {
-	ret := _m.Called(ctx, prID, prName, authorID)
+// CreatePullRequest provides a mock function with given fields: ctx, prID, prName, authorID, requireLead
+func (_m *PullRequestRepository) CreatePullRequest(ctx context.Context, prID string, prName string, authorID string, requireLead bool) ([]string, error) {
+	ret := _m.Called(ctx, prID, prName, authorID, requireLead)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreatePullRequest")
@@ -24,19 +24,19 @@ func (_m *PullRequestRepository) CreatePullRequest(ctx context.Context, prID str
 
 	var r0 []string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) ([]string, error)); ok {
-		return rf(ctx, prID, prName, authorID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, bool) ([]string, error)); ok {
+		return rf(ctx, prID, prName, authorID, requireLead)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) []string); ok {
-		r0 = rf(ctx, prID, prName, authorID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, bool) []string); ok {
+		r0 = rf(ctx, prID, prName, authorID, requireLead)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]string)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
-		r1 = rf(ctx, prID, prName, authorID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, bool) error); ok {
+		r1 = rf(ctx, prID, prName, authorID, requireLead)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -74,17 +74,47 @@ func (_m *PullRequestRepository) GetPullRequestByID(ctx context.Context, prID st
 	return r0, r1
 }
 
-// MergePullRequest provides a mock function with given fields: ctx, prID
-func (_m *PullRequestRepository) MergePullRequest(ctx context.Context, prID string) error {
-	ret := _m.Called(ctx, prID)
+// ListPullRequests provides a mock function with given fields: ctx, filter
+func (_m *PullRequestRepository) ListPullRequests(ctx context.Context, filter domains.PullRequestFilter) (*domains.PullRequestPage, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPullRequests")
+	}
+
+	var r0 *domains.PullRequestPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domains.PullRequestFilter) (*domains.PullRequestPage, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domains.PullRequestFilter) *domains.PullRequestPage); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.PullRequestPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domains.PullRequestFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MergePullRequest provides a mock function with given fields: ctx, prID, ifVersion
+func (_m *PullRequestRepository) MergePullRequest(ctx context.Context, prID string, ifVersion int64) error {
+	ret := _m.Called(ctx, prID, ifVersion)
 
 	if len(ret) == 0 {
 		panic("no return value specified for MergePullRequest")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, prID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) error); ok {
+		r0 = rf(ctx, prID, ifVersion)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -148,9 +178,9 @@ func (_m *PullRequestRepository) PullRequestMerged(ctx context.Context, prID str
 	return r0, r1
 }
 
-// ReassignReviewer provides a mock function with given fields: ctx, prID, oldUserID
-func (_m *PullRequestRepository) ReassignReviewer(ctx context.Context, prID string, oldUserID string) (string, error) {
-	ret := _m.Called(ctx, prID, oldUserID)
+// ReassignReviewer provides a mock function with given fields: ctx, prID, oldUserID, ifVersion
+func (_m *PullRequestRepository) ReassignReviewer(ctx context.Context, prID string, oldUserID string, ifVersion int64) (string, error) {
+	ret := _m.Called(ctx, prID, oldUserID, ifVersion)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ReassignReviewer")
@@ -158,17 +188,17 @@ func (_m *PullRequestRepository) ReassignReviewer(ctx context.Context, prID stri
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
-		return rf(ctx, prID, oldUserID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) (string, error)); ok {
+		return rf(ctx, prID, oldUserID, ifVersion)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
-		r0 = rf(ctx, prID, oldUserID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) string); ok {
+		r0 = rf(ctx, prID, oldUserID, ifVersion)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, prID, oldUserID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64) error); ok {
+		r1 = rf(ctx, prID, oldUserID, ifVersion)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -176,6 +206,24 @@ func (_m *PullRequestRepository) ReassignReviewer(ctx context.Context, prID stri
 	return r0, r1
 }
 
+// UpdatePullRequest provides a mock function with given fields: ctx, prID, upd
+func (_m *PullRequestRepository) UpdatePullRequest(ctx context.Context, prID string, upd domains.PullRequestUpdate) error {
+	ret := _m.Called(ctx, prID, upd)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePullRequest")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domains.PullRequestUpdate) error); ok {
+		r0 = rf(ctx, prID, upd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewPullRequestRepository creates a new instance of PullRequestRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewPullRequestRepository(t interface {
@@ -19,87 +19,135 @@ type UserRepository interface {
 
 //go:generate go run github.com/vektra/mockery/v2@v2.53.5 --name=PullRequestRepository
 type PullRequestRepository interface {
-	CreatePullRequest(ctx context.Context, prID, prName, authorID string) ([]string, error)
+	CreatePullRequest(ctx context.Context, prID, prName, authorID string, requireLead bool) ([]string, error)
 	PullRequestExists(ctx context.Context, prID string) (bool, error)
 	PullRequestMerged(ctx context.Context, prID string) (bool, error)
-	MergePullRequest(ctx context.Context, prID string) error
+	MergePullRequest(ctx context.Context, prID string, ifVersion int64) error
 	GetPullRequestByID(ctx context.Context, prID string) (*domains.PullRequest, error)
-	ReassignReviewer(ctx context.Context, prID, oldUserID string) (string, error)
+	ReassignReviewer(ctx context.Context, prID, oldUserID string, ifVersion int64) (string, error)
+	ListPullRequests(ctx context.Context, filter domains.PullRequestFilter) (*domains.PullRequestPage, error)
+	UpdatePullRequest(ctx context.Context, prID string, upd domains.PullRequestUpdate) error
 }
 
 type Service struct {
-	log      *slog.Logger
-	userRepo UserRepository
-	prRepo   PullRequestRepository
+	log       *slog.Logger
+	userRepo  UserRepository
+	prRepo    PullRequestRepository
+	txManager repository.TxManager
 }
 
-func New(log *slog.Logger, userRepo UserRepository, prRepo PullRequestRepository) *Service {
+func New(
+	log *slog.Logger,
+	userRepo UserRepository,
+	prRepo PullRequestRepository,
+	txManager repository.TxManager,
+) *Service {
 	return &Service{
-		log:      log,
-		userRepo: userRepo,
-		prRepo:   prRepo,
+		log:       log,
+		userRepo:  userRepo,
+		prRepo:    prRepo,
+		txManager: txManager,
 	}
 }
 
-func (s *Service) CreatePullRequest(ctx context.Context, prID, prName, authorID string) ([]string, error) {
+func (s *Service) CreatePullRequest(
+	ctx context.Context,
+	prID, prName, authorID string,
+	requireLead bool,
+) (*domains.PullRequest, error) {
 	const op = "usecase.pull_request.CreatePullRequest"
 
-	ok, err := s.userRepo.UserExists(ctx, authorID)
-	if err != nil {
-		s.log.Error("failed to check if author exists", slog.String("op", op), slog.String("err", err.Error()))
-		return nil, err
-	}
-	if !ok {
-		s.log.Warn("author does not exist", slog.String("author_id", authorID))
-		return nil, usecase.ErrUserNotFound
-	}
+	var pr *domains.PullRequest
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		ok, err := s.userRepo.UserExists(ctx, authorID)
+		if err != nil {
+			s.log.Error("failed to check if author exists", slog.String("op", op), slog.String("err", err.Error()))
+			return err
+		}
+		if !ok {
+			s.log.Warn("author does not exist", slog.String("author_id", authorID))
+			return usecase.ErrUserNotFound
+		}
 
-	ok, err = s.userRepo.UserHasActiveTeam(ctx, authorID)
-	if err != nil {
-		s.log.Error("failed to check if author has active team", slog.String("op", op), slog.String("err", err.Error()))
-		return nil, err
-	}
-	if !ok {
-		s.log.Warn("author does not have an active team", slog.String("author_id", authorID))
-		return nil, usecase.ErrTeamNotFound
-	}
+		ok, err = s.userRepo.UserHasActiveTeam(ctx, authorID)
+		if err != nil {
+			s.log.Error("failed to check if author has active team", slog.String("op", op), slog.String("err", err.Error()))
+			return err
+		}
+		if !ok {
+			s.log.Warn("author does not have an active team", slog.String("author_id", authorID))
+			return usecase.ErrTeamNotFound
+		}
 
-	assignedReviewers, err := s.prRepo.CreatePullRequest(ctx, prID, prName, authorID)
-	if err != nil {
-		if errors.Is(err, repository.ErrPRAlreadyExists) {
-			s.log.Warn("pull request already exists", slog.String("pr_id", prID))
-			return nil, usecase.ErrPRAlreadyExists
+		_, err = s.prRepo.CreatePullRequest(ctx, prID, prName, authorID, requireLead)
+		if err != nil {
+			if errors.Is(err, repository.ErrPRAlreadyExists) {
+				s.log.Warn("pull request already exists", slog.String("pr_id", prID))
+				return usecase.ErrPRAlreadyExists
+			}
+			if errors.Is(err, repository.ErrNoCandidate) {
+				s.log.Warn("no team lead available to review", slog.String("pr_id", prID))
+				return usecase.ErrNoAvailableReviewer
+			}
+			s.log.Error("failed to create pull request", slog.String("op", op), slog.String("err", err.Error()))
+			return err
+		}
+
+		pr, err = s.prRepo.GetPullRequestByID(ctx, prID)
+		if err != nil {
+			s.log.Error("failed to get created pull request", slog.String("op", op), slog.String("err", err.Error()))
+			return err
 		}
-		s.log.Error("failed to create pull request", slog.String("op", op), slog.String("err", err.Error()))
+
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	s.log.Info("pull request created and reviewers assigned", slog.String("pr_id", prID))
-	return assignedReviewers, nil
+	return pr, nil
 }
 
-func (s *Service) MergePullRequest(ctx context.Context, prID string) (*domains.PullRequest, error) {
+// MergePullRequest merges the pull request. A non-zero ifVersion must match its current version.
+func (s *Service) MergePullRequest(ctx context.Context, prID string, ifVersion int64) (*domains.PullRequest, error) {
 	const op = "usecase.pull_request.MergePullRequest"
 
-	ok, err := s.prRepo.PullRequestExists(ctx, prID)
-	if err != nil {
-		s.log.Error("failed to check if pull request exists", slog.String("op", op), slog.String("err", err.Error()))
-		return nil, err
-	}
-	if !ok {
-		s.log.Warn("pull request does not exist", slog.String("pr_id", prID))
-		return nil, usecase.ErrPullRequestNotFound
-	}
+	var pr *domains.PullRequest
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		ok, err := s.prRepo.PullRequestExists(ctx, prID)
+		if err != nil {
+			s.log.Error("failed to check if pull request exists", slog.String("op", op), slog.String("err", err.Error()))
+			return err
+		}
+		if !ok {
+			s.log.Warn("pull request does not exist", slog.String("pr_id", prID))
+			return usecase.ErrPullRequestNotFound
+		}
 
-	err = s.prRepo.MergePullRequest(ctx, prID)
-	if err != nil {
-		s.log.Error("failed to merge pull request", slog.String("op", op), slog.String("err", err.Error()))
-		return nil, err
-	}
+		err = s.prRepo.MergePullRequest(ctx, prID, ifVersion)
+		if err != nil {
+			if errors.Is(err, repository.ErrPRNotFound) {
+				s.log.Warn("pull request does not exist", slog.String("pr_id", prID))
+				return usecase.ErrPullRequestNotFound
+			}
+			if errors.Is(err, repository.ErrVersionConflict) {
+				s.log.Warn("pull request version conflict", slog.String("pr_id", prID), slog.Int64("version", ifVersion))
+				return usecase.ErrVersionConflict
+			}
+			s.log.Error("failed to merge pull request", slog.String("op", op), slog.String("err", err.Error()))
+			return err
+		}
 
-	pr, err := s.prRepo.GetPullRequestByID(ctx, prID)
+		pr, err = s.prRepo.GetPullRequestByID(ctx, prID)
+		if err != nil {
+			s.log.Error("failed to get pull request", slog.String("op", op), slog.String("err", err.Error()))
+			return err
+		}
+
+		return nil
+	})
 	if err != nil {
-		s.log.Error("failed to get pull request", slog.String("op", op), slog.String("err", err.Error()))
 		return nil, err
 	}
 
@@ -107,78 +155,205 @@ func (s *Service) MergePullRequest(ctx context.Context, prID string) (*domains.P
 	return pr, nil
 }
 
-func (s *Service) ReassignReviewer(ctx context.Context, prID, oldUserID string) (*domains.PullRequest, string, error) {
+// ReassignReviewer replaces oldUserID on the pull request. A non-zero ifVersion must match its
+// current version.
+func (s *Service) ReassignReviewer(
+	ctx context.Context,
+	prID, oldUserID string,
+	ifVersion int64,
+) (*domains.PullRequest, string, error) {
 	const op = "usecase.pull_request.ReassignReviewer"
 
-	ok, err := s.prRepo.PullRequestExists(ctx, prID)
-	if err != nil {
-		s.log.Error("failed to check if pull request exists", slog.String("op", op), slog.String("err", err.Error()))
-		return nil, "", err
-	}
-	if !ok {
-		s.log.Warn("pull request does not exist", slog.String("pr_id", prID))
-		return nil, "", usecase.ErrPullRequestNotFound
-	}
+	var (
+		pr        *domains.PullRequest
+		newUserID string
+	)
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		ok, err := s.prRepo.PullRequestExists(ctx, prID)
+		if err != nil {
+			s.log.Error("failed to check if pull request exists", slog.String("op", op), slog.String("err", err.Error()))
+			return err
+		}
+		if !ok {
+			s.log.Warn("pull request does not exist", slog.String("pr_id", prID))
+			return usecase.ErrPullRequestNotFound
+		}
 
-	ok, err = s.prRepo.PullRequestMerged(ctx, prID)
-	if err != nil {
-		s.log.Error("failed to check if pull request is merged", slog.String("op", op), slog.String("err", err.Error()))
-		return nil, "", err
-	}
-	if ok {
-		s.log.Warn("pull request is already merged", slog.String("pr_id", prID))
-		return nil, "", usecase.ErrPRAlreadyMerged
-	}
+		ok, err = s.prRepo.PullRequestMerged(ctx, prID)
+		if err != nil {
+			s.log.Error("failed to check if pull request is merged", slog.String("op", op), slog.String("err", err.Error()))
+			return err
+		}
+		if ok {
+			s.log.Warn("pull request is already merged", slog.String("pr_id", prID))
+			return usecase.ErrPRAlreadyMerged
+		}
+
+		ok, err = s.userRepo.UserExists(ctx, oldUserID)
+		if err != nil {
+			s.log.Error("failed to check if user exists", slog.String("op", op), slog.String("err", err.Error()))
+			return err
+		}
+		if !ok {
+			s.log.Warn("user does not exist", slog.String("user_id", oldUserID))
+			return usecase.ErrUserNotFound
+		}
+
+		ok, err = s.userRepo.UserAssigned(ctx, prID, oldUserID)
+		if err != nil {
+			s.log.Error("failed to check if user is assigned to the pull request",
+				slog.String("op", op),
+				slog.String("err", err.Error()))
+			return err
+		}
+		if !ok {
+			s.log.Warn("user is not assigned to the pull request",
+				slog.String("pr_id", prID),
+				slog.String("user_id", oldUserID))
+			return usecase.ErrUserNotAssigned
+		}
+
+		newUserID, err = s.prRepo.ReassignReviewer(ctx, prID, oldUserID, ifVersion)
+		if err != nil {
+			switch {
+			case errors.Is(err, repository.ErrPRNotFound):
+				s.log.Warn("pull request does not exist", slog.String("pr_id", prID))
+				return usecase.ErrPullRequestNotFound
+			case errors.Is(err, repository.ErrPRMerged):
+				s.log.Warn("pull request is already merged", slog.String("pr_id", prID))
+				return usecase.ErrPRAlreadyMerged
+			case errors.Is(err, repository.ErrVersionConflict):
+				s.log.Warn("pull request version conflict", slog.String("pr_id", prID), slog.Int64("version", ifVersion))
+				return usecase.ErrVersionConflict
+			}
+			if errors.Is(err, repository.ErrNoCandidate) {
+				s.log.Warn("no available reviewer to reassign",
+					slog.String("pr_id", prID),
+					slog.String("old_user_id", oldUserID))
+				return usecase.ErrNoAvailableReviewer
+			}
+			s.log.Error("failed to reassign reviewer",
+				slog.String("op", op),
+				slog.String("err", err.Error()))
+			return err
+		}
+
+		pr, err = s.prRepo.GetPullRequestByID(ctx, prID)
+		if err != nil {
+			s.log.Error("failed to get pull request",
+				slog.String("op", op),
+				slog.String("err", err.Error()))
+			return err
+		}
 
-	ok, err = s.userRepo.UserExists(ctx, oldUserID)
+		return nil
+	})
 	if err != nil {
-		s.log.Error("failed to check if user exists", slog.String("op", op), slog.String("err", err.Error()))
 		return nil, "", err
 	}
-	if !ok {
-		s.log.Warn("user does not exist", slog.String("user_id", oldUserID))
-		return nil, "", usecase.ErrUserNotFound
-	}
 
-	ok, err = s.userRepo.UserAssigned(ctx, prID, oldUserID)
+	s.log.Info("reviewer reassigned", slog.String("pr_id", prID),
+		slog.String("old_user_id", oldUserID),
+		slog.String("new_user_id", newUserID))
+
+	return pr, newUserID, nil
+}
+
+func (s *Service) GetPullRequest(ctx context.Context, prID string) (*domains.PullRequest, error) {
+	const op = "usecase.pull_request.GetPullRequest"
+
+	pr, err := s.prRepo.GetPullRequestByID(ctx, prID)
 	if err != nil {
-		s.log.Error("failed to check if user is assigned to the pull request",
-			slog.String("op", op),
-			slog.String("err", err.Error()))
-		return nil, "", err
-	}
-	if !ok {
-		s.log.Warn("user is not assigned to the pull request",
-			slog.String("pr_id", prID),
-			slog.String("user_id", oldUserID))
-		return nil, "", usecase.ErrUserNotAssigned
+		if errors.Is(err, repository.ErrPRNotFound) {
+			s.log.Warn("pull request does not exist", slog.String("pr_id", prID))
+			return nil, usecase.ErrPullRequestNotFound
+		}
+		s.log.Error("failed to get pull request", slog.String("op", op), slog.String("err", err.Error()))
+		return nil, err
 	}
 
-	newUserID, err := s.prRepo.ReassignReviewer(ctx, prID, oldUserID)
+	return pr, nil
+}
+
+func (s *Service) ListPullRequests(ctx context.Context, filter domains.PullRequestFilter) (*domains.PullRequestPage, error) {
+	const op = "usecase.pull_request.ListPullRequests"
+
+	page, err := s.prRepo.ListPullRequests(ctx, filter)
 	if err != nil {
-		if errors.Is(err, repository.ErrNoCandidate) {
-			s.log.Warn("no available reviewer to reassign",
-				slog.String("pr_id", prID),
-				slog.String("old_user_id", oldUserID))
-			return nil, "", usecase.ErrNoAvailableReviewer
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			s.log.Warn("invalid pull request cursor")
+			return nil, usecase.ErrInvalidCursor
 		}
-		s.log.Error("failed to reassign reviewer",
-			slog.String("op", op),
-			slog.String("err", err.Error()))
-		return nil, "", err
+		s.log.Error("failed to list pull requests", slog.String("op", op), slog.String("err", err.Error()))
+		return nil, err
 	}
 
-	pr, err := s.prRepo.GetPullRequestByID(ctx, prID)
+	s.log.Info("pull requests successfully listed", slog.Int("pull_requests_count", len(page.PullRequests)))
+	return page, nil
+}
+
+// UpdatePullRequest edits an open pull request. A new author must exist and belong to an
+// active team, just like on creation.
+func (s *Service) UpdatePullRequest(
+	ctx context.Context,
+	prID string,
+	upd domains.PullRequestUpdate,
+) (*domains.PullRequest, error) {
+	const op = "usecase.pull_request.UpdatePullRequest"
+
+	var pr *domains.PullRequest
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if upd.AuthorID != nil {
+			ok, err := s.userRepo.UserExists(ctx, *upd.AuthorID)
+			if err != nil {
+				s.log.Error("failed to check if author exists", slog.String("op", op), slog.String("err", err.Error()))
+				return err
+			}
+			if !ok {
+				s.log.Warn("new author does not exist", slog.String("author_id", *upd.AuthorID))
+				return usecase.ErrUserNotFound
+			}
+
+			ok, err = s.userRepo.UserHasActiveTeam(ctx, *upd.AuthorID)
+			if err != nil {
+				s.log.Error("failed to check if author has active team", slog.String("op", op), slog.String("err", err.Error()))
+				return err
+			}
+			if !ok {
+				s.log.Warn("new author does not have an active team", slog.String("author_id", *upd.AuthorID))
+				return usecase.ErrTeamNotFound
+			}
+		}
+
+		if err := s.prRepo.UpdatePullRequest(ctx, prID, upd); err != nil {
+			switch {
+			case errors.Is(err, repository.ErrPRNotFound):
+				s.log.Warn("pull request does not exist", slog.String("pr_id", prID))
+				return usecase.ErrPullRequestNotFound
+			case errors.Is(err, repository.ErrPRMerged):
+				s.log.Warn("pull request is already merged", slog.String("pr_id", prID))
+				return usecase.ErrPRAlreadyMerged
+			case errors.Is(err, repository.ErrVersionConflict):
+				s.log.Warn("pull request version conflict", slog.String("pr_id", prID), slog.Int64("version", upd.Version))
+				return usecase.ErrVersionConflict
+			}
+			s.log.Error("failed to update pull request", slog.String("op", op), slog.String("err", err.Error()))
+			return err
+		}
+
+		var err error
+		pr, err = s.prRepo.GetPullRequestByID(ctx, prID)
+		if err != nil {
+			s.log.Error("failed to get pull request", slog.String("op", op), slog.String("err", err.Error()))
+			return err
+		}
+
+		return nil
+	})
 	if err != nil {
-		s.log.Error("failed to get pull request",
-			slog.String("op", op),
-			slog.String("err", err.Error()))
-		return nil, "", err
+		return nil, err
 	}
 
-	s.log.Info("reviewer reassigned", slog.String("pr_id", prID),
-		slog.String("old_user_id", oldUserID),
-		slog.String("new_user_id", newUserID))
-
-	return pr, newUserID, nil
+	s.log.Info("pull request updated", slog.String("pr_id", prID), slog.Int64("version", pr.Version))
+	return pr, nil
 }
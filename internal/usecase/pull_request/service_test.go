@@ -9,6 +9,7 @@ import (
 
 	"github.com/Deymos01/pr-review-manager/internal/domains"
 	"github.com/Deymos01/pr-review-manager/internal/repository"
+	repomocks "github.com/Deymos01/pr-review-manager/internal/repository/mocks"
 	"github.com/Deymos01/pr-review-manager/internal/usecase"
 	"github.com/Deymos01/pr-review-manager/internal/usecase/pull_request"
 	"github.com/Deymos01/pr-review-manager/internal/usecase/pull_request/mocks"
@@ -21,6 +22,17 @@ func discardLogger() *slog.Logger {
 }
 
 func TestCreatePullRequest(t *testing.T) {
+	created := &domains.PullRequest{
+		ID:     "pr1",
+		Name:   "Feature",
+		Author: &domains.User{ID: "authorID"},
+		Status: "OPEN",
+		Reviewers: []*domains.Reviewer{
+			{User: &domains.User{ID: "u1"}},
+			{User: &domains.User{ID: "u2"}},
+		},
+	}
+
 	type testCase struct {
 		name         string
 		authorExists bool
@@ -29,6 +41,7 @@ func TestCreatePullRequest(t *testing.T) {
 		mockErrAuthor error
 		mockErrTeam   error
 		mockErrCreate error
+		mockErrGet    error
 
 		expectedErr error
 	}
@@ -68,6 +81,20 @@ func TestCreatePullRequest(t *testing.T) {
 			mockErrCreate: errors.New("create pull request error"),
 			expectedErr:   errors.New("create pull request error"),
 		},
+		{
+			name:         "GetPullRequestByID returns error",
+			authorExists: true,
+			hasTeam:      true,
+			mockErrGet:   errors.New("get err"),
+			expectedErr:  errors.New("get err"),
+		},
+		{
+			name:          "No lead available",
+			authorExists:  true,
+			hasTeam:       true,
+			mockErrCreate: repository.ErrNoCandidate,
+			expectedErr:   usecase.ErrNoAvailableReviewer,
+		},
 	}
 
 	for _, tc := range cases {
@@ -94,20 +121,24 @@ func TestCreatePullRequest(t *testing.T) {
 			if tc.mockErrTeam == nil && tc.authorExists && tc.hasTeam {
 				if tc.mockErrCreate != nil {
 					prRepo.
-						On("CreatePullRequest", mock.Anything, "pr1", "Feature", "authorID").
+						On("CreatePullRequest", mock.Anything, "pr1", "Feature", "authorID", false).
 						Return(nil, tc.mockErrCreate).
 						Once()
 				} else {
 					prRepo.
-						On("CreatePullRequest", mock.Anything, "pr1", "Feature", "authorID").
+						On("CreatePullRequest", mock.Anything, "pr1", "Feature", "authorID", false).
 						Return([]string{"u1", "u2"}, nil).
 						Once()
+					prRepo.
+						On("GetPullRequestByID", mock.Anything, "pr1").
+						Return(created, tc.mockErrGet).
+						Once()
 				}
 			}
 
-			svc := pull_request.New(discardLogger(), userRepo, prRepo)
+			svc := pull_request.New(discardLogger(), userRepo, prRepo, repomocks.PassThroughTx(t))
 
-			res, err := svc.CreatePullRequest(context.Background(), "pr1", "Feature", "authorID")
+			res, err := svc.CreatePullRequest(context.Background(), "pr1", "Feature", "authorID", false)
 
 			if tc.expectedErr != nil {
 				require.Error(t, err)
@@ -116,7 +147,7 @@ func TestCreatePullRequest(t *testing.T) {
 			}
 
 			require.NoError(t, err)
-			require.Equal(t, []string{"u1", "u2"}, res)
+			require.Equal(t, created, res)
 		})
 	}
 }
@@ -148,6 +179,12 @@ func TestMergePullRequest(t *testing.T) {
 			mockErrExists: errors.New("exists err"),
 			expectedErr:   errors.New("exists err"),
 		},
+		{
+			name:         "Version conflict",
+			exists:       true,
+			mockErrMerge: repository.ErrVersionConflict,
+			expectedErr:  usecase.ErrVersionConflict,
+		},
 		{
 			name:         "MergePullRequest error",
 			exists:       true,
@@ -178,7 +215,7 @@ func TestMergePullRequest(t *testing.T) {
 
 			if tc.mockErrExists == nil && tc.exists {
 				prRepo.
-					On("MergePullRequest", mock.Anything, "pr1").
+					On("MergePullRequest", mock.Anything, "pr1", int64(3)).
 					Return(tc.mockErrMerge).
 					Once()
 			}
@@ -197,8 +234,8 @@ func TestMergePullRequest(t *testing.T) {
 				}
 			}
 
-			svc := pull_request.New(discardLogger(), userRepo, prRepo)
-			pr, err := svc.MergePullRequest(context.Background(), "pr1")
+			svc := pull_request.New(discardLogger(), userRepo, prRepo, repomocks.PassThroughTx(t))
+			pr, err := svc.MergePullRequest(context.Background(), "pr1", 3)
 
 			if tc.expectedErr != nil {
 				require.Error(t, err)
@@ -300,6 +337,24 @@ func TestReassignReviewer(t *testing.T) {
 			mockErrReassign: repository.ErrNoCandidate,
 			expectedErr:     usecase.ErrNoAvailableReviewer,
 		},
+		{
+			name:            "Reassign returns ErrVersionConflict",
+			prExists:        true,
+			prMerged:        false,
+			userExists:      true,
+			userAssigned:    true,
+			mockErrReassign: repository.ErrVersionConflict,
+			expectedErr:     usecase.ErrVersionConflict,
+		},
+		{
+			name:            "Reassign returns ErrPRMerged",
+			prExists:        true,
+			prMerged:        false,
+			userExists:      true,
+			userAssigned:    true,
+			mockErrReassign: repository.ErrPRMerged,
+			expectedErr:     usecase.ErrPRAlreadyMerged,
+		},
 		{
 			name:            "Reassign returns other error",
 			prExists:        true,
@@ -358,12 +413,12 @@ func TestReassignReviewer(t *testing.T) {
 			if tc.mockErrAssigned == nil && tc.userAssigned {
 				if tc.mockErrReassign != nil {
 					prRepo.
-						On("ReassignReviewer", mock.Anything, "pr1", "old").
+						On("ReassignReviewer", mock.Anything, "pr1", "old", int64(3)).
 						Return("", tc.mockErrReassign).
 						Once()
 				} else {
 					prRepo.
-						On("ReassignReviewer", mock.Anything, "pr1", "old").
+						On("ReassignReviewer", mock.Anything, "pr1", "old", int64(3)).
 						Return("newUser", nil).
 						Once()
 				}
@@ -383,8 +438,8 @@ func TestReassignReviewer(t *testing.T) {
 				}
 			}
 
-			svc := pull_request.New(discardLogger(), userRepo, prRepo)
-			pr, newID, err := svc.ReassignReviewer(context.Background(), "pr1", "old")
+			svc := pull_request.New(discardLogger(), userRepo, prRepo, repomocks.PassThroughTx(t))
+			pr, newID, err := svc.ReassignReviewer(context.Background(), "pr1", "old", 3)
 
 			if tc.expectedErr != nil {
 				require.Error(t, err)
@@ -398,3 +453,239 @@ func TestReassignReviewer(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPullRequest(t *testing.T) {
+	type testCase struct {
+		name string
+
+		mockPR  *domains.PullRequest
+		mockErr error
+
+		expectedErr error
+	}
+
+	cases := []testCase{
+		{
+			name:   "Success",
+			mockPR: &domains.PullRequest{ID: "pr1"},
+		},
+		{
+			name:        "PR does not exist",
+			mockErr:     repository.ErrPRNotFound,
+			expectedErr: usecase.ErrPullRequestNotFound,
+		},
+		{
+			name:        "GetPullRequestByID error",
+			mockErr:     errors.New("get err"),
+			expectedErr: errors.New("get err"),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			userRepo := mocks.NewUserRepository(t)
+			prRepo := mocks.NewPullRequestRepository(t)
+
+			prRepo.
+				On("GetPullRequestByID", mock.Anything, "pr1").
+				Return(tc.mockPR, tc.mockErr).
+				Once()
+
+			svc := pull_request.New(discardLogger(), userRepo, prRepo, repomocks.NewTxManager(t))
+			pr, err := svc.GetPullRequest(context.Background(), "pr1")
+
+			if tc.expectedErr != nil {
+				require.Error(t, err)
+				require.Equal(t, tc.expectedErr, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.mockPR, pr)
+		})
+	}
+}
+
+func TestListPullRequests(t *testing.T) {
+	filter := domains.PullRequestFilter{Status: "OPEN", ReviewerID: "u2", Limit: 10}
+
+	type testCase struct {
+		name string
+
+		mockPage *domains.PullRequestPage
+		mockErr  error
+
+		expectedErr error
+	}
+
+	cases := []testCase{
+		{
+			name:     "Success",
+			mockPage: &domains.PullRequestPage{PullRequests: []*domains.PullRequest{{ID: "pr1"}}},
+		},
+		{
+			name:        "Invalid cursor",
+			mockErr:     repository.ErrInvalidCursor,
+			expectedErr: usecase.ErrInvalidCursor,
+		},
+		{
+			name:        "ListPullRequests error",
+			mockErr:     errors.New("list err"),
+			expectedErr: errors.New("list err"),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			userRepo := mocks.NewUserRepository(t)
+			prRepo := mocks.NewPullRequestRepository(t)
+
+			prRepo.
+				On("ListPullRequests", mock.Anything, filter).
+				Return(tc.mockPage, tc.mockErr).
+				Once()
+
+			svc := pull_request.New(discardLogger(), userRepo, prRepo, repomocks.NewTxManager(t))
+			page, err := svc.ListPullRequests(context.Background(), filter)
+
+			if tc.expectedErr != nil {
+				require.Error(t, err)
+				require.Equal(t, tc.expectedErr, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.mockPage, page)
+		})
+	}
+}
+
+func TestUpdatePullRequest(t *testing.T) {
+	newAuthor := "u4"
+	title := "New title"
+
+	type testCase struct {
+		name string
+		upd  domains.PullRequestUpdate
+
+		authorExists  bool
+		hasTeam       bool
+		mockErrUpdate error
+		mockErrGet    error
+
+		expectedErr error
+	}
+
+	cases := []testCase{
+		{
+			name: "Title only",
+			upd:  domains.PullRequestUpdate{Name: &title, Version: 1},
+		},
+		{
+			name:         "Author transfer",
+			upd:          domains.PullRequestUpdate{AuthorID: &newAuthor, Version: 1},
+			authorExists: true,
+			hasTeam:      true,
+		},
+		{
+			name:        "New author does not exist",
+			upd:         domains.PullRequestUpdate{AuthorID: &newAuthor, Version: 1},
+			expectedErr: usecase.ErrUserNotFound,
+		},
+		{
+			name:         "New author has no active team",
+			upd:          domains.PullRequestUpdate{AuthorID: &newAuthor, Version: 1},
+			authorExists: true,
+			expectedErr:  usecase.ErrTeamNotFound,
+		},
+		{
+			name:          "PR does not exist",
+			upd:           domains.PullRequestUpdate{Name: &title, Version: 1},
+			mockErrUpdate: repository.ErrPRNotFound,
+			expectedErr:   usecase.ErrPullRequestNotFound,
+		},
+		{
+			name:          "PR is merged",
+			upd:           domains.PullRequestUpdate{Name: &title, Version: 1},
+			mockErrUpdate: repository.ErrPRMerged,
+			expectedErr:   usecase.ErrPRAlreadyMerged,
+		},
+		{
+			name:          "Stale version",
+			upd:           domains.PullRequestUpdate{Name: &title, Version: 1},
+			mockErrUpdate: repository.ErrVersionConflict,
+			expectedErr:   usecase.ErrVersionConflict,
+		},
+		{
+			name:          "UpdatePullRequest error",
+			upd:           domains.PullRequestUpdate{Name: &title, Version: 1},
+			mockErrUpdate: errors.New("update err"),
+			expectedErr:   errors.New("update err"),
+		},
+		{
+			name:        "GetPullRequestByID error",
+			upd:         domains.PullRequestUpdate{Name: &title, Version: 1},
+			mockErrGet:  errors.New("get err"),
+			expectedErr: errors.New("get err"),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			userRepo := mocks.NewUserRepository(t)
+			prRepo := mocks.NewPullRequestRepository(t)
+
+			transfer := tc.upd.AuthorID != nil
+			if transfer {
+				userRepo.
+					On("UserExists", mock.Anything, newAuthor).
+					Return(tc.authorExists, nil).
+					Once()
+				if tc.authorExists {
+					userRepo.
+						On("UserHasActiveTeam", mock.Anything, newAuthor).
+						Return(tc.hasTeam, nil).
+						Once()
+				}
+			}
+
+			if !transfer || (tc.authorExists && tc.hasTeam) {
+				prRepo.
+					On("UpdatePullRequest", mock.Anything, "pr1", tc.upd).
+					Return(tc.mockErrUpdate).
+					Once()
+			}
+
+			if tc.mockErrUpdate == nil && (!transfer || (tc.authorExists && tc.hasTeam)) {
+				prRepo.
+					On("GetPullRequestByID", mock.Anything, "pr1").
+					Return(&domains.PullRequest{ID: "pr1", Version: 2}, tc.mockErrGet).
+					Once()
+			}
+
+			svc := pull_request.New(discardLogger(), userRepo, prRepo, repomocks.PassThroughTx(t))
+			pr, err := svc.UpdatePullRequest(context.Background(), "pr1", tc.upd)
+
+			if tc.expectedErr != nil {
+				require.Error(t, err)
+				require.Equal(t, tc.expectedErr, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, int64(2), pr.Version)
+		})
+	}
+}
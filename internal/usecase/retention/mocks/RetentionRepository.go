@@ -0,0 +1,66 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domains "github.com/Deymos01/pr-review-manager/internal/domains"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// RetentionRepository is an autogenerated mock type for the RetentionRepository type
+type RetentionRepository struct {
+	mock.Mock
+}
+
+// PruneMergedPullRequests provides a mock function with given fields: ctx, mergedBefore, mode, limit
+func (_m *RetentionRepository) PruneMergedPullRequests(ctx context.Context, mergedBefore time.Time, mode domains.RetentionMode, limit int) (int, int, error) {
+	ret := _m.Called(ctx, mergedBefore, mode, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PruneMergedPullRequests")
+	}
+
+	var r0 int
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, domains.RetentionMode, int) (int, int, error)); ok {
+		return rf(ctx, mergedBefore, mode, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, domains.RetentionMode, int) int); ok {
+		r0 = rf(ctx, mergedBefore, mode, limit)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, domains.RetentionMode, int) int); ok {
+		r1 = rf(ctx, mergedBefore, mode, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, time.Time, domains.RetentionMode, int) error); ok {
+		r2 = rf(ctx, mergedBefore, mode, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// NewRetentionRepository creates a new instance of RetentionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRetentionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RetentionRepository {
+	mock := &RetentionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,109 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/lib/tenant"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.53.5 --name=RetentionRepository
+type RetentionRepository interface {
+	PruneMergedPullRequests(
+		ctx context.Context,
+		mergedBefore time.Time,
+		mode domains.RetentionMode,
+		limit int,
+	) (int, int, error)
+}
+
+type Service struct {
+	log    *slog.Logger
+	repo   RetentionRepository
+	policy domains.RetentionPolicy
+	now    func() time.Time
+}
+
+// New returns a service applying policy unless a run overrides it. A zero MaxAge disables
+// scheduled runs, see Enabled.
+func New(log *slog.Logger, repo RetentionRepository, policy domains.RetentionPolicy) *Service {
+	return &Service{log: log, repo: repo, policy: policy, now: time.Now}
+}
+
+// Enabled reports whether the configured policy removes anything.
+func (s *Service) Enabled() bool {
+	return s.policy.MaxAge > 0
+}
+
+// Run removes the pull requests merged more than MaxAge ago from the live tables, one batch
+// per transaction, until none is left. Zero fields of override fall back to the configured
+// policy. Batches committed before a failure stay removed.
+func (s *Service) Run(ctx context.Context, override domains.RetentionPolicy) (*domains.RetentionReport, error) {
+	const op = "usecase.retention.Run"
+
+	policy := s.policy
+	if override.MaxAge != 0 {
+		policy.MaxAge = override.MaxAge
+	}
+	if override.Mode != "" {
+		policy.Mode = override.Mode
+	}
+	if override.BatchSize != 0 {
+		policy.BatchSize = override.BatchSize
+	}
+
+	switch {
+	case policy.MaxAge <= 0:
+		return nil, fmt.Errorf("%w: max age must be positive", usecase.ErrInvalidRetention)
+	case !policy.Mode.Valid():
+		return nil, fmt.Errorf("%w: mode must be archive or delete", usecase.ErrInvalidRetention)
+	case policy.BatchSize <= 0:
+		return nil, fmt.Errorf("%w: batch size must be positive", usecase.ErrInvalidRetention)
+	}
+
+	startedAt := s.now()
+	report := &domains.RetentionReport{
+		Mode:         policy.Mode,
+		MergedBefore: startedAt.Add(-policy.MaxAge),
+		StartedAt:    startedAt,
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		prs, reviews, err := s.repo.PruneMergedPullRequests(ctx, report.MergedBefore, policy.Mode, policy.BatchSize)
+		if err != nil {
+			s.log.Error("failed to prune merged pull requests",
+				slog.String("op", op),
+				slog.String("org", tenant.Org(ctx)),
+				slog.Int("pruned", report.PullRequests),
+				slog.String("err", err.Error()))
+			return nil, err
+		}
+		if prs > 0 {
+			report.Batches++
+			report.PullRequests += prs
+			report.Reviews += reviews
+		}
+		if prs < policy.BatchSize {
+			break
+		}
+	}
+
+	report.FinishedAt = s.now()
+
+	s.log.Info("retention run finished",
+		slog.String("org", tenant.Org(ctx)),
+		slog.String("mode", string(report.Mode)),
+		slog.Time("merged_before", report.MergedBefore),
+		slog.Int("pull_requests", report.PullRequests),
+		slog.Int("reviews", report.Reviews),
+		slog.Int("batches", report.Batches))
+	return report, nil
+}
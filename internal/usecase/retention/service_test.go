@@ -0,0 +1,149 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
+	"github.com/Deymos01/pr-review-manager/internal/usecase/retention/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+type batch struct {
+	prs     int
+	reviews int
+	err     error
+}
+
+func TestService_Run(t *testing.T) {
+	now := time.Date(2025, 11, 30, 12, 0, 0, 0, time.UTC)
+	configured := domains.RetentionPolicy{
+		MaxAge:    90 * 24 * time.Hour,
+		Mode:      domains.RetentionArchive,
+		BatchSize: 2,
+	}
+
+	type testCase struct {
+		name     string
+		override domains.RetentionPolicy
+		batches  []batch
+
+		expectedMode   domains.RetentionMode
+		expectedCutoff time.Time
+		expectedLimit  int
+		expectedReport *domains.RetentionReport
+		expectedErr    error
+	}
+
+	cases := []testCase{
+		{
+			name:           "Configured policy",
+			batches:        []batch{{prs: 2, reviews: 3}, {prs: 2, reviews: 4}, {prs: 1, reviews: 2}},
+			expectedMode:   domains.RetentionArchive,
+			expectedCutoff: now.Add(-90 * 24 * time.Hour),
+			expectedLimit:  2,
+			expectedReport: &domains.RetentionReport{
+				Mode:         domains.RetentionArchive,
+				MergedBefore: now.Add(-90 * 24 * time.Hour),
+				PullRequests: 5,
+				Reviews:      9,
+				Batches:      3,
+				StartedAt:    now,
+				FinishedAt:   now,
+			},
+		},
+		{
+			name:           "Overridden age and mode",
+			override:       domains.RetentionPolicy{MaxAge: 24 * time.Hour, Mode: domains.RetentionDelete},
+			batches:        []batch{{prs: 2, reviews: 2}, {}},
+			expectedMode:   domains.RetentionDelete,
+			expectedCutoff: now.Add(-24 * time.Hour),
+			expectedLimit:  2,
+			expectedReport: &domains.RetentionReport{
+				Mode:         domains.RetentionDelete,
+				MergedBefore: now.Add(-24 * time.Hour),
+				PullRequests: 2,
+				Reviews:      2,
+				Batches:      1,
+				StartedAt:    now,
+				FinishedAt:   now,
+			},
+		},
+		{
+			name:           "Nothing to prune",
+			batches:        []batch{{}},
+			expectedMode:   domains.RetentionArchive,
+			expectedCutoff: now.Add(-90 * 24 * time.Hour),
+			expectedLimit:  2,
+			expectedReport: &domains.RetentionReport{
+				Mode:         domains.RetentionArchive,
+				MergedBefore: now.Add(-90 * 24 * time.Hour),
+				StartedAt:    now,
+				FinishedAt:   now,
+			},
+		},
+		{
+			name:        "Invalid mode",
+			override:    domains.RetentionPolicy{Mode: "compress"},
+			expectedErr: usecase.ErrInvalidRetention,
+		},
+		{
+			name:        "Negative age",
+			override:    domains.RetentionPolicy{MaxAge: -time.Hour},
+			expectedErr: usecase.ErrInvalidRetention,
+		},
+		{
+			name:           "Repository error",
+			batches:        []batch{{prs: 2, reviews: 2}, {err: errors.New("db error")}},
+			expectedMode:   domains.RetentionArchive,
+			expectedCutoff: now.Add(-90 * 24 * time.Hour),
+			expectedLimit:  2,
+			expectedErr:    errors.New("db error"),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := mocks.NewRetentionRepository(t)
+			for _, b := range tc.batches {
+				repo.On("PruneMergedPullRequests", context.Background(), tc.expectedCutoff, tc.expectedMode, tc.expectedLimit).
+					Return(b.prs, b.reviews, b.err).Once()
+			}
+
+			s := New(discardLogger(), repo, configured)
+			s.now = func() time.Time { return now }
+
+			report, err := s.Run(context.Background(), tc.override)
+			if tc.expectedErr != nil {
+				if errors.Is(tc.expectedErr, usecase.ErrInvalidRetention) {
+					require.ErrorIs(t, err, usecase.ErrInvalidRetention)
+				} else {
+					require.Equal(t, tc.expectedErr, err)
+				}
+				require.Nil(t, report)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedReport, report)
+		})
+	}
+}
+
+func TestService_Enabled(t *testing.T) {
+	require.False(t, New(discardLogger(), nil, domains.RetentionPolicy{}).Enabled())
+	require.True(t, New(discardLogger(), nil, domains.RetentionPolicy{MaxAge: time.Hour}).Enabled())
+}
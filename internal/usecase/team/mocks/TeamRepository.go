@@ -32,9 +32,9 @@ func (_m *TeamRepository) CreateTeam(ctx context.Context, _a1 *domains.Team) err
 	return r0
 }
 
-// DeactivateTeamMembers provides a mock function with given fields: ctx, teamName, userIDs
-func (_m *TeamRepository) DeactivateTeamMembers(ctx context.Context, teamName string, userIDs []string) (*domains.Team, []*domains.ReassignedPR, error) {
-	ret := _m.Called(ctx, teamName, userIDs)
+// DeactivateTeamMembers provides a mock function with given fields: ctx, teamName, userIDs, ifVersion
+func (_m *TeamRepository) DeactivateTeamMembers(ctx context.Context, teamName string, userIDs []string, ifVersion int64) (*domains.Team, []*domains.ReassignedPR, error) {
+	ret := _m.Called(ctx, teamName, userIDs, ifVersion)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeactivateTeamMembers")
@@ -43,27 +43,27 @@ func (_m *TeamRepository) DeactivateTeamMembers(ctx context.Context, teamName st
 	var r0 *domains.Team
 	var r1 []*domains.ReassignedPR
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, []string) (*domains.Team, []*domains.ReassignedPR, error)); ok {
-		return rf(ctx, teamName, userIDs)
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, int64) (*domains.Team, []*domains.ReassignedPR, error)); ok {
+		return rf(ctx, teamName, userIDs, ifVersion)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, []string) *domains.Team); ok {
-		r0 = rf(ctx, teamName, userIDs)
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, int64) *domains.Team); ok {
+		r0 = rf(ctx, teamName, userIDs, ifVersion)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domains.Team)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, []string) []*domains.ReassignedPR); ok {
-		r1 = rf(ctx, teamName, userIDs)
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string, int64) []*domains.ReassignedPR); ok {
+		r1 = rf(ctx, teamName, userIDs, ifVersion)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).([]*domains.ReassignedPR)
 		}
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, string, []string) error); ok {
-		r2 = rf(ctx, teamName, userIDs)
+	if rf, ok := ret.Get(2).(func(context.Context, string, []string, int64) error); ok {
+		r2 = rf(ctx, teamName, userIDs, ifVersion)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -101,6 +101,36 @@ func (_m *TeamRepository) GetTeamByName(ctx context.Context, name string) (*doma
 	return r0, r1
 }
 
+// ListTeams provides a mock function with given fields: ctx, filter
+func (_m *TeamRepository) ListTeams(ctx context.Context, filter domains.TeamFilter) (*domains.TeamPage, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTeams")
+	}
+
+	var r0 *domains.TeamPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domains.TeamFilter) (*domains.TeamPage, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domains.TeamFilter) *domains.TeamPage); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.TeamPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domains.TeamFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // TeamExists provides a mock function with given fields: ctx, name
 func (_m *TeamRepository) TeamExists(ctx context.Context, name string) (bool, error) {
 	ret := _m.Called(ctx, name)
@@ -129,6 +159,34 @@ func (_m *TeamRepository) TeamExists(ctx context.Context, name string) (bool, er
 	return r0, r1
 }
 
+// UserIsTeamLead provides a mock function with given fields: ctx, userID, teamName
+func (_m *TeamRepository) UserIsTeamLead(ctx context.Context, userID string, teamName string) (bool, error) {
+	ret := _m.Called(ctx, userID, teamName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UserIsTeamLead")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (bool, error)); ok {
+		return rf(ctx, userID, teamName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) bool); ok {
+		r0 = rf(ctx, userID, teamName)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, teamName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewTeamRepository creates a new instance of TeamRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewTeamRepository(t interface {
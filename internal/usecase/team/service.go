@@ -15,39 +15,59 @@ type TeamRepository interface {
 	CreateTeam(ctx context.Context, team *domains.Team) error
 	TeamExists(ctx context.Context, name string) (bool, error)
 	GetTeamByName(ctx context.Context, name string) (*domains.Team, error)
-	DeactivateTeamMembers(ctx context.Context, teamName string, userIDs []string) (*domains.Team, []*domains.ReassignedPR, error)
+	UserIsTeamLead(ctx context.Context, userID, teamName string) (bool, error)
+	DeactivateTeamMembers(
+		ctx context.Context,
+		teamName string,
+		userIDs []string,
+		ifVersion int64,
+	) (*domains.Team, []*domains.ReassignedPR, error)
+	ListTeams(ctx context.Context, filter domains.TeamFilter) (*domains.TeamPage, error)
 }
 
 type Service struct {
-	log  *slog.Logger
-	repo TeamRepository
+	log       *slog.Logger
+	repo      TeamRepository
+	txManager repository.TxManager
 }
 
-func New(log *slog.Logger, repo TeamRepository) *Service {
-	return &Service{repo: repo, log: log}
+func New(log *slog.Logger, repo TeamRepository, txManager repository.TxManager) *Service {
+	return &Service{repo: repo, log: log, txManager: txManager}
 }
 
 func (s *Service) AddTeam(ctx context.Context, team *domains.Team) (*domains.Team, error) {
 	const op = "usecase.team.AddTeam"
 
-	exists, err := s.repo.TeamExists(ctx, team.Name)
-	if err != nil {
-		s.log.Error("failed to check team existence", slog.String("op", op), slog.String("err", err.Error()))
-		return nil, err
-	}
-	if exists {
-		s.log.Warn("team already exists", slog.String("team", team.Name))
-		return nil, usecase.ErrTeamAlreadyExists
-	}
+	var created *domains.Team
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		exists, err := s.repo.TeamExists(ctx, team.Name)
+		if err != nil {
+			s.log.Error("failed to check team existence", slog.String("op", op), slog.String("err", err.Error()))
+			return err
+		}
+		if exists {
+			s.log.Warn("team already exists", slog.String("team", team.Name))
+			return usecase.ErrTeamAlreadyExists
+		}
 
-	if err := s.repo.CreateTeam(ctx, team); err != nil {
-		s.log.Error("failed to create team", slog.String("op", op), slog.String("err", err.Error()))
-		return nil, err
-	}
+		if err := s.repo.CreateTeam(ctx, team); err != nil {
+			if errors.Is(err, repository.ErrUserDeleted) {
+				s.log.Warn("team lists a deleted user", slog.String("team", team.Name))
+				return usecase.ErrUserDeleted
+			}
+			s.log.Error("failed to create team", slog.String("op", op), slog.String("err", err.Error()))
+			return err
+		}
 
-	created, err := s.repo.GetTeamByName(ctx, team.Name)
+		created, err = s.repo.GetTeamByName(ctx, team.Name)
+		if err != nil {
+			s.log.Error("failed to get created team", slog.String("op", op), slog.String("err", err.Error()))
+			return err
+		}
+
+		return nil
+	})
 	if err != nil {
-		s.log.Error("failed to get created team", slog.String("op", op), slog.String("err", err.Error()))
 		return nil, err
 	}
 
@@ -73,40 +93,87 @@ func (s *Service) GetTeam(ctx context.Context, teamName string) (*domains.Team,
 	return team, nil
 }
 
+func (s *Service) ListTeams(ctx context.Context, filter domains.TeamFilter) (*domains.TeamPage, error) {
+	const op = "usecase.team.ListTeams"
+
+	page, err := s.repo.ListTeams(ctx, filter)
+	if err != nil {
+		s.log.Error("failed to list teams", slog.String("op", op), slog.String("err", err.Error()))
+		return nil, err
+	}
+
+	s.log.Info("teams successfully listed", slog.Int("teams_count", len(page.Teams)))
+	return page, nil
+}
+
+func (s *Service) IsTeamLead(ctx context.Context, userID, teamName string) (bool, error) {
+	const op = "usecase.team.IsTeamLead"
+
+	isLead, err := s.repo.UserIsTeamLead(ctx, userID, teamName)
+	if err != nil {
+		s.log.Error("failed to check team lead", slog.String("op", op), slog.String("err", err.Error()))
+		return false, err
+	}
+
+	return isLead, nil
+}
+
+// DeactivateTeamMembers deactivates users of the team. A non-zero ifVersion must match the
+// current team version.
 func (s *Service) DeactivateTeamMembers(
 	ctx context.Context,
 	teamName string,
 	users []string,
+	ifVersion int64,
 ) (*domains.Team, []*domains.ReassignedPR, error) {
 	const op = "usecase.team.DeactivateTeamMembers"
 
-	exists, err := s.repo.TeamExists(ctx, teamName)
-	if err != nil {
-		s.log.Error("failed to check team existence",
-			slog.String("op", op),
-			slog.Any("error", err),
-		)
-		return nil, nil, err
-	}
-	if !exists {
-		s.log.Warn("team does not exist", slog.String("team", teamName))
-		return nil, nil, usecase.ErrTeamNotFound
-	}
+	var (
+		updatedTeam   *domains.Team
+		reassignedPRs []*domains.ReassignedPR
+	)
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		exists, err := s.repo.TeamExists(ctx, teamName)
+		if err != nil {
+			s.log.Error("failed to check team existence",
+				slog.String("op", op),
+				slog.Any("error", err),
+			)
+			return err
+		}
+		if !exists {
+			s.log.Warn("team does not exist", slog.String("team", teamName))
+			return usecase.ErrTeamNotFound
+		}
 
-	updatedTeam, reassignedPRs, err := s.repo.DeactivateTeamMembers(ctx, teamName, users)
-	if err != nil {
-		if errors.Is(err, repository.ErrTeamCompatibility) {
-			s.log.Warn("some users do not belong to the team",
+		updatedTeam, reassignedPRs, err = s.repo.DeactivateTeamMembers(ctx, teamName, users, ifVersion)
+		if err != nil {
+			if errors.Is(err, repository.ErrTeamNotFound) {
+				s.log.Warn("team does not exist", slog.String("team", teamName))
+				return usecase.ErrTeamNotFound
+			}
+			if errors.Is(err, repository.ErrVersionConflict) {
+				s.log.Warn("team version conflict", slog.String("team", teamName), slog.Int64("version", ifVersion))
+				return usecase.ErrVersionConflict
+			}
+			if errors.Is(err, repository.ErrTeamCompatibility) {
+				s.log.Warn("some users do not belong to the team",
+					slog.String("team", teamName),
+					slog.Any("users", users),
+				)
+				return usecase.ErrTeamCompatibility
+			}
+			s.log.Error("failed to deactivate team members",
+				slog.String("op", op),
+				slog.Any("error", err),
 				slog.String("team", teamName),
-				slog.Any("users", users),
 			)
-			return nil, nil, usecase.ErrTeamCompatibility
+			return err
 		}
-		s.log.Error("failed to deactivate team members",
-			slog.String("op", op),
-			slog.Any("error", err),
-			slog.String("team", teamName),
-		)
+
+		return nil
+	})
+	if err != nil {
 		return nil, nil, err
 	}
 
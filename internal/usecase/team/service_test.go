@@ -3,12 +3,14 @@ package team
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"testing"
 
 	"github.com/Deymos01/pr-review-manager/internal/domains"
 	"github.com/Deymos01/pr-review-manager/internal/repository"
+	repomocks "github.com/Deymos01/pr-review-manager/internal/repository/mocks"
 	"github.com/Deymos01/pr-review-manager/internal/usecase"
 	"github.com/Deymos01/pr-review-manager/internal/usecase/team/mocks"
 	"github.com/stretchr/testify/mock"
@@ -65,6 +67,13 @@ func TestService_AddTeam(t *testing.T) {
 			mockErrCreate: errors.New("create team error"),
 			expectedErr:   errors.New("create team error"),
 		},
+		{
+			name:          "Deleted member",
+			teamExists:    false,
+			team:          teamSample,
+			mockErrCreate: fmt.Errorf("storage.postgres.CreateTeam: %w", repository.ErrUserDeleted),
+			expectedErr:   usecase.ErrUserDeleted,
+		},
 		{
 			name:        "GetTeamByName returns error",
 			teamExists:  false,
@@ -101,7 +110,7 @@ func TestService_AddTeam(t *testing.T) {
 					Once()
 			}
 
-			svc := New(discardLogger(), teamRepo)
+			svc := New(discardLogger(), teamRepo, repomocks.PassThroughTx(t))
 			team, err := svc.AddTeam(context.Background(), tc.team)
 
 			if tc.expectedErr != nil {
@@ -169,7 +178,7 @@ func TestService_GetTeam(t *testing.T) {
 				Return(tc.team, tc.mockErrTeam).
 				Once()
 
-			svc := New(discardLogger(), teamRepo)
+			svc := New(discardLogger(), teamRepo, repomocks.NewTxManager(t))
 			team, err := svc.GetTeam(context.Background(), "team")
 
 			if tc.expectedErr != nil {
@@ -187,3 +196,115 @@ func TestService_GetTeam(t *testing.T) {
 		})
 	}
 }
+
+func TestService_IsTeamLead(t *testing.T) {
+	type testCase struct {
+		name   string
+		isLead bool
+
+		mockErr error
+
+		expectedErr error
+	}
+
+	cases := []testCase{
+		{
+			name:   "Lead",
+			isLead: true,
+		},
+		{
+			name:   "Not a lead",
+			isLead: false,
+		},
+		{
+			name:        "UserIsTeamLead returns error",
+			mockErr:     errors.New("is lead error"),
+			expectedErr: errors.New("is lead error"),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			teamRepo := mocks.NewTeamRepository(t)
+
+			teamRepo.
+				On("UserIsTeamLead", mock.Anything, "u1", "team").
+				Return(tc.isLead, tc.mockErr).
+				Once()
+
+			svc := New(discardLogger(), teamRepo, repomocks.NewTxManager(t))
+			isLead, err := svc.IsTeamLead(context.Background(), "u1", "team")
+
+			if tc.expectedErr != nil {
+				require.Error(t, err)
+				require.Equal(t, tc.expectedErr, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.isLead, isLead)
+		})
+	}
+}
+
+func TestService_ListTeams(t *testing.T) {
+	filter := domains.TeamFilter{NamePrefix: "back", Limit: 2}
+
+	type testCase struct {
+		name string
+
+		mockPage *domains.TeamPage
+		mockErr  error
+
+		expectedErr error
+	}
+
+	cases := []testCase{
+		{
+			name: "Success",
+			mockPage: &domains.TeamPage{
+				Teams: []*domains.TeamSummary{
+					{Name: "backend", MembersCount: 3, ActiveMembers: 2},
+					{Name: "backoffice", MembersCount: 1, ActiveMembers: 1},
+				},
+				Next: &domains.Cursor{Key: "backoffice", ID: "backoffice"},
+			},
+		},
+		{
+			name:        "ListTeams returns error",
+			mockErr:     errors.New("list error"),
+			expectedErr: errors.New("list error"),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			teamRepo := mocks.NewTeamRepository(t)
+
+			teamRepo.
+				On("ListTeams", mock.Anything, filter).
+				Return(tc.mockPage, tc.mockErr).
+				Once()
+
+			svc := New(discardLogger(), teamRepo, repomocks.NewTxManager(t))
+			page, err := svc.ListTeams(context.Background(), filter)
+
+			if tc.expectedErr != nil {
+				require.Error(t, err)
+				require.Equal(t, tc.expectedErr, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.mockPage, page)
+		})
+	}
+}
@@ -12,4 +12,12 @@ var (
 	ErrNoAvailableReviewer = errors.New("no available reviewer")
 	ErrUserNotAssigned     = errors.New("user not assigned to the pull request")
 	ErrTeamCompatibility   = errors.New("some users do not belong to the team")
+	ErrInvalidImport       = errors.New("invalid import")
+	ErrInvalidSnapshot     = errors.New("invalid snapshot")
+	ErrStorageNotEmpty     = errors.New("storage is not empty")
+	ErrInvalidCursor       = errors.New("invalid cursor")
+	ErrVersionConflict     = errors.New("version conflict")
+	ErrUserDeleted         = errors.New("user is deleted")
+	ErrNoNewAuthor         = errors.New("no user to take over the pull requests")
+	ErrInvalidRetention    = errors.New("invalid retention policy")
 )
@@ -0,0 +1,77 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domains "github.com/Deymos01/pr-review-manager/internal/domains"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PullRequestRepository is an autogenerated mock type for the PullRequestRepository type
+type PullRequestRepository struct {
+	mock.Mock
+}
+
+// ListPullRequests provides a mock function with given fields: ctx, filter
+func (_m *PullRequestRepository) ListPullRequests(ctx context.Context, filter domains.PullRequestFilter) (*domains.PullRequestPage, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPullRequests")
+	}
+
+	var r0 *domains.PullRequestPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domains.PullRequestFilter) (*domains.PullRequestPage, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domains.PullRequestFilter) *domains.PullRequestPage); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.PullRequestPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domains.PullRequestFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePullRequest provides a mock function with given fields: ctx, prID, upd
+func (_m *PullRequestRepository) UpdatePullRequest(ctx context.Context, prID string, upd domains.PullRequestUpdate) error {
+	ret := _m.Called(ctx, prID, upd)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePullRequest")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domains.PullRequestUpdate) error); ok {
+		r0 = rf(ctx, prID, upd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPullRequestRepository creates a new instance of PullRequestRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPullRequestRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PullRequestRepository {
+	mock := &PullRequestRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -14,6 +14,165 @@ type UserRepository struct {
 	mock.Mock
 }
 
+// DeactivateTeamMembers provides a mock function with given fields: ctx, teamName, userIDs, ifVersion
+func (_m *UserRepository) DeactivateTeamMembers(ctx context.Context, teamName string, userIDs []string, ifVersion int64) (*domains.Team, []*domains.ReassignedPR, error) {
+	ret := _m.Called(ctx, teamName, userIDs, ifVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeactivateTeamMembers")
+	}
+
+	var r0 *domains.Team
+	var r1 []*domains.ReassignedPR
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, int64) (*domains.Team, []*domains.ReassignedPR, error)); ok {
+		return rf(ctx, teamName, userIDs, ifVersion)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, int64) *domains.Team); ok {
+		r0 = rf(ctx, teamName, userIDs, ifVersion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.Team)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string, int64) []*domains.ReassignedPR); ok {
+		r1 = rf(ctx, teamName, userIDs, ifVersion)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]*domains.ReassignedPR)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, []string, int64) error); ok {
+		r2 = rf(ctx, teamName, userIDs, ifVersion)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetReviewStats provides a mock function with given fields: ctx, userID
+func (_m *UserRepository) GetReviewStats(ctx context.Context, userID string) (*domains.ReviewStats, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReviewStats")
+	}
+
+	var r0 *domains.ReviewStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domains.ReviewStats, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domains.ReviewStats); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.ReviewStats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByID provides a mock function with given fields: ctx, userID
+func (_m *UserRepository) GetUserByID(ctx context.Context, userID string) (*domains.User, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByID")
+	}
+
+	var r0 *domains.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domains.User, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domains.User); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RebalanceReviews provides a mock function with given fields: ctx, userID
+func (_m *UserRepository) RebalanceReviews(ctx context.Context, userID string) ([]*domains.ReassignedPR, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RebalanceReviews")
+	}
+
+	var r0 []*domains.ReassignedPR
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*domains.ReassignedPR, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*domains.ReassignedPR); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domains.ReassignedPR)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchUsers provides a mock function with given fields: ctx, filter
+func (_m *UserRepository) SearchUsers(ctx context.Context, filter domains.UserFilter) (*domains.UserPage, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchUsers")
+	}
+
+	var r0 *domains.UserPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domains.UserFilter) (*domains.UserPage, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domains.UserFilter) *domains.UserPage); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.UserPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domains.UserFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SetUserStatus provides a mock function with given fields: ctx, userID, isActive
 func (_m *UserRepository) SetUserStatus(ctx context.Context, userID string, isActive bool) (*domains.User, error) {
 	ret := _m.Called(ctx, userID, isActive)
@@ -44,24 +203,24 @@ func (_m *UserRepository) SetUserStatus(ctx context.Context, userID string, isAc
 	return r0, r1
 }
 
-// UsersReview provides a mock function with given fields: ctx, userID
-func (_m *UserRepository) UsersReview(ctx context.Context, userID string) ([]*domains.PullRequest, error) {
+// SoftDeleteUser provides a mock function with given fields: ctx, userID
+func (_m *UserRepository) SoftDeleteUser(ctx context.Context, userID string) (*domains.User, error) {
 	ret := _m.Called(ctx, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UsersReview")
+		panic("no return value specified for SoftDeleteUser")
 	}
 
-	var r0 []*domains.PullRequest
+	var r0 *domains.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*domains.PullRequest, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domains.User, error)); ok {
 		return rf(ctx, userID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []*domains.PullRequest); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domains.User); ok {
 		r0 = rf(ctx, userID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*domains.PullRequest)
+			r0 = ret.Get(0).(*domains.User)
 		}
 	}
 
@@ -74,6 +233,36 @@ func (_m *UserRepository) UsersReview(ctx context.Context, userID string) ([]*do
 	return r0, r1
 }
 
+// UsersReview provides a mock function with given fields: ctx, userID, filter
+func (_m *UserRepository) UsersReview(ctx context.Context, userID string, filter domains.ReviewFilter) (*domains.ReviewPage, error) {
+	ret := _m.Called(ctx, userID, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UsersReview")
+	}
+
+	var r0 *domains.ReviewPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domains.ReviewFilter) (*domains.ReviewPage, error)); ok {
+		return rf(ctx, userID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domains.ReviewFilter) *domains.ReviewPage); ok {
+		r0 = rf(ctx, userID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.ReviewPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domains.ReviewFilter) error); ok {
+		r1 = rf(ctx, userID, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewUserRepository creates a new instance of UserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserRepository(t interface {
@@ -2,48 +2,377 @@ package user
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 
 	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/repository"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@v2.53.5 --name=UserRepository
 type UserRepository interface {
+	GetUserByID(ctx context.Context, userID string) (*domains.User, error)
 	SetUserStatus(ctx context.Context, userID string, isActive bool) (*domains.User, error)
-	UsersReview(ctx context.Context, userID string) ([]*domains.PullRequest, error)
+	UsersReview(ctx context.Context, userID string, filter domains.ReviewFilter) (*domains.ReviewPage, error)
+	RebalanceReviews(ctx context.Context, userID string) ([]*domains.ReassignedPR, error)
+	DeactivateTeamMembers(
+		ctx context.Context,
+		teamName string,
+		userIDs []string,
+		ifVersion int64,
+	) (*domains.Team, []*domains.ReassignedPR, error)
+	SearchUsers(ctx context.Context, filter domains.UserFilter) (*domains.UserPage, error)
+	SoftDeleteUser(ctx context.Context, userID string) (*domains.User, error)
+	GetReviewStats(ctx context.Context, userID string) (*domains.ReviewStats, error)
 }
 
+//go:generate go run github.com/vektra/mockery/v2@v2.53.5 --name=PullRequestRepository
+type PullRequestRepository interface {
+	ListPullRequests(ctx context.Context, filter domains.PullRequestFilter) (*domains.PullRequestPage, error)
+	UpdatePullRequest(ctx context.Context, prID string, upd domains.PullRequestUpdate) error
+}
+
+// OffboardResult describes what offboarding a user changed.
+type OffboardResult struct {
+	User *domains.User
+	// NewAuthorID took over TransferredPRs; it is empty when the user authored no open pull requests
+	NewAuthorID    string
+	TransferredPRs []string
+	ReassignedPRs  []*domains.ReassignedPR
+}
+
+// offboardPageSize is how many authored pull requests are loaded at once while offboarding.
+const offboardPageSize = 100
+
 type Service struct {
-	log  *slog.Logger
-	repo UserRepository
+	log       *slog.Logger
+	repo      UserRepository
+	prRepo    PullRequestRepository
+	txManager repository.TxManager
 }
 
-func New(log *slog.Logger, repo UserRepository) *Service {
-	return &Service{repo: repo, log: log}
+func New(
+	log *slog.Logger,
+	repo UserRepository,
+	prRepo PullRequestRepository,
+	txManager repository.TxManager,
+) *Service {
+	return &Service{
+		log:       log,
+		repo:      repo,
+		prRepo:    prRepo,
+		txManager: txManager,
+	}
 }
 
-func (s *Service) SetUserIsActive(ctx context.Context, userID string, isActive bool) (*domains.User, error) {
+// SetUserIsActive updates the user's activity flag. A deactivated user is released from
+// their open reviews the same way /team/deactivate does it, unless opts.KeepReviews is set.
+// A reactivated user with opts.Rebalance takes a fair share of open reviews from the most loaded teammates.
+func (s *Service) SetUserIsActive(
+	ctx context.Context,
+	userID string,
+	isActive bool,
+	opts domains.StatusOptions,
+) (*domains.User, []*domains.ReassignedPR, error) {
 	const op = "usecase.user.SetUserIsActive"
 
-	user, err := s.repo.SetUserStatus(ctx, userID, isActive)
+	var (
+		user       *domains.User
+		reassigned []*domains.ReassignedPR
+	)
+	// The status change and the reviews it moves are committed together
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if !isActive && !opts.KeepReviews {
+			user, reassigned, err = s.deactivateUser(ctx, userID)
+			return err
+		}
+
+		user, err = s.repo.SetUserStatus(ctx, userID, isActive)
+		if err != nil {
+			if errors.Is(err, repository.ErrUserNotFound) {
+				s.log.Warn("user not found", slog.String("user_id", userID))
+				return usecase.ErrUserNotFound
+			}
+			s.log.Error("failed to set user is_active", slog.String("op", op), slog.String("err", err.Error()))
+			return err
+		}
+
+		if !isActive || !opts.Rebalance {
+			return nil
+		}
+
+		reassigned, err = s.repo.RebalanceReviews(ctx, userID)
+		if err != nil {
+			s.log.Error("failed to rebalance reviews", slog.String("op", op), slog.String("err", err.Error()))
+			return err
+		}
+
+		return nil
+	})
 	if err != nil {
-		s.log.Error("failed to set user is_active", slog.String("op", op), slog.String("err", err.Error()))
-		return nil, err
+		return nil, nil, err
 	}
 
-	s.log.Info("user is_active successfully updated", slog.String("user_id", userID), slog.Bool("is_active", isActive))
-	return user, nil
+	s.log.Info("user is_active successfully updated",
+		slog.String("user_id", userID),
+		slog.Bool("is_active", isActive),
+		slog.Int("reassigned_count", len(reassigned)))
+	return user, reassigned, nil
 }
 
-func (s *Service) GetUsersReview(ctx context.Context, userID string) ([]*domains.PullRequest, error) {
+// deactivateUser releases the open reviews of the user. It runs inside the transaction of
+// SetUserIsActive.
+func (s *Service) deactivateUser(ctx context.Context, userID string) (*domains.User, []*domains.ReassignedPR, error) {
+	const op = "usecase.user.deactivateUser"
+
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			s.log.Warn("user not found", slog.String("user_id", userID))
+			return nil, nil, usecase.ErrUserNotFound
+		}
+		s.log.Error("failed to get user", slog.String("op", op), slog.String("err", err.Error()))
+		return nil, nil, err
+	}
+
+	// Without a team there is nobody to hand the reviews over to
+	if user.TeamName == nil {
+		user, err = s.repo.SetUserStatus(ctx, userID, false)
+		if err != nil {
+			s.log.Error("failed to set user is_active", slog.String("op", op), slog.String("err", err.Error()))
+			return nil, nil, err
+		}
+
+		return user, nil, nil
+	}
+
+	_, reassigned, err := s.repo.DeactivateTeamMembers(ctx, *user.TeamName, []string{userID}, 0)
+	if err != nil {
+		s.log.Error("failed to deactivate user",
+			slog.String("op", op),
+			slog.String("err", err.Error()),
+			slog.String("user_id", userID))
+		return nil, nil, err
+	}
+	user.IsActive = false
+
+	return user, reassigned, nil
+}
+
+func (s *Service) GetUsersReview(ctx context.Context, userID string, filter domains.ReviewFilter) (*domains.ReviewPage, error) {
 	const op = "usecase.user.GetUsersReview"
 
-	reviews, err := s.repo.UsersReview(ctx, userID)
+	page, err := s.repo.UsersReview(ctx, userID, filter)
 	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			s.log.Warn("invalid review cursor", slog.String("user_id", userID))
+			return nil, usecase.ErrInvalidCursor
+		}
+
 		s.log.Error("failed to get user reviews", slog.String("op", op), slog.String("err", err.Error()))
 		return nil, err
 	}
 
-	s.log.Info("user reviews successfully retrieved", slog.String("user_id", userID), slog.Int("reviews_count", len(reviews)))
-	return reviews, nil
+	s.log.Info("user reviews successfully retrieved", slog.String("user_id", userID), slog.Int("reviews_count", len(page.Reviews)))
+	return page, nil
+}
+
+func (s *Service) SearchUsers(ctx context.Context, filter domains.UserFilter) (*domains.UserPage, error) {
+	const op = "usecase.user.SearchUsers"
+
+	page, err := s.repo.SearchUsers(ctx, filter)
+	if err != nil {
+		s.log.Error("failed to search users", slog.String("op", op), slog.String("err", err.Error()))
+		return nil, err
+	}
+
+	s.log.Info("users successfully listed", slog.Int("users_count", len(page.Users)))
+	return page, nil
+}
+
+// GetReviewStats returns the all-time totals of the user, including pull requests that
+// retention has already removed.
+func (s *Service) GetReviewStats(ctx context.Context, userID string) (*domains.ReviewStats, error) {
+	const op = "usecase.user.GetReviewStats"
+
+	if _, err := s.repo.GetUserByID(ctx, userID); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			s.log.Warn("user not found", slog.String("user_id", userID))
+			return nil, usecase.ErrUserNotFound
+		}
+		s.log.Error("failed to get user", slog.String("op", op), slog.String("err", err.Error()))
+		return nil, err
+	}
+
+	stats, err := s.repo.GetReviewStats(ctx, userID)
+	if err != nil {
+		s.log.Error("failed to get review stats", slog.String("op", op), slog.String("err", err.Error()))
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// OffboardUser removes a departed user while keeping the history of their work: open reviews
+// are released like on deactivation, open pull requests they authored move to newAuthorID,
+// and the user is anonymized and soft-deleted. With an empty newAuthorID an active teammate
+// takes over, team leads first.
+func (s *Service) OffboardUser(ctx context.Context, userID, newAuthorID string) (*OffboardResult, error) {
+	const op = "usecase.user.OffboardUser"
+
+	var result *OffboardResult
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		result = &OffboardResult{}
+
+		user, err := s.repo.GetUserByID(ctx, userID)
+		if err != nil {
+			if errors.Is(err, repository.ErrUserNotFound) {
+				s.log.Warn("user not found", slog.String("user_id", userID))
+				return usecase.ErrUserNotFound
+			}
+			s.log.Error("failed to get user", slog.String("op", op), slog.String("err", err.Error()))
+			return err
+		}
+
+		if user.TeamName != nil {
+			_, result.ReassignedPRs, err = s.repo.DeactivateTeamMembers(ctx, *user.TeamName, []string{userID}, 0)
+			if err != nil {
+				s.log.Error("failed to release reviews", slog.String("op", op), slog.String("err", err.Error()))
+				return err
+			}
+		}
+
+		prs, err := s.openPullRequests(ctx, userID)
+		if err != nil {
+			s.log.Error("failed to list authored pull requests", slog.String("op", op), slog.String("err", err.Error()))
+			return err
+		}
+
+		if len(prs) > 0 {
+			result.NewAuthorID, err = s.newAuthor(ctx, user, newAuthorID)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, pr := range prs {
+			upd := domains.PullRequestUpdate{AuthorID: &result.NewAuthorID, Version: pr.Version}
+			if err := s.prRepo.UpdatePullRequest(ctx, pr.ID, upd); err != nil {
+				s.log.Error("failed to transfer pull request", slog.String("op", op), slog.String("err", err.Error()))
+				return err
+			}
+			result.TransferredPRs = append(result.TransferredPRs, pr.ID)
+		}
+
+		result.User, err = s.repo.SoftDeleteUser(ctx, userID)
+		if err != nil {
+			s.log.Error("failed to delete user", slog.String("op", op), slog.String("err", err.Error()))
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("user offboarded",
+		slog.String("user_id", userID),
+		slog.Int("transferred_count", len(result.TransferredPRs)),
+		slog.Int("reassigned_count", len(result.ReassignedPRs)))
+	return result, nil
+}
+
+// openPullRequests loads every open pull request authored by userID.
+func (s *Service) openPullRequests(ctx context.Context, userID string) ([]*domains.PullRequest, error) {
+	filter := domains.PullRequestFilter{
+		Status:   domains.PRStatusOpen,
+		AuthorID: userID,
+		Limit:    offboardPageSize,
+	}
+
+	var prs []*domains.PullRequest
+	for {
+		page, err := s.prRepo.ListPullRequests(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		prs = append(prs, page.PullRequests...)
+
+		if page.Next == nil {
+			return prs, nil
+		}
+		filter.After = page.Next
+	}
+}
+
+// newAuthor checks the requested new author or picks an active teammate of user, leads first.
+func (s *Service) newAuthor(ctx context.Context, user *domains.User, requested string) (string, error) {
+	const op = "usecase.user.newAuthor"
+
+	if requested != "" {
+		if requested == user.ID {
+			s.log.Warn("user cannot take over own pull requests", slog.String("user_id", user.ID))
+			return "", usecase.ErrNoNewAuthor
+		}
+
+		author, err := s.repo.GetUserByID(ctx, requested)
+		if err != nil {
+			if errors.Is(err, repository.ErrUserNotFound) {
+				s.log.Warn("new author not found", slog.String("new_author_id", requested))
+				return "", usecase.ErrUserNotFound
+			}
+			s.log.Error("failed to get new author", slog.String("op", op), slog.String("err", err.Error()))
+			return "", err
+		}
+		if !author.IsActive {
+			s.log.Warn("new author is inactive", slog.String("new_author_id", requested))
+			return "", usecase.ErrNoNewAuthor
+		}
+
+		return author.ID, nil
+	}
+
+	if user.TeamName == nil {
+		s.log.Warn("no teammates to take over pull requests", slog.String("user_id", user.ID))
+		return "", usecase.ErrNoNewAuthor
+	}
+
+	isActive := true
+	filter := domains.UserFilter{IsActive: &isActive, TeamName: user.TeamName, Limit: offboardPageSize}
+
+	var fallback string
+	for {
+		page, err := s.repo.SearchUsers(ctx, filter)
+		if err != nil {
+			s.log.Error("failed to search teammates", slog.String("op", op), slog.String("err", err.Error()))
+			return "", err
+		}
+
+		for _, teammate := range page.Users {
+			if teammate.ID == user.ID {
+				continue
+			}
+			if teammate.Role == domains.RoleLead {
+				return teammate.ID, nil
+			}
+			if fallback == "" {
+				fallback = teammate.ID
+			}
+		}
+
+		if page.Next == nil {
+			break
+		}
+		filter.After = page.Next
+	}
+
+	if fallback == "" {
+		s.log.Warn("no teammates to take over pull requests", slog.String("user_id", user.ID))
+		return "", usecase.ErrNoNewAuthor
+	}
+
+	return fallback, nil
 }
@@ -8,6 +8,9 @@ import (
 	"testing"
 
 	"github.com/Deymos01/pr-review-manager/internal/domains"
+	"github.com/Deymos01/pr-review-manager/internal/repository"
+	repomocks "github.com/Deymos01/pr-review-manager/internal/repository/mocks"
+	"github.com/Deymos01/pr-review-manager/internal/usecase"
 	"github.com/Deymos01/pr-review-manager/internal/usecase/user/mocks"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -21,16 +24,24 @@ func TestService_SetUserIsActive(t *testing.T) {
 	userSample := &domains.User{
 		ID:       "123",
 		Name:     "John",
+		TeamName: ptr("team"),
 		IsActive: true,
 	}
 
+	reassignedSample := []*domains.ReassignedPR{
+		{PrID: "pr1", OldUserID: "456", NewUserID: "123"},
+	}
+
 	type testCase struct {
 		name     string
 		userID   string
 		isActive bool
+		opts     domains.StatusOptions
 
-		mockUser *domains.User
-		mockErr  error
+		mockUser       *domains.User
+		mockErr        error
+		mockReassigned []*domains.ReassignedPR
+		mockErrBalance error
 
 		expectedErr error
 	}
@@ -45,10 +56,41 @@ func TestService_SetUserIsActive(t *testing.T) {
 		{
 			name:        "SetUserStatus returns error",
 			userID:      "123",
-			isActive:    false,
+			isActive:    true,
 			mockErr:     errors.New("update error"),
 			expectedErr: errors.New("update error"),
 		},
+		{
+			name:        "User not found",
+			userID:      "123",
+			isActive:    true,
+			mockErr:     repository.ErrUserNotFound,
+			expectedErr: usecase.ErrUserNotFound,
+		},
+		{
+			name:           "Reactivation with rebalance",
+			userID:         "123",
+			isActive:       true,
+			opts:           domains.StatusOptions{Rebalance: true},
+			mockUser:       userSample,
+			mockReassigned: reassignedSample,
+		},
+		{
+			name:           "RebalanceReviews returns error",
+			userID:         "123",
+			isActive:       true,
+			opts:           domains.StatusOptions{Rebalance: true},
+			mockUser:       userSample,
+			mockErrBalance: errors.New("rebalance error"),
+			expectedErr:    errors.New("rebalance error"),
+		},
+		{
+			name:     "Deactivation keeping reviews",
+			userID:   "123",
+			isActive: false,
+			opts:     domains.StatusOptions{KeepReviews: true, Rebalance: true},
+			mockUser: &domains.User{ID: "123", Name: "John"},
+		},
 	}
 
 	for _, tc := range cases {
@@ -64,8 +106,15 @@ func TestService_SetUserIsActive(t *testing.T) {
 				Return(tc.mockUser, tc.mockErr).
 				Once()
 
-			svc := New(discardLogger(), userRepo)
-			user, err := svc.SetUserIsActive(context.Background(), tc.userID, tc.isActive)
+			if tc.mockErr == nil && tc.isActive && tc.opts.Rebalance {
+				userRepo.
+					On("RebalanceReviews", mock.Anything, tc.userID).
+					Return(tc.mockReassigned, tc.mockErrBalance).
+					Once()
+			}
+
+			svc := New(discardLogger(), userRepo, nil, repomocks.PassThroughTx(t))
+			user, reassigned, err := svc.SetUserIsActive(context.Background(), tc.userID, tc.isActive, tc.opts)
 
 			if tc.expectedErr != nil {
 				require.Error(t, err)
@@ -76,31 +125,137 @@ func TestService_SetUserIsActive(t *testing.T) {
 			require.NoError(t, err)
 			require.Equal(t, tc.mockUser.ID, user.ID)
 			require.Equal(t, tc.mockUser.IsActive, user.IsActive)
+			require.Equal(t, tc.mockReassigned, reassigned)
+		})
+	}
+}
+
+func TestService_SetUserIsActive_Deactivation(t *testing.T) {
+	reassignedSample := []*domains.ReassignedPR{
+		{PrID: "pr1", OldUserID: "123", NewUserID: "456"},
+	}
+
+	type testCase struct {
+		name string
+
+		mockUser          *domains.User
+		mockErrGet        error
+		mockErrDeactivate error
+		mockErrStatus     error
+		mockReassigned    []*domains.ReassignedPR
+
+		expectedErr error
+	}
+
+	cases := []testCase{
+		{
+			name:           "Reviews released",
+			mockUser:       &domains.User{ID: "123", Name: "John", TeamName: ptr("team"), IsActive: true},
+			mockReassigned: reassignedSample,
+		},
+		{
+			name:     "User without team",
+			mockUser: &domains.User{ID: "123", Name: "John", IsActive: true},
+		},
+		{
+			name:        "User not found",
+			mockErrGet:  repository.ErrUserNotFound,
+			expectedErr: usecase.ErrUserNotFound,
+		},
+		{
+			name:        "GetUserByID returns error",
+			mockErrGet:  errors.New("get error"),
+			expectedErr: errors.New("get error"),
+		},
+		{
+			name:              "DeactivateTeamMembers returns error",
+			mockUser:          &domains.User{ID: "123", Name: "John", TeamName: ptr("team"), IsActive: true},
+			mockErrDeactivate: errors.New("deactivate error"),
+			expectedErr:       errors.New("deactivate error"),
+		},
+		{
+			name:          "SetUserStatus returns error",
+			mockUser:      &domains.User{ID: "123", Name: "John", IsActive: true},
+			mockErrStatus: errors.New("update error"),
+			expectedErr:   errors.New("update error"),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			userRepo := mocks.NewUserRepository(t)
+
+			userRepo.
+				On("GetUserByID", mock.Anything, "123").
+				Return(tc.mockUser, tc.mockErrGet).
+				Once()
+
+			if tc.mockErrGet == nil && tc.mockUser.TeamName != nil {
+				userRepo.
+					On("DeactivateTeamMembers", mock.Anything, *tc.mockUser.TeamName, []string{"123"}, int64(0)).
+					Return(&domains.Team{Name: *tc.mockUser.TeamName}, tc.mockReassigned, tc.mockErrDeactivate).
+					Once()
+			}
+			if tc.mockErrGet == nil && tc.mockUser.TeamName == nil {
+				userRepo.
+					On("SetUserStatus", mock.Anything, "123", false).
+					Return(&domains.User{ID: "123", Name: "John"}, tc.mockErrStatus).
+					Once()
+			}
+
+			svc := New(discardLogger(), userRepo, nil, repomocks.PassThroughTx(t))
+			user, reassigned, err := svc.SetUserIsActive(context.Background(), "123", false, domains.StatusOptions{})
+
+			if tc.expectedErr != nil {
+				require.Error(t, err)
+				require.Equal(t, tc.expectedErr, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, "123", user.ID)
+			require.False(t, user.IsActive)
+			require.Equal(t, tc.mockReassigned, reassigned)
 		})
 	}
 }
 
 func TestService_GetUsersReview(t *testing.T) {
-	reviewSample := []*domains.PullRequest{
-		{ID: "1", Name: "Fix bug"},
-		{ID: "2", Name: "Add feature"},
+	filter := domains.ReviewFilter{Status: "OPEN", Limit: 2}
+
+	pageSample := &domains.ReviewPage{
+		Reviews: []*domains.Review{
+			{PullRequest: &domains.PullRequest{ID: "1", Name: "Fix bug"}},
+			{PullRequest: &domains.PullRequest{ID: "2", Name: "Add feature"}},
+		},
+		Next: &domains.Cursor{Key: "2025-10-20T09:00:00Z", ID: "2"},
 	}
 
 	type testCase struct {
 		name   string
 		userID string
 
-		mockReviews []*domains.PullRequest
-		mockErr     error
+		mockPage *domains.ReviewPage
+		mockErr  error
 
 		expectedErr error
 	}
 
 	cases := []testCase{
 		{
-			name:        "Success",
+			name:     "Success",
+			userID:   "123",
+			mockPage: pageSample,
+		},
+		{
+			name:        "Invalid cursor",
 			userID:      "123",
-			mockReviews: reviewSample,
+			mockErr:     repository.ErrInvalidCursor,
+			expectedErr: usecase.ErrInvalidCursor,
 		},
 		{
 			name:        "UsersReview returns error",
@@ -119,12 +274,69 @@ func TestService_GetUsersReview(t *testing.T) {
 			userRepo := mocks.NewUserRepository(t)
 
 			userRepo.
-				On("UsersReview", mock.Anything, tc.userID).
-				Return(tc.mockReviews, tc.mockErr).
+				On("UsersReview", mock.Anything, tc.userID, filter).
+				Return(tc.mockPage, tc.mockErr).
+				Once()
+
+			svc := New(discardLogger(), userRepo, nil, nil)
+			page, err := svc.GetUsersReview(context.Background(), tc.userID, filter)
+
+			if tc.expectedErr != nil {
+				require.Error(t, err)
+				require.Equal(t, tc.expectedErr, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.mockPage, page)
+		})
+	}
+}
+
+func ptr(s string) *string { return &s }
+
+func TestService_SearchUsers(t *testing.T) {
+	filter := domains.UserFilter{TeamName: ptr("team"), SortBy: domains.UserSortByName, Limit: 1}
+
+	type testCase struct {
+		name string
+
+		mockPage *domains.UserPage
+		mockErr  error
+
+		expectedErr error
+	}
+
+	cases := []testCase{
+		{
+			name: "Success",
+			mockPage: &domains.UserPage{
+				Users: []*domains.User{{ID: "u1", Name: "Alice", TeamName: ptr("team"), IsActive: true}},
+				Next:  &domains.Cursor{Key: "Alice", ID: "u1"},
+			},
+		},
+		{
+			name:        "SearchUsers returns error",
+			mockErr:     errors.New("search error"),
+			expectedErr: errors.New("search error"),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			userRepo := mocks.NewUserRepository(t)
+
+			userRepo.
+				On("SearchUsers", mock.Anything, filter).
+				Return(tc.mockPage, tc.mockErr).
 				Once()
 
-			svc := New(discardLogger(), userRepo)
-			reviews, err := svc.GetUsersReview(context.Background(), tc.userID)
+			svc := New(discardLogger(), userRepo, nil, nil)
+			page, err := svc.SearchUsers(context.Background(), filter)
 
 			if tc.expectedErr != nil {
 				require.Error(t, err)
@@ -133,11 +345,219 @@ func TestService_GetUsersReview(t *testing.T) {
 			}
 
 			require.NoError(t, err)
-			require.Equal(t, len(tc.mockReviews), len(reviews))
+			require.Equal(t, tc.mockPage, page)
+		})
+	}
+}
+
+func TestService_GetReviewStats(t *testing.T) {
+	stats := &domains.ReviewStats{UserID: "u1", Authored: 4, Reviewed: 7}
+
+	type testCase struct {
+		name string
+
+		mockGetErr   error
+		callStats    bool
+		mockStatsErr error
+
+		expectedErr error
+	}
+
+	cases := []testCase{
+		{
+			name:      "Success",
+			callStats: true,
+		},
+		{
+			name:        "User not found",
+			mockGetErr:  repository.ErrUserNotFound,
+			expectedErr: usecase.ErrUserNotFound,
+		},
+		{
+			name:         "GetReviewStats returns error",
+			callStats:    true,
+			mockStatsErr: errors.New("stats error"),
+			expectedErr:  errors.New("stats error"),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			userRepo := mocks.NewUserRepository(t)
 
-			for i := range reviews {
-				require.Equal(t, tc.mockReviews[i].ID, reviews[i].ID)
-				require.Equal(t, tc.mockReviews[i].Name, reviews[i].Name)
+			userRepo.
+				On("GetUserByID", mock.Anything, "u1").
+				Return(&domains.User{ID: "u1", Name: "Alice", IsActive: true}, tc.mockGetErr).
+				Once()
+			if tc.callStats {
+				userRepo.
+					On("GetReviewStats", mock.Anything, "u1").
+					Return(stats, tc.mockStatsErr).
+					Once()
+			}
+
+			svc := New(discardLogger(), userRepo, nil, nil)
+			got, err := svc.GetReviewStats(context.Background(), "u1")
+
+			if tc.expectedErr != nil {
+				require.Error(t, err)
+				require.Equal(t, tc.expectedErr, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, stats, got)
+		})
+	}
+}
+
+func TestService_OffboardUser(t *testing.T) {
+	member := &domains.User{ID: "123", Name: "John", TeamName: ptr("team"), IsActive: true, Role: domains.RoleMember}
+	deleted := &domains.User{ID: "123", Name: domains.DeletedUserName, Role: domains.RoleMember}
+	openPR := &domains.PullRequest{ID: "pr1", Author: &domains.User{ID: "123"}, Status: domains.PRStatusOpen, Version: 3}
+	reassignedSample := []*domains.ReassignedPR{
+		{PrID: "pr2", OldUserID: "123", NewUserID: "456"},
+	}
+	teammates := &domains.UserPage{Users: []*domains.User{
+		{ID: "456", Name: "Ann", TeamName: ptr("team"), IsActive: true, Role: domains.RoleMember},
+		{ID: "789", Name: "Bob", TeamName: ptr("team"), IsActive: true, Role: domains.RoleLead},
+	}}
+
+	type testCase struct {
+		name        string
+		newAuthorID string
+
+		mockUser       *domains.User
+		mockErrGet     error
+		mockReassigned []*domains.ReassignedPR
+		mockPRs        []*domains.PullRequest
+		mockNewAuthor  *domains.User
+		mockTeammates  *domains.UserPage
+		mockErrUpdate  error
+
+		expectedAuthor string
+		expectedErr    error
+	}
+
+	cases := []testCase{
+		{
+			name:           "Transferred to team lead",
+			mockUser:       member,
+			mockReassigned: reassignedSample,
+			mockPRs:        []*domains.PullRequest{openPR},
+			mockTeammates:  teammates,
+			expectedAuthor: "789",
+		},
+		{
+			name:           "Transferred to requested author",
+			newAuthorID:    "456",
+			mockUser:       member,
+			mockPRs:        []*domains.PullRequest{openPR},
+			mockNewAuthor:  &domains.User{ID: "456", IsActive: true},
+			expectedAuthor: "456",
+		},
+		{
+			name:           "No open pull requests",
+			newAuthorID:    "456",
+			mockUser:       member,
+			mockReassigned: reassignedSample,
+		},
+		{
+			name:        "User not found",
+			mockErrGet:  repository.ErrUserNotFound,
+			expectedErr: usecase.ErrUserNotFound,
+		},
+		{
+			name:          "Requested author inactive",
+			newAuthorID:   "456",
+			mockUser:      member,
+			mockPRs:       []*domains.PullRequest{openPR},
+			mockNewAuthor: &domains.User{ID: "456"},
+			expectedErr:   usecase.ErrNoNewAuthor,
+		},
+		{
+			name:          "No teammates",
+			mockUser:      member,
+			mockPRs:       []*domains.PullRequest{openPR},
+			mockTeammates: &domains.UserPage{Users: []*domains.User{member}},
+			expectedErr:   usecase.ErrNoNewAuthor,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			userRepo := mocks.NewUserRepository(t)
+			prRepo := mocks.NewPullRequestRepository(t)
+
+			userRepo.
+				On("GetUserByID", mock.Anything, "123").
+				Return(tc.mockUser, tc.mockErrGet).
+				Once()
+
+			if tc.mockErrGet == nil {
+				userRepo.
+					On("DeactivateTeamMembers", mock.Anything, "team", []string{"123"}, int64(0)).
+					Return(&domains.Team{Name: "team"}, tc.mockReassigned, nil).
+					Once()
+				prRepo.
+					On("ListPullRequests", mock.Anything, domains.PullRequestFilter{
+						Status:   domains.PRStatusOpen,
+						AuthorID: "123",
+						Limit:    offboardPageSize,
+					}).
+					Return(&domains.PullRequestPage{PullRequests: tc.mockPRs}, nil).
+					Once()
+			}
+			if tc.mockNewAuthor != nil {
+				userRepo.
+					On("GetUserByID", mock.Anything, tc.newAuthorID).
+					Return(tc.mockNewAuthor, nil).
+					Once()
+			}
+			if tc.mockTeammates != nil {
+				userRepo.
+					On("SearchUsers", mock.Anything, mock.Anything).
+					Return(tc.mockTeammates, nil).
+					Once()
+			}
+			if tc.expectedAuthor != "" {
+				author := tc.expectedAuthor
+				prRepo.
+					On("UpdatePullRequest", mock.Anything, "pr1", domains.PullRequestUpdate{AuthorID: &author, Version: 3}).
+					Return(tc.mockErrUpdate).
+					Once()
+			}
+			if tc.expectedErr == nil {
+				userRepo.
+					On("SoftDeleteUser", mock.Anything, "123").
+					Return(deleted, nil).
+					Once()
+			}
+
+			svc := New(discardLogger(), userRepo, prRepo, repomocks.PassThroughTx(t))
+			result, err := svc.OffboardUser(context.Background(), "123", tc.newAuthorID)
+
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, deleted, result.User)
+			require.Equal(t, tc.expectedAuthor, result.NewAuthorID)
+			require.Equal(t, tc.mockReassigned, result.ReassignedPRs)
+			if tc.expectedAuthor != "" {
+				require.Equal(t, []string{"pr1"}, result.TransferredPRs)
+			} else {
+				require.Empty(t, result.TransferredPRs)
 			}
 		})
 	}
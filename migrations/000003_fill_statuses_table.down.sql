@@ -1 +1 @@
-TRUNCATE TABLE statuses CASCADE;
\ No newline at end of file
+DELETE FROM statuses WHERE name IN ('OPEN', 'MERGED');
\ No newline at end of file
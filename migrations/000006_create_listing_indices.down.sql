@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_teams_lower_name;
+DROP INDEX IF EXISTS idx_users_is_active;
+DROP INDEX IF EXISTS idx_users_lower_name;
+DROP INDEX IF EXISTS idx_users_name_id;
\ No newline at end of file
@@ -0,0 +1,4 @@
+CREATE INDEX IF NOT EXISTS idx_users_name_id ON users (name, id);
+CREATE INDEX IF NOT EXISTS idx_users_lower_name ON users (lower(name) text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_users_is_active ON users (is_active);
+CREATE INDEX IF NOT EXISTS idx_teams_lower_name ON teams (lower(name) text_pattern_ops);
\ No newline at end of file
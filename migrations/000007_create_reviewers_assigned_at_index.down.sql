@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_reviewers_user_id_assigned_at;
\ No newline at end of file
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_reviewers_user_id_assigned_at ON reviewers (user_id, assigned_at, pull_request_id);
\ No newline at end of file
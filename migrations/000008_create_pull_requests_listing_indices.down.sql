@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_pull_requests_merged_at;
+DROP INDEX IF EXISTS idx_pull_requests_status_id;
+DROP INDEX IF EXISTS idx_pull_requests_created_at_id;
\ No newline at end of file
@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS idx_pull_requests_created_at_id ON pull_requests (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_status_id ON pull_requests (status_id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_merged_at ON pull_requests (merged_at);
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE pull_requests
+    DROP COLUMN version,
+    DROP COLUMN labels,
+    DROP COLUMN description;
\ No newline at end of file
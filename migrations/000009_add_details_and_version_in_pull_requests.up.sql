@@ -0,0 +1,4 @@
+ALTER TABLE pull_requests
+    ADD COLUMN description TEXT   NOT NULL DEFAULT '',
+    ADD COLUMN labels      TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN version     BIGINT NOT NULL DEFAULT 1;
\ No newline at end of file
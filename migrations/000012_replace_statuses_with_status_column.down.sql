@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS statuses
+(
+    id   SERIAL PRIMARY KEY,
+    name TEXT NOT NULL
+);
+
+INSERT INTO statuses (name)
+VALUES ('OPEN'),
+       ('MERGED');
+
+ALTER TABLE pull_requests
+    ADD COLUMN status_id INT REFERENCES statuses (id);
+
+UPDATE pull_requests pr
+SET status_id = st.id
+FROM statuses st
+WHERE st.name = pr.status;
+
+ALTER TABLE pull_requests
+    ALTER COLUMN status_id SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_pull_requests_status;
+ALTER TABLE pull_requests
+    DROP COLUMN status;
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_status_id ON pull_requests (status_id);
\ No newline at end of file
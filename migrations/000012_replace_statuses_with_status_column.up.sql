@@ -0,0 +1,39 @@
+-- Refuse to migrate while some pull request points at a status other than OPEN or MERGED:
+-- the CHECK constraint below has no place for it and silently rewriting it would lose data.
+DO
+$$
+    DECLARE
+        bad TEXT;
+    BEGIN
+        SELECT string_agg(format('%s (%s)', pr.id, coalesce(st.name, 'status_id ' || pr.status_id)), ', ' ORDER BY pr.id)
+        INTO bad
+        FROM pull_requests pr
+                 LEFT JOIN statuses st ON st.id = pr.status_id
+        WHERE st.name IS NULL
+           OR st.name NOT IN ('OPEN', 'MERGED');
+
+        IF bad IS NOT NULL THEN
+            RAISE EXCEPTION 'pull requests with unsupported statuses: %', bad;
+        END IF;
+    END
+$$;
+
+ALTER TABLE pull_requests
+    ADD COLUMN status TEXT;
+
+UPDATE pull_requests pr
+SET status = st.name
+FROM statuses st
+WHERE st.id = pr.status_id;
+
+ALTER TABLE pull_requests
+    ALTER COLUMN status SET NOT NULL,
+    ALTER COLUMN status SET DEFAULT 'OPEN',
+    ADD CONSTRAINT pull_requests_status_check CHECK (status IN ('OPEN', 'MERGED'));
+
+DROP INDEX IF EXISTS idx_pull_requests_status_id;
+ALTER TABLE pull_requests
+    DROP COLUMN status_id;
+DROP TABLE statuses;
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_status ON pull_requests (status);
\ No newline at end of file
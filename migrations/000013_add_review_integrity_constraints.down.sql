@@ -0,0 +1,17 @@
+DROP TRIGGER IF EXISTS reviewers_forbid_merged ON reviewers;
+DROP FUNCTION IF EXISTS reviewers_forbid_merged();
+DROP TRIGGER IF EXISTS pull_requests_check_author ON pull_requests;
+DROP FUNCTION IF EXISTS pull_requests_check_author();
+DROP TRIGGER IF EXISTS reviewers_check_author ON reviewers;
+DROP FUNCTION IF EXISTS reviewers_check_author();
+
+ALTER TABLE users
+    DROP CONSTRAINT users_team_name_fkey;
+ALTER TABLE teams
+    DROP CONSTRAINT teams_pkey,
+    ADD CONSTRAINT teams_name_key UNIQUE (name);
+ALTER TABLE users
+    ADD CONSTRAINT users_team_name_fkey FOREIGN KEY (team_name) REFERENCES teams (name) ON DELETE SET NULL;
+
+ALTER TABLE users
+    ALTER COLUMN is_active DROP NOT NULL;
\ No newline at end of file
@@ -0,0 +1,102 @@
+-- Report every legacy row the new constraints would reject instead of failing on the first one.
+-- teams.name is already UNIQUE and NOT NULL, so the primary key below cannot be violated.
+DO
+$$
+    DECLARE
+        problems TEXT[] := '{}';
+        bad      TEXT;
+    BEGIN
+        SELECT string_agg(id, ', ' ORDER BY id)
+        INTO bad
+        FROM users
+        WHERE is_active IS NULL;
+        IF bad IS NOT NULL THEN
+            problems := problems || ('users with NULL is_active: ' || bad);
+        END IF;
+
+        SELECT string_agg(format('%s (%s)', r.pull_request_id, r.user_id), ', ' ORDER BY r.pull_request_id)
+        INTO bad
+        FROM reviewers r
+                 JOIN pull_requests pr ON pr.id = r.pull_request_id
+        WHERE r.user_id = pr.author_id;
+        IF bad IS NOT NULL THEN
+            problems := problems || ('pull requests reviewed by their author: ' || bad);
+        END IF;
+
+        IF cardinality(problems) > 0 THEN
+            RAISE EXCEPTION 'integrity constraints cannot be applied: %', array_to_string(problems, '; ');
+        END IF;
+    END
+$$;
+
+ALTER TABLE users
+    ALTER COLUMN is_active SET NOT NULL;
+
+ALTER TABLE users
+    DROP CONSTRAINT users_team_name_fkey;
+ALTER TABLE teams
+    DROP CONSTRAINT teams_name_key,
+    ADD CONSTRAINT teams_pkey PRIMARY KEY (name);
+ALTER TABLE users
+    ADD CONSTRAINT users_team_name_fkey FOREIGN KEY (team_name) REFERENCES teams (name) ON DELETE SET NULL;
+
+CREATE OR REPLACE FUNCTION reviewers_check_author() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF EXISTS(SELECT 1 FROM pull_requests WHERE id = NEW.pull_request_id AND author_id = NEW.user_id) THEN
+        RAISE EXCEPTION 'user % is the author of pull request % and cannot review it', NEW.user_id, NEW.pull_request_id
+            USING ERRCODE = 'check_violation';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER reviewers_check_author
+    BEFORE INSERT OR UPDATE
+    ON reviewers
+    FOR EACH ROW
+EXECUTE FUNCTION reviewers_check_author();
+
+CREATE OR REPLACE FUNCTION pull_requests_check_author() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF EXISTS(SELECT 1 FROM reviewers WHERE pull_request_id = NEW.id AND user_id = NEW.author_id) THEN
+        RAISE EXCEPTION 'user % reviews pull request % and cannot become its author', NEW.author_id, NEW.id
+            USING ERRCODE = 'check_violation';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER pull_requests_check_author
+    BEFORE UPDATE OF author_id
+    ON pull_requests
+    FOR EACH ROW
+EXECUTE FUNCTION pull_requests_check_author();
+
+-- Reviewers of a merged pull request are history. Rows removed together with their pull request
+-- are allowed: by the time the cascade reaches reviewers the pull request is no longer visible.
+CREATE OR REPLACE FUNCTION reviewers_forbid_merged() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        IF EXISTS(SELECT 1 FROM pull_requests WHERE id = OLD.pull_request_id AND status = 'MERGED') THEN
+            RAISE EXCEPTION 'pull request % is merged and its reviewers cannot change', OLD.pull_request_id
+                USING ERRCODE = 'check_violation';
+        END IF;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        IF EXISTS(SELECT 1 FROM pull_requests WHERE id = NEW.pull_request_id AND status = 'MERGED') THEN
+            RAISE EXCEPTION 'pull request % is merged and its reviewers cannot change', NEW.pull_request_id
+                USING ERRCODE = 'check_violation';
+        END IF;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER reviewers_forbid_merged
+    AFTER INSERT OR UPDATE OR DELETE
+    ON reviewers
+    FOR EACH ROW
+EXECUTE FUNCTION reviewers_forbid_merged();
\ No newline at end of file
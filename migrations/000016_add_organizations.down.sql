@@ -0,0 +1,142 @@
+-- Without org the ids of different organizations would collide, so only a deployment that
+-- never served another organization can be rolled back.
+DO
+$$
+    DECLARE
+        orgs TEXT;
+    BEGIN
+        SELECT string_agg(DISTINCT org, ', ' ORDER BY org)
+        INTO orgs
+        FROM (SELECT org FROM teams
+              UNION ALL SELECT org FROM users
+              UNION ALL SELECT org FROM pull_requests
+              UNION ALL SELECT org FROM pull_requests_archive
+              UNION ALL SELECT org FROM idempotency_keys) AS o
+        WHERE org <> 'default';
+        IF orgs IS NOT NULL THEN
+            RAISE EXCEPTION 'organizations cannot be removed while they own data: %', orgs;
+        END IF;
+    END
+$$;
+
+CREATE OR REPLACE FUNCTION reviewers_check_author() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF EXISTS(SELECT 1 FROM pull_requests WHERE id = NEW.pull_request_id AND author_id = NEW.user_id) THEN
+        RAISE EXCEPTION 'user % is the author of pull request % and cannot review it', NEW.user_id, NEW.pull_request_id
+            USING ERRCODE = 'check_violation';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION pull_requests_check_author() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF EXISTS(SELECT 1 FROM reviewers WHERE pull_request_id = NEW.id AND user_id = NEW.author_id) THEN
+        RAISE EXCEPTION 'user % reviews pull request % and cannot become its author', NEW.author_id, NEW.id
+            USING ERRCODE = 'check_violation';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION reviewers_forbid_merged() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        IF EXISTS(SELECT 1 FROM pull_requests WHERE id = OLD.pull_request_id AND status = 'MERGED') THEN
+            RAISE EXCEPTION 'pull request % is merged and its reviewers cannot change', OLD.pull_request_id
+                USING ERRCODE = 'check_violation';
+        END IF;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        IF EXISTS(SELECT 1 FROM pull_requests WHERE id = NEW.pull_request_id AND status = 'MERGED') THEN
+            RAISE EXCEPTION 'pull request % is merged and its reviewers cannot change', NEW.pull_request_id
+                USING ERRCODE = 'check_violation';
+        END IF;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS idx_reviewers_org_pull_request_id;
+CREATE INDEX IF NOT EXISTS idx_reviewers_pull_request_id ON reviewers (pull_request_id);
+DROP INDEX IF EXISTS idx_pull_requests_org_author_id;
+CREATE INDEX IF NOT EXISTS idx_pull_requests_author_id ON pull_requests (author_id);
+DROP INDEX IF EXISTS idx_users_org_team_name;
+CREATE INDEX IF NOT EXISTS idx_users_team_name ON users (team_name);
+
+ALTER TABLE users
+    DROP CONSTRAINT users_team_name_fkey;
+ALTER TABLE pull_requests
+    DROP CONSTRAINT pull_requests_author_id_fkey;
+ALTER TABLE reviewers
+    DROP CONSTRAINT reviewers_user_id_fkey,
+    DROP CONSTRAINT reviewers_pull_request_id_fkey;
+ALTER TABLE pull_requests_archive
+    DROP CONSTRAINT pull_requests_archive_author_id_fkey;
+ALTER TABLE reviewers_archive
+    DROP CONSTRAINT reviewers_archive_user_id_fkey,
+    DROP CONSTRAINT reviewers_archive_pull_request_id_archived_at_fkey;
+ALTER TABLE review_stats
+    DROP CONSTRAINT review_stats_user_id_fkey;
+
+ALTER TABLE teams
+    DROP CONSTRAINT teams_pkey,
+    ADD CONSTRAINT teams_pkey PRIMARY KEY (name);
+ALTER TABLE users
+    DROP CONSTRAINT users_pkey,
+    ADD CONSTRAINT users_pkey PRIMARY KEY (id);
+ALTER TABLE pull_requests
+    DROP CONSTRAINT pull_requests_pkey,
+    ADD CONSTRAINT pull_requests_pkey PRIMARY KEY (id);
+ALTER TABLE reviewers
+    DROP CONSTRAINT reviewers_pkey,
+    ADD CONSTRAINT reviewers_pkey PRIMARY KEY (user_id, pull_request_id);
+ALTER TABLE pull_requests_archive
+    DROP CONSTRAINT pull_requests_archive_pkey,
+    ADD CONSTRAINT pull_requests_archive_pkey PRIMARY KEY (id, archived_at);
+ALTER TABLE reviewers_archive
+    DROP CONSTRAINT reviewers_archive_pkey,
+    ADD CONSTRAINT reviewers_archive_pkey PRIMARY KEY (pull_request_id, archived_at, user_id);
+ALTER TABLE review_stats
+    DROP CONSTRAINT review_stats_pkey,
+    ADD CONSTRAINT review_stats_pkey PRIMARY KEY (user_id);
+ALTER TABLE idempotency_keys
+    DROP CONSTRAINT idempotency_keys_pkey,
+    ADD CONSTRAINT idempotency_keys_pkey PRIMARY KEY (scope, key);
+
+ALTER TABLE users
+    ADD CONSTRAINT users_team_name_fkey FOREIGN KEY (team_name) REFERENCES teams (name) ON DELETE SET NULL;
+ALTER TABLE pull_requests
+    ADD CONSTRAINT pull_requests_author_id_fkey FOREIGN KEY (author_id) REFERENCES users (id) ON DELETE CASCADE;
+ALTER TABLE reviewers
+    ADD CONSTRAINT reviewers_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    ADD CONSTRAINT reviewers_pull_request_id_fkey FOREIGN KEY (pull_request_id)
+        REFERENCES pull_requests (id) ON DELETE CASCADE;
+ALTER TABLE pull_requests_archive
+    ADD CONSTRAINT pull_requests_archive_author_id_fkey FOREIGN KEY (author_id) REFERENCES users (id);
+ALTER TABLE reviewers_archive
+    ADD CONSTRAINT reviewers_archive_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id),
+    ADD CONSTRAINT reviewers_archive_pull_request_id_archived_at_fkey FOREIGN KEY (pull_request_id, archived_at)
+        REFERENCES pull_requests_archive (id, archived_at) ON DELETE CASCADE;
+ALTER TABLE review_stats
+    ADD CONSTRAINT review_stats_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE teams
+    DROP COLUMN org;
+ALTER TABLE users
+    DROP COLUMN org;
+ALTER TABLE pull_requests
+    DROP COLUMN org;
+ALTER TABLE reviewers
+    DROP COLUMN org;
+ALTER TABLE pull_requests_archive
+    DROP COLUMN org;
+ALTER TABLE reviewers_archive
+    DROP COLUMN org;
+ALTER TABLE review_stats
+    DROP COLUMN org;
+ALTER TABLE idempotency_keys
+    DROP COLUMN org;
\ No newline at end of file
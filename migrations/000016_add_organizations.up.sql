@@ -0,0 +1,163 @@
+-- Every existing row belongs to the default organization. Ids only have to be unique within
+-- an organization, so keys, foreign keys and the review triggers are rebuilt around org.
+ALTER TABLE teams
+    ADD COLUMN org TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE users
+    ADD COLUMN org TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE pull_requests
+    ADD COLUMN org TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE reviewers
+    ADD COLUMN org TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE pull_requests_archive
+    ADD COLUMN org TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE reviewers_archive
+    ADD COLUMN org TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE review_stats
+    ADD COLUMN org TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE idempotency_keys
+    ADD COLUMN org TEXT NOT NULL DEFAULT 'default';
+
+ALTER TABLE users
+    DROP CONSTRAINT users_team_name_fkey;
+ALTER TABLE pull_requests
+    DROP CONSTRAINT pull_requests_author_id_fkey;
+ALTER TABLE reviewers
+    DROP CONSTRAINT reviewers_user_id_fkey,
+    DROP CONSTRAINT reviewers_pull_request_id_fkey;
+ALTER TABLE pull_requests_archive
+    DROP CONSTRAINT pull_requests_archive_author_id_fkey;
+ALTER TABLE reviewers_archive
+    DROP CONSTRAINT reviewers_archive_user_id_fkey,
+    DROP CONSTRAINT reviewers_archive_pull_request_id_archived_at_fkey;
+ALTER TABLE review_stats
+    DROP CONSTRAINT review_stats_user_id_fkey;
+
+ALTER TABLE teams
+    DROP CONSTRAINT teams_pkey,
+    ADD CONSTRAINT teams_pkey PRIMARY KEY (org, name);
+ALTER TABLE users
+    DROP CONSTRAINT users_pkey,
+    ADD CONSTRAINT users_pkey PRIMARY KEY (org, id);
+ALTER TABLE pull_requests
+    DROP CONSTRAINT pull_requests_pkey,
+    ADD CONSTRAINT pull_requests_pkey PRIMARY KEY (org, id);
+ALTER TABLE reviewers
+    DROP CONSTRAINT reviewers_pkey,
+    ADD CONSTRAINT reviewers_pkey PRIMARY KEY (org, user_id, pull_request_id);
+ALTER TABLE pull_requests_archive
+    DROP CONSTRAINT pull_requests_archive_pkey,
+    ADD CONSTRAINT pull_requests_archive_pkey PRIMARY KEY (org, id, archived_at);
+ALTER TABLE reviewers_archive
+    DROP CONSTRAINT reviewers_archive_pkey,
+    ADD CONSTRAINT reviewers_archive_pkey PRIMARY KEY (org, pull_request_id, archived_at, user_id);
+ALTER TABLE review_stats
+    DROP CONSTRAINT review_stats_pkey,
+    ADD CONSTRAINT review_stats_pkey PRIMARY KEY (org, user_id);
+ALTER TABLE idempotency_keys
+    DROP CONSTRAINT idempotency_keys_pkey,
+    ADD CONSTRAINT idempotency_keys_pkey PRIMARY KEY (org, scope, key);
+
+ALTER TABLE users
+    ADD CONSTRAINT users_team_name_fkey FOREIGN KEY (org, team_name)
+        REFERENCES teams (org, name) ON DELETE SET NULL (team_name);
+ALTER TABLE pull_requests
+    ADD CONSTRAINT pull_requests_author_id_fkey FOREIGN KEY (org, author_id)
+        REFERENCES users (org, id) ON DELETE CASCADE;
+ALTER TABLE reviewers
+    ADD CONSTRAINT reviewers_user_id_fkey FOREIGN KEY (org, user_id)
+        REFERENCES users (org, id) ON DELETE CASCADE,
+    ADD CONSTRAINT reviewers_pull_request_id_fkey FOREIGN KEY (org, pull_request_id)
+        REFERENCES pull_requests (org, id) ON DELETE CASCADE;
+ALTER TABLE pull_requests_archive
+    ADD CONSTRAINT pull_requests_archive_author_id_fkey FOREIGN KEY (org, author_id)
+        REFERENCES users (org, id);
+ALTER TABLE reviewers_archive
+    ADD CONSTRAINT reviewers_archive_user_id_fkey FOREIGN KEY (org, user_id)
+        REFERENCES users (org, id),
+    ADD CONSTRAINT reviewers_archive_pull_request_id_archived_at_fkey FOREIGN KEY (org, pull_request_id, archived_at)
+        REFERENCES pull_requests_archive (org, id, archived_at) ON DELETE CASCADE;
+ALTER TABLE review_stats
+    ADD CONSTRAINT review_stats_user_id_fkey FOREIGN KEY (org, user_id)
+        REFERENCES users (org, id) ON DELETE CASCADE;
+
+-- New rows must name their organization explicitly
+ALTER TABLE teams
+    ALTER COLUMN org DROP DEFAULT;
+ALTER TABLE users
+    ALTER COLUMN org DROP DEFAULT;
+ALTER TABLE pull_requests
+    ALTER COLUMN org DROP DEFAULT;
+ALTER TABLE reviewers
+    ALTER COLUMN org DROP DEFAULT;
+ALTER TABLE pull_requests_archive
+    ALTER COLUMN org DROP DEFAULT;
+ALTER TABLE reviewers_archive
+    ALTER COLUMN org DROP DEFAULT;
+ALTER TABLE review_stats
+    ALTER COLUMN org DROP DEFAULT;
+ALTER TABLE idempotency_keys
+    ALTER COLUMN org DROP DEFAULT;
+
+DROP INDEX IF EXISTS idx_users_team_name;
+CREATE INDEX IF NOT EXISTS idx_users_org_team_name ON users (org, team_name);
+DROP INDEX IF EXISTS idx_pull_requests_author_id;
+CREATE INDEX IF NOT EXISTS idx_pull_requests_org_author_id ON pull_requests (org, author_id);
+DROP INDEX IF EXISTS idx_reviewers_pull_request_id;
+CREATE INDEX IF NOT EXISTS idx_reviewers_org_pull_request_id ON reviewers (org, pull_request_id);
+
+CREATE OR REPLACE FUNCTION reviewers_check_author() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF EXISTS(SELECT 1
+              FROM pull_requests
+              WHERE org = NEW.org
+                AND id = NEW.pull_request_id
+                AND author_id = NEW.user_id) THEN
+        RAISE EXCEPTION 'user % is the author of pull request % and cannot review it', NEW.user_id, NEW.pull_request_id
+            USING ERRCODE = 'check_violation';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION pull_requests_check_author() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF EXISTS(SELECT 1
+              FROM reviewers
+              WHERE org = NEW.org
+                AND pull_request_id = NEW.id
+                AND user_id = NEW.author_id) THEN
+        RAISE EXCEPTION 'user % reviews pull request % and cannot become its author', NEW.author_id, NEW.id
+            USING ERRCODE = 'check_violation';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION reviewers_forbid_merged() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        IF EXISTS(SELECT 1
+                  FROM pull_requests
+                  WHERE org = OLD.org
+                    AND id = OLD.pull_request_id
+                    AND status = 'MERGED') THEN
+            RAISE EXCEPTION 'pull request % is merged and its reviewers cannot change', OLD.pull_request_id
+                USING ERRCODE = 'check_violation';
+        END IF;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        IF EXISTS(SELECT 1
+                  FROM pull_requests
+                  WHERE org = NEW.org
+                    AND id = NEW.pull_request_id
+                    AND status = 'MERGED') THEN
+            RAISE EXCEPTION 'pull request % is merged and its reviewers cannot change', NEW.pull_request_id
+                USING ERRCODE = 'check_violation';
+        END IF;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
\ No newline at end of file
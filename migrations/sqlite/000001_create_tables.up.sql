@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS teams
+(
+    name    TEXT PRIMARY KEY,
+    version INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE TABLE IF NOT EXISTS users
+(
+    id        TEXT PRIMARY KEY,
+    name      TEXT    NOT NULL,
+    team_name TEXT REFERENCES teams (name) ON DELETE SET NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    role      TEXT    NOT NULL DEFAULT 'member'
+        CHECK (role IN ('lead', 'member', 'observer'))
+);
+
+CREATE TABLE IF NOT EXISTS pull_requests
+(
+    id                  TEXT PRIMARY KEY,
+    name                TEXT      NOT NULL,
+    description         TEXT      NOT NULL DEFAULT '',
+    labels              TEXT      NOT NULL DEFAULT '[]',
+    author_id           TEXT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    status              TEXT      NOT NULL CHECK (status IN ('OPEN', 'MERGED')),
+    need_more_reviewers BOOLEAN   NOT NULL DEFAULT FALSE,
+    created_at          TIMESTAMP NOT NULL,
+    merged_at           TIMESTAMP,
+    version             INTEGER   NOT NULL DEFAULT 1
+);
+
+CREATE TABLE IF NOT EXISTS reviewers
+(
+    user_id         TEXT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    pull_request_id TEXT      NOT NULL REFERENCES pull_requests (id) ON DELETE CASCADE,
+    assigned_at     TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, pull_request_id)
+);
\ No newline at end of file
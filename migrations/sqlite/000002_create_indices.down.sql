@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_reviewers_user_id_assigned_at;
+DROP INDEX IF EXISTS idx_reviewers_pull_request_id;
+DROP INDEX IF EXISTS idx_pull_requests_status;
+DROP INDEX IF EXISTS idx_pull_requests_created_at_id;
+DROP INDEX IF EXISTS idx_pull_requests_author_id;
+DROP INDEX IF EXISTS idx_users_name_id;
+DROP INDEX IF EXISTS idx_users_team_name;
\ No newline at end of file
@@ -0,0 +1,7 @@
+CREATE INDEX IF NOT EXISTS idx_users_team_name ON users (team_name);
+CREATE INDEX IF NOT EXISTS idx_users_name_id ON users (name, id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_author_id ON pull_requests (author_id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_created_at_id ON pull_requests (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_status ON pull_requests (status);
+CREATE INDEX IF NOT EXISTS idx_reviewers_pull_request_id ON reviewers (pull_request_id);
+CREATE INDEX IF NOT EXISTS idx_reviewers_user_id_assigned_at ON reviewers (user_id, assigned_at, pull_request_id);
\ No newline at end of file
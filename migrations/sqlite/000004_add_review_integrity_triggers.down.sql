@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS reviewers_forbid_merged_delete;
+DROP TRIGGER IF EXISTS reviewers_forbid_merged_update;
+DROP TRIGGER IF EXISTS reviewers_forbid_merged_insert;
+DROP TRIGGER IF EXISTS pull_requests_check_author;
+DROP TRIGGER IF EXISTS reviewers_check_author_update;
+DROP TRIGGER IF EXISTS reviewers_check_author_insert;
\ No newline at end of file
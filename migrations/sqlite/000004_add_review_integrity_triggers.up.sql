@@ -0,0 +1,78 @@
+-- teams already has a primary key and users.is_active is NOT NULL here; only the review rules are new.
+-- Report the legacy rows that break them instead of installing triggers over bad data.
+CREATE TEMP TABLE integrity_problems
+(
+    problem TEXT NOT NULL
+);
+
+CREATE TEMP TRIGGER integrity_problems_abort
+    BEFORE INSERT
+    ON integrity_problems
+BEGIN
+    SELECT RAISE(ABORT, 'integrity constraints cannot be applied: ' || NEW.problem);
+END;
+
+INSERT INTO integrity_problems
+SELECT 'pull requests reviewed by their author: ' || group_concat(r.pull_request_id || ' (' || r.user_id || ')', ', ')
+FROM reviewers r
+         JOIN pull_requests pr ON pr.id = r.pull_request_id
+WHERE r.user_id = pr.author_id
+HAVING count(*) > 0;
+
+DROP TABLE integrity_problems;
+
+CREATE TRIGGER IF NOT EXISTS reviewers_check_author_insert
+    BEFORE INSERT
+    ON reviewers
+    WHEN EXISTS(SELECT 1 FROM pull_requests WHERE id = NEW.pull_request_id AND author_id = NEW.user_id)
+BEGIN
+    SELECT RAISE(ABORT, 'user ' || NEW.user_id || ' is the author of pull request ' || NEW.pull_request_id ||
+                        ' and cannot review it');
+END;
+
+CREATE TRIGGER IF NOT EXISTS reviewers_check_author_update
+    BEFORE UPDATE
+    ON reviewers
+    WHEN EXISTS(SELECT 1 FROM pull_requests WHERE id = NEW.pull_request_id AND author_id = NEW.user_id)
+BEGIN
+    SELECT RAISE(ABORT, 'user ' || NEW.user_id || ' is the author of pull request ' || NEW.pull_request_id ||
+                        ' and cannot review it');
+END;
+
+CREATE TRIGGER IF NOT EXISTS pull_requests_check_author
+    BEFORE UPDATE OF author_id
+    ON pull_requests
+    WHEN EXISTS(SELECT 1 FROM reviewers WHERE pull_request_id = NEW.id AND user_id = NEW.author_id)
+BEGIN
+    SELECT RAISE(ABORT, 'user ' || NEW.author_id || ' reviews pull request ' || NEW.id ||
+                        ' and cannot become its author');
+END;
+
+-- Reviewers of a merged pull request are history. Rows removed together with their pull request
+-- are allowed: the pull request is already gone when the cascade reaches reviewers.
+CREATE TRIGGER IF NOT EXISTS reviewers_forbid_merged_insert
+    BEFORE INSERT
+    ON reviewers
+    WHEN EXISTS(SELECT 1 FROM pull_requests WHERE id = NEW.pull_request_id AND status = 'MERGED')
+BEGIN
+    SELECT RAISE(ABORT, 'pull request ' || NEW.pull_request_id || ' is merged and its reviewers cannot change');
+END;
+
+CREATE TRIGGER IF NOT EXISTS reviewers_forbid_merged_update
+    BEFORE UPDATE
+    ON reviewers
+    WHEN EXISTS(SELECT 1
+                FROM pull_requests
+                WHERE id IN (OLD.pull_request_id, NEW.pull_request_id)
+                  AND status = 'MERGED')
+BEGIN
+    SELECT RAISE(ABORT, 'pull request ' || OLD.pull_request_id || ' is merged and its reviewers cannot change');
+END;
+
+CREATE TRIGGER IF NOT EXISTS reviewers_forbid_merged_delete
+    AFTER DELETE
+    ON reviewers
+    WHEN EXISTS(SELECT 1 FROM pull_requests WHERE id = OLD.pull_request_id AND status = 'MERGED')
+BEGIN
+    SELECT RAISE(ABORT, 'pull request ' || OLD.pull_request_id || ' is merged and its reviewers cannot change');
+END;
\ No newline at end of file
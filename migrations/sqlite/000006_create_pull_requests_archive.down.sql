@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS review_stats;
+DROP TABLE IF EXISTS reviewers_archive;
+DROP TABLE IF EXISTS pull_requests_archive;
\ No newline at end of file
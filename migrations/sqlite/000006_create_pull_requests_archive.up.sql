@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS pull_requests_archive
+(
+    id          TEXT      NOT NULL,
+    name        TEXT      NOT NULL,
+    description TEXT      NOT NULL DEFAULT '',
+    labels      TEXT      NOT NULL DEFAULT '[]',
+    author_id   TEXT      NOT NULL REFERENCES users (id),
+    created_at  TIMESTAMP NOT NULL,
+    merged_at   TIMESTAMP NOT NULL,
+    archived_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (id, archived_at)
+);
+
+CREATE TABLE IF NOT EXISTS reviewers_archive
+(
+    user_id         TEXT      NOT NULL REFERENCES users (id),
+    pull_request_id TEXT      NOT NULL,
+    archived_at     TIMESTAMP NOT NULL,
+    assigned_at     TIMESTAMP NOT NULL,
+    PRIMARY KEY (pull_request_id, archived_at, user_id),
+    FOREIGN KEY (pull_request_id, archived_at) REFERENCES pull_requests_archive (id, archived_at) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS review_stats
+(
+    user_id  TEXT PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    authored INTEGER NOT NULL DEFAULT 0,
+    reviewed INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_archive_author_id ON pull_requests_archive (author_id);
+CREATE INDEX IF NOT EXISTS idx_reviewers_archive_user_id ON reviewers_archive (user_id);
\ No newline at end of file
@@ -0,0 +1,216 @@
+-- Without org the ids of different organizations would collide, so only a deployment that
+-- never served another organization can be rolled back.
+CREATE TEMP TABLE organization_problems
+(
+    problem TEXT NOT NULL
+);
+
+CREATE TEMP TRIGGER organization_problems_abort
+    BEFORE INSERT
+    ON organization_problems
+BEGIN
+    SELECT RAISE(ABORT, 'organizations cannot be removed while they own data: ' || NEW.problem);
+END;
+
+INSERT INTO organization_problems
+SELECT group_concat(org, ', ')
+FROM (SELECT org FROM teams
+      UNION SELECT org FROM users
+      UNION SELECT org FROM pull_requests
+      UNION SELECT org FROM pull_requests_archive
+      UNION SELECT org FROM idempotency_keys)
+WHERE org <> 'default'
+HAVING count(*) > 0;
+
+DROP TABLE organization_problems;
+
+ALTER TABLE teams RENAME TO teams_old;
+ALTER TABLE users RENAME TO users_old;
+ALTER TABLE pull_requests RENAME TO pull_requests_old;
+ALTER TABLE reviewers RENAME TO reviewers_old;
+ALTER TABLE pull_requests_archive RENAME TO pull_requests_archive_old;
+ALTER TABLE reviewers_archive RENAME TO reviewers_archive_old;
+ALTER TABLE review_stats RENAME TO review_stats_old;
+ALTER TABLE idempotency_keys RENAME TO idempotency_keys_old;
+
+CREATE TABLE teams
+(
+    name    TEXT PRIMARY KEY,
+    version INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE TABLE users
+(
+    id         TEXT PRIMARY KEY,
+    name       TEXT    NOT NULL,
+    team_name  TEXT REFERENCES teams (name) ON DELETE SET NULL,
+    is_active  BOOLEAN NOT NULL DEFAULT TRUE,
+    role       TEXT    NOT NULL DEFAULT 'member'
+        CHECK (role IN ('lead', 'member', 'observer')),
+    deleted_at TIMESTAMP
+);
+
+CREATE TABLE pull_requests
+(
+    id                  TEXT PRIMARY KEY,
+    name                TEXT      NOT NULL,
+    description         TEXT      NOT NULL DEFAULT '',
+    labels              TEXT      NOT NULL DEFAULT '[]',
+    author_id           TEXT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    status              TEXT      NOT NULL CHECK (status IN ('OPEN', 'MERGED')),
+    need_more_reviewers BOOLEAN   NOT NULL DEFAULT FALSE,
+    created_at          TIMESTAMP NOT NULL,
+    merged_at           TIMESTAMP,
+    version             INTEGER   NOT NULL DEFAULT 1
+);
+
+CREATE TABLE reviewers
+(
+    user_id         TEXT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    pull_request_id TEXT      NOT NULL REFERENCES pull_requests (id) ON DELETE CASCADE,
+    assigned_at     TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, pull_request_id)
+);
+
+CREATE TABLE pull_requests_archive
+(
+    id          TEXT      NOT NULL,
+    name        TEXT      NOT NULL,
+    description TEXT      NOT NULL DEFAULT '',
+    labels      TEXT      NOT NULL DEFAULT '[]',
+    author_id   TEXT      NOT NULL REFERENCES users (id),
+    created_at  TIMESTAMP NOT NULL,
+    merged_at   TIMESTAMP NOT NULL,
+    archived_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (id, archived_at)
+);
+
+CREATE TABLE reviewers_archive
+(
+    user_id         TEXT      NOT NULL REFERENCES users (id),
+    pull_request_id TEXT      NOT NULL,
+    archived_at     TIMESTAMP NOT NULL,
+    assigned_at     TIMESTAMP NOT NULL,
+    PRIMARY KEY (pull_request_id, archived_at, user_id),
+    FOREIGN KEY (pull_request_id, archived_at) REFERENCES pull_requests_archive (id, archived_at) ON DELETE CASCADE
+);
+
+CREATE TABLE review_stats
+(
+    user_id  TEXT PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    authored INTEGER NOT NULL DEFAULT 0,
+    reviewed INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE idempotency_keys
+(
+    scope         TEXT      NOT NULL,
+    key           TEXT      NOT NULL,
+    request_hash  TEXT      NOT NULL,
+    status_code   INTEGER,
+    response_body BLOB,
+    created_at    TIMESTAMP NOT NULL,
+    expires_at    TIMESTAMP NOT NULL,
+    PRIMARY KEY (scope, key)
+);
+
+INSERT INTO teams (name, version)
+SELECT name, version
+FROM teams_old;
+INSERT INTO users (id, name, team_name, is_active, role, deleted_at)
+SELECT id, name, team_name, is_active, role, deleted_at
+FROM users_old;
+INSERT INTO pull_requests (id, name, description, labels, author_id, status, need_more_reviewers,
+                           created_at, merged_at, version)
+SELECT id, name, description, labels, author_id, status, need_more_reviewers,
+       created_at, merged_at, version
+FROM pull_requests_old;
+INSERT INTO reviewers (user_id, pull_request_id, assigned_at)
+SELECT user_id, pull_request_id, assigned_at
+FROM reviewers_old;
+INSERT INTO pull_requests_archive (id, name, description, labels, author_id, created_at, merged_at, archived_at)
+SELECT id, name, description, labels, author_id, created_at, merged_at, archived_at
+FROM pull_requests_archive_old;
+INSERT INTO reviewers_archive (user_id, pull_request_id, archived_at, assigned_at)
+SELECT user_id, pull_request_id, archived_at, assigned_at
+FROM reviewers_archive_old;
+INSERT INTO review_stats (user_id, authored, reviewed)
+SELECT user_id, authored, reviewed
+FROM review_stats_old;
+INSERT INTO idempotency_keys (scope, key, request_hash, status_code, response_body, created_at, expires_at)
+SELECT scope, key, request_hash, status_code, response_body, created_at, expires_at
+FROM idempotency_keys_old;
+
+DROP TABLE reviewers_old;
+DROP TABLE pull_requests_old;
+DROP TABLE reviewers_archive_old;
+DROP TABLE pull_requests_archive_old;
+DROP TABLE review_stats_old;
+DROP TABLE users_old;
+DROP TABLE teams_old;
+DROP TABLE idempotency_keys_old;
+
+CREATE INDEX IF NOT EXISTS idx_users_team_name ON users (team_name);
+CREATE INDEX IF NOT EXISTS idx_users_name_id ON users (name, id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_author_id ON pull_requests (author_id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_created_at_id ON pull_requests (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_status ON pull_requests (status);
+CREATE INDEX IF NOT EXISTS idx_reviewers_pull_request_id ON reviewers (pull_request_id);
+CREATE INDEX IF NOT EXISTS idx_reviewers_user_id_assigned_at ON reviewers (user_id, assigned_at, pull_request_id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_archive_author_id ON pull_requests_archive (author_id);
+CREATE INDEX IF NOT EXISTS idx_reviewers_archive_user_id ON reviewers_archive (user_id);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
+
+CREATE TRIGGER IF NOT EXISTS reviewers_check_author_insert
+    BEFORE INSERT
+    ON reviewers
+    WHEN EXISTS(SELECT 1 FROM pull_requests WHERE id = NEW.pull_request_id AND author_id = NEW.user_id)
+BEGIN
+    SELECT RAISE(ABORT, 'user ' || NEW.user_id || ' is the author of pull request ' || NEW.pull_request_id ||
+                        ' and cannot review it');
+END;
+
+CREATE TRIGGER IF NOT EXISTS reviewers_check_author_update
+    BEFORE UPDATE
+    ON reviewers
+    WHEN EXISTS(SELECT 1 FROM pull_requests WHERE id = NEW.pull_request_id AND author_id = NEW.user_id)
+BEGIN
+    SELECT RAISE(ABORT, 'user ' || NEW.user_id || ' is the author of pull request ' || NEW.pull_request_id ||
+                        ' and cannot review it');
+END;
+
+CREATE TRIGGER IF NOT EXISTS pull_requests_check_author
+    BEFORE UPDATE OF author_id
+    ON pull_requests
+    WHEN EXISTS(SELECT 1 FROM reviewers WHERE pull_request_id = NEW.id AND user_id = NEW.author_id)
+BEGIN
+    SELECT RAISE(ABORT, 'user ' || NEW.author_id || ' reviews pull request ' || NEW.id ||
+                        ' and cannot become its author');
+END;
+
+CREATE TRIGGER IF NOT EXISTS reviewers_forbid_merged_insert
+    BEFORE INSERT
+    ON reviewers
+    WHEN EXISTS(SELECT 1 FROM pull_requests WHERE id = NEW.pull_request_id AND status = 'MERGED')
+BEGIN
+    SELECT RAISE(ABORT, 'pull request ' || NEW.pull_request_id || ' is merged and its reviewers cannot change');
+END;
+
+CREATE TRIGGER IF NOT EXISTS reviewers_forbid_merged_update
+    BEFORE UPDATE
+    ON reviewers
+    WHEN EXISTS(SELECT 1
+                FROM pull_requests
+                WHERE id IN (OLD.pull_request_id, NEW.pull_request_id)
+                  AND status = 'MERGED')
+BEGIN
+    SELECT RAISE(ABORT, 'pull request ' || OLD.pull_request_id || ' is merged and its reviewers cannot change');
+END;
+
+CREATE TRIGGER IF NOT EXISTS reviewers_forbid_merged_delete
+    AFTER DELETE
+    ON reviewers
+    WHEN EXISTS(SELECT 1 FROM pull_requests WHERE id = OLD.pull_request_id AND status = 'MERGED')
+BEGIN
+    SELECT RAISE(ABORT, 'pull request ' || OLD.pull_request_id || ' is merged and its reviewers cannot change');
+END;
\ No newline at end of file
@@ -0,0 +1,224 @@
+-- Every existing row belongs to the default organization. SQLite cannot change a primary key
+-- in place, so the tables are rebuilt with org in their keys; foreign keys are not enforced
+-- while migrations run.
+ALTER TABLE teams RENAME TO teams_old;
+ALTER TABLE users RENAME TO users_old;
+ALTER TABLE pull_requests RENAME TO pull_requests_old;
+ALTER TABLE reviewers RENAME TO reviewers_old;
+ALTER TABLE pull_requests_archive RENAME TO pull_requests_archive_old;
+ALTER TABLE reviewers_archive RENAME TO reviewers_archive_old;
+ALTER TABLE review_stats RENAME TO review_stats_old;
+ALTER TABLE idempotency_keys RENAME TO idempotency_keys_old;
+
+CREATE TABLE teams
+(
+    org     TEXT    NOT NULL,
+    name    TEXT    NOT NULL,
+    version INTEGER NOT NULL DEFAULT 1,
+    PRIMARY KEY (org, name)
+);
+
+CREATE TABLE users
+(
+    org        TEXT    NOT NULL,
+    id         TEXT    NOT NULL,
+    name       TEXT    NOT NULL,
+    team_name  TEXT,
+    is_active  BOOLEAN NOT NULL DEFAULT TRUE,
+    role       TEXT    NOT NULL DEFAULT 'member'
+        CHECK (role IN ('lead', 'member', 'observer')),
+    deleted_at TIMESTAMP,
+    PRIMARY KEY (org, id),
+    FOREIGN KEY (org, team_name) REFERENCES teams (org, name)
+);
+
+CREATE TABLE pull_requests
+(
+    org                 TEXT      NOT NULL,
+    id                  TEXT      NOT NULL,
+    name                TEXT      NOT NULL,
+    description         TEXT      NOT NULL DEFAULT '',
+    labels              TEXT      NOT NULL DEFAULT '[]',
+    author_id           TEXT      NOT NULL,
+    status              TEXT      NOT NULL CHECK (status IN ('OPEN', 'MERGED')),
+    need_more_reviewers BOOLEAN   NOT NULL DEFAULT FALSE,
+    created_at          TIMESTAMP NOT NULL,
+    merged_at           TIMESTAMP,
+    version             INTEGER   NOT NULL DEFAULT 1,
+    PRIMARY KEY (org, id),
+    FOREIGN KEY (org, author_id) REFERENCES users (org, id) ON DELETE CASCADE
+);
+
+CREATE TABLE reviewers
+(
+    org             TEXT      NOT NULL,
+    user_id         TEXT      NOT NULL,
+    pull_request_id TEXT      NOT NULL,
+    assigned_at     TIMESTAMP NOT NULL,
+    PRIMARY KEY (org, user_id, pull_request_id),
+    FOREIGN KEY (org, user_id) REFERENCES users (org, id) ON DELETE CASCADE,
+    FOREIGN KEY (org, pull_request_id) REFERENCES pull_requests (org, id) ON DELETE CASCADE
+);
+
+CREATE TABLE pull_requests_archive
+(
+    org         TEXT      NOT NULL,
+    id          TEXT      NOT NULL,
+    name        TEXT      NOT NULL,
+    description TEXT      NOT NULL DEFAULT '',
+    labels      TEXT      NOT NULL DEFAULT '[]',
+    author_id   TEXT      NOT NULL,
+    created_at  TIMESTAMP NOT NULL,
+    merged_at   TIMESTAMP NOT NULL,
+    archived_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (org, id, archived_at),
+    FOREIGN KEY (org, author_id) REFERENCES users (org, id)
+);
+
+CREATE TABLE reviewers_archive
+(
+    org             TEXT      NOT NULL,
+    user_id         TEXT      NOT NULL,
+    pull_request_id TEXT      NOT NULL,
+    archived_at     TIMESTAMP NOT NULL,
+    assigned_at     TIMESTAMP NOT NULL,
+    PRIMARY KEY (org, pull_request_id, archived_at, user_id),
+    FOREIGN KEY (org, user_id) REFERENCES users (org, id),
+    FOREIGN KEY (org, pull_request_id, archived_at)
+        REFERENCES pull_requests_archive (org, id, archived_at) ON DELETE CASCADE
+);
+
+CREATE TABLE review_stats
+(
+    org      TEXT    NOT NULL,
+    user_id  TEXT    NOT NULL,
+    authored INTEGER NOT NULL DEFAULT 0,
+    reviewed INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (org, user_id),
+    FOREIGN KEY (org, user_id) REFERENCES users (org, id) ON DELETE CASCADE
+);
+
+CREATE TABLE idempotency_keys
+(
+    org           TEXT      NOT NULL,
+    scope         TEXT      NOT NULL,
+    key           TEXT      NOT NULL,
+    request_hash  TEXT      NOT NULL,
+    status_code   INTEGER,
+    response_body BLOB,
+    created_at    TIMESTAMP NOT NULL,
+    expires_at    TIMESTAMP NOT NULL,
+    PRIMARY KEY (org, scope, key)
+);
+
+INSERT INTO teams (org, name, version)
+SELECT 'default', name, version
+FROM teams_old;
+INSERT INTO users (org, id, name, team_name, is_active, role, deleted_at)
+SELECT 'default', id, name, team_name, is_active, role, deleted_at
+FROM users_old;
+INSERT INTO pull_requests (org, id, name, description, labels, author_id, status, need_more_reviewers,
+                           created_at, merged_at, version)
+SELECT 'default', id, name, description, labels, author_id, status, need_more_reviewers,
+       created_at, merged_at, version
+FROM pull_requests_old;
+INSERT INTO reviewers (org, user_id, pull_request_id, assigned_at)
+SELECT 'default', user_id, pull_request_id, assigned_at
+FROM reviewers_old;
+INSERT INTO pull_requests_archive (org, id, name, description, labels, author_id, created_at, merged_at, archived_at)
+SELECT 'default', id, name, description, labels, author_id, created_at, merged_at, archived_at
+FROM pull_requests_archive_old;
+INSERT INTO reviewers_archive (org, user_id, pull_request_id, archived_at, assigned_at)
+SELECT 'default', user_id, pull_request_id, archived_at, assigned_at
+FROM reviewers_archive_old;
+INSERT INTO review_stats (org, user_id, authored, reviewed)
+SELECT 'default', user_id, authored, reviewed
+FROM review_stats_old;
+INSERT INTO idempotency_keys (org, scope, key, request_hash, status_code, response_body, created_at, expires_at)
+SELECT 'default', scope, key, request_hash, status_code, response_body, created_at, expires_at
+FROM idempotency_keys_old;
+
+-- dropping the old tables also drops their indexes and triggers
+DROP TABLE reviewers_old;
+DROP TABLE pull_requests_old;
+DROP TABLE reviewers_archive_old;
+DROP TABLE pull_requests_archive_old;
+DROP TABLE review_stats_old;
+DROP TABLE users_old;
+DROP TABLE teams_old;
+DROP TABLE idempotency_keys_old;
+
+CREATE INDEX IF NOT EXISTS idx_users_team_name ON users (org, team_name);
+CREATE INDEX IF NOT EXISTS idx_users_name_id ON users (org, name, id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_author_id ON pull_requests (org, author_id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_created_at_id ON pull_requests (org, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_status ON pull_requests (org, status);
+CREATE INDEX IF NOT EXISTS idx_reviewers_pull_request_id ON reviewers (org, pull_request_id);
+CREATE INDEX IF NOT EXISTS idx_reviewers_user_id_assigned_at ON reviewers (org, user_id, assigned_at, pull_request_id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_archive_author_id ON pull_requests_archive (org, author_id);
+CREATE INDEX IF NOT EXISTS idx_reviewers_archive_user_id ON reviewers_archive (org, user_id);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
+
+CREATE TRIGGER IF NOT EXISTS reviewers_check_author_insert
+    BEFORE INSERT
+    ON reviewers
+    WHEN EXISTS(SELECT 1
+                FROM pull_requests
+                WHERE org = NEW.org AND id = NEW.pull_request_id AND author_id = NEW.user_id)
+BEGIN
+    SELECT RAISE(ABORT, 'user ' || NEW.user_id || ' is the author of pull request ' || NEW.pull_request_id ||
+                        ' and cannot review it');
+END;
+
+CREATE TRIGGER IF NOT EXISTS reviewers_check_author_update
+    BEFORE UPDATE
+    ON reviewers
+    WHEN EXISTS(SELECT 1
+                FROM pull_requests
+                WHERE org = NEW.org AND id = NEW.pull_request_id AND author_id = NEW.user_id)
+BEGIN
+    SELECT RAISE(ABORT, 'user ' || NEW.user_id || ' is the author of pull request ' || NEW.pull_request_id ||
+                        ' and cannot review it');
+END;
+
+CREATE TRIGGER IF NOT EXISTS pull_requests_check_author
+    BEFORE UPDATE OF author_id
+    ON pull_requests
+    WHEN EXISTS(SELECT 1
+                FROM reviewers
+                WHERE org = NEW.org AND pull_request_id = NEW.id AND user_id = NEW.author_id)
+BEGIN
+    SELECT RAISE(ABORT, 'user ' || NEW.author_id || ' reviews pull request ' || NEW.id ||
+                        ' and cannot become its author');
+END;
+
+CREATE TRIGGER IF NOT EXISTS reviewers_forbid_merged_insert
+    BEFORE INSERT
+    ON reviewers
+    WHEN EXISTS(SELECT 1
+                FROM pull_requests
+                WHERE org = NEW.org AND id = NEW.pull_request_id AND status = 'MERGED')
+BEGIN
+    SELECT RAISE(ABORT, 'pull request ' || NEW.pull_request_id || ' is merged and its reviewers cannot change');
+END;
+
+CREATE TRIGGER IF NOT EXISTS reviewers_forbid_merged_update
+    BEFORE UPDATE
+    ON reviewers
+    WHEN EXISTS(SELECT 1
+                FROM pull_requests
+                WHERE ((org = OLD.org AND id = OLD.pull_request_id) OR (org = NEW.org AND id = NEW.pull_request_id))
+                  AND status = 'MERGED')
+BEGIN
+    SELECT RAISE(ABORT, 'pull request ' || OLD.pull_request_id || ' is merged and its reviewers cannot change');
+END;
+
+CREATE TRIGGER IF NOT EXISTS reviewers_forbid_merged_delete
+    AFTER DELETE
+    ON reviewers
+    WHEN EXISTS(SELECT 1
+                FROM pull_requests
+                WHERE org = OLD.org AND id = OLD.pull_request_id AND status = 'MERGED')
+BEGIN
+    SELECT RAISE(ABORT, 'pull request ' || OLD.pull_request_id || ' is merged and its reviewers cannot change');
+END;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE idempotency_keys
+    ADD COLUMN response_headers TEXT;
\ No newline at end of file
@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConcurrentAssignments hammers create, reassign and deactivate in parallel and checks
+// that no request fails with a server error and that the reviewer invariants still hold.
+func TestConcurrentAssignments(t *testing.T) {
+	truncateAllTables(db)
+	ensureTeam(t, "backend", []string{"alice", "bob", "charlie", "dave", "erin", "frank", "grace", "heidi"})
+
+	const prCount = 24
+
+	for i := 0; i < prCount/2; i++ {
+		createOpenPR(t, fmt.Sprintf("pr-seed-%d", i), fmt.Sprintf("u%d", i%4+1))
+	}
+
+	seedReviewers := make([][]string, prCount/2)
+	for i := range seedReviewers {
+		seedReviewers[i] = reviewersInDB(t, fmt.Sprintf("pr-seed-%d", i))
+	}
+
+	type result struct {
+		status int
+		err    error
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results []result
+	)
+	// workers must not call t.FailNow, so they only record what they got
+	send := func(path string, body any) {
+		status, err := postAdmin(path, body)
+		mu.Lock()
+		results = append(results, result{status: status, err: err})
+		mu.Unlock()
+	}
+
+	for i := 0; i < prCount/2; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			send("/pullRequest/create", map[string]any{
+				"pull_request_id":   fmt.Sprintf("pr-new-%d", i),
+				"pull_request_name": "new",
+				"author_id":         fmt.Sprintf("u%d", i%8+1),
+			})
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			for _, reviewer := range seedReviewers[i] {
+				send("/pullRequest/reassign", map[string]any{
+					"pull_request_id": fmt.Sprintf("pr-seed-%d", i),
+					"old_reviewer_id": reviewer,
+				})
+			}
+		}(i)
+	}
+	for _, userID := range []string{"u5", "u6"} {
+		wg.Add(1)
+		go func(userID string) {
+			defer wg.Done()
+			send("/team/deactivate", map[string]any{"team_name": "backend", "users": []string{userID}})
+		}(userID)
+	}
+	wg.Wait()
+
+	for _, res := range results {
+		require.NoError(t, res.err)
+		assert.Less(t, res.status, http.StatusInternalServerError)
+	}
+
+	var violations int
+	err := db.QueryRow(`
+		SELECT COUNT(*) FROM reviewers rev
+		JOIN pull_requests pr ON rev.pull_request_id = pr.id
+		JOIN users u ON rev.user_id = u.id
+		WHERE rev.user_id = pr.author_id OR NOT u.is_active
+	`).Scan(&violations)
+	require.NoError(t, err)
+	assert.Zero(t, violations, "author or inactive user assigned as reviewer")
+
+	err = db.QueryRow(`
+		SELECT COUNT(*) FROM (
+			SELECT pull_request_id FROM reviewers GROUP BY pull_request_id HAVING COUNT(*) > 2
+		) overloaded
+	`).Scan(&violations)
+	require.NoError(t, err)
+	assert.Zero(t, violations, "pull request with more than two reviewers")
+}
+
+// postAdmin sends body to path as the admin and returns the response status. It reports
+// failures as errors instead of through t, so that it can be called from any goroutine.
+func postAdmin(path string, body any) (int, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Admin-Token", "admin")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	_ = resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
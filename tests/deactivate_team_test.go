@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeactivateTeam_MergedPRUntouched(t *testing.T) {
+	truncateAllTables(db)
+	ensureTeam(t, "backend", []string{"alice", "bob", "charlie", "dave"})
+
+	mergedReviewers := createOpenPR(t, "pr-merged", "u1")
+	require.Len(t, mergedReviewers, 2)
+	sort.Strings(mergedReviewers)
+
+	resp := mergePR(t, httpClient, "pr-merged")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+
+	openReviewers := createOpenPR(t, "pr-open", "u1")
+	require.Len(t, openReviewers, 2)
+
+	deactivated := mergedReviewers[0]
+
+	resp = deactivateTeamMembers(t, httpClient, "backend", []string{deactivated})
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var out DeactivateResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+
+	for _, pr := range out.PullRequests {
+		assert.NotEqual(t, "pr-merged", pr.PullRequestId, "merged PR must not be reassigned")
+	}
+
+	// Historical reviewers of the merged PR are preserved
+	assert.Equal(t, mergedReviewers, reviewersInDB(t, "pr-merged"))
+
+	// The open PR no longer has the deactivated reviewer
+	assert.NotContains(t, reviewersInDB(t, "pr-open"), deactivated)
+	for _, id := range openReviewers {
+		if id == deactivated {
+			require.Len(t, out.PullRequests, 1)
+			assert.Equal(t, "pr-open", out.PullRequests[0].PullRequestId)
+			assert.Equal(t, deactivated, out.PullRequests[0].OldReviewerId)
+		}
+	}
+}
+
+func TestDeactivateTeam_MergedPRKeepsReviewersWithoutCandidates(t *testing.T) {
+	truncateAllTables(db)
+	ensureTeam(t, "backend", []string{"alice", "bob", "charlie"})
+
+	reviewers := createOpenPR(t, "pr-merged", "u1")
+	require.Equal(t, []string{"u2", "u3"}, sortedCopy(reviewers))
+
+	resp := mergePR(t, httpClient, "pr-merged")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+
+	resp = deactivateTeamMembers(t, httpClient, "backend", []string{"u2", "u3"})
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var out DeactivateResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+
+	assert.Empty(t, out.PullRequests)
+	assert.Equal(t, []string{"u2", "u3"}, reviewersInDB(t, "pr-merged"))
+}
+
+func TestSetIsActive_DeactivationSkipsMergedPR(t *testing.T) {
+	truncateAllTables(db)
+	ensureTeam(t, "backend", []string{"alice", "bob", "charlie", "dave"})
+
+	mergedReviewers := sortedCopy(createOpenPR(t, "pr-merged", "u1"))
+
+	resp := mergePR(t, httpClient, "pr-merged")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+
+	resp = setUserIsActive(t, httpClient, mergedReviewers[0], false)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var out SetIsActiveResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+
+	assert.False(t, out.User.IsActive)
+	assert.Empty(t, out.PullRequests)
+	assert.Equal(t, mergedReviewers, reviewersInDB(t, "pr-merged"))
+}
+
+func sortedCopy(ids []string) []string {
+	out := append([]string(nil), ids...)
+	sort.Strings(out)
+	return out
+}
@@ -63,3 +63,27 @@ type TeamResponse struct {
 		Members []Member `json:"members"`
 	} `json:"team"`
 }
+
+type ReassignedPR struct {
+	PullRequestId string `json:"pull_request_id"`
+	OldReviewerId string `json:"old_reviewer_id"`
+	ReplacedBy    string `json:"replaced_by"`
+}
+
+type DeactivateResponse struct {
+	Team struct {
+		Name    string   `json:"team_name"`
+		Members []Member `json:"members"`
+	} `json:"team"`
+	PullRequests []ReassignedPR `json:"pull_requests"`
+}
+
+type SetIsActiveResponse struct {
+	User struct {
+		UserId   string `json:"user_id"`
+		Username string `json:"username"`
+		TeamName string `json:"team_name"`
+		IsActive bool   `json:"is_active"`
+	} `json:"user"`
+	PullRequests []ReassignedPR `json:"pull_requests"`
+}
@@ -124,3 +124,88 @@ func reassignUser(t *testing.T, httpClient *http.Client, oldUserID string, prID
 
 	return resp
 }
+
+func mergePR(t *testing.T, httpClient *http.Client, prID string) *http.Response {
+	data, err := json.Marshal(map[string]any{"pull_request_id": prID})
+	require.NoError(t, err)
+
+	req, err := http.NewRequest("POST", baseURL+"/pullRequest/merge", bytes.NewReader(data))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Admin-Token", "admin")
+
+	resp, err := httpClient.Do(req)
+	require.NoError(t, err)
+
+	return resp
+}
+
+func deactivateTeamMembers(t *testing.T, httpClient *http.Client, teamName string, userIDs []string) *http.Response {
+	data, err := json.Marshal(map[string]any{
+		"team_name": teamName,
+		"users":     userIDs,
+	})
+	require.NoError(t, err)
+
+	req, err := http.NewRequest("POST", baseURL+"/team/deactivate", bytes.NewReader(data))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Admin-Token", "admin")
+
+	resp, err := httpClient.Do(req)
+	require.NoError(t, err)
+
+	return resp
+}
+
+func setUserIsActive(t *testing.T, httpClient *http.Client, userID string, isActive bool) *http.Response {
+	data, err := json.Marshal(map[string]any{
+		"user_id":   userID,
+		"is_active": isActive,
+	})
+	require.NoError(t, err)
+
+	req, err := http.NewRequest("POST", baseURL+"/users/setIsActive", bytes.NewReader(data))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Admin-Token", "admin")
+
+	resp, err := httpClient.Do(req)
+	require.NoError(t, err)
+
+	return resp
+}
+
+func createOpenPR(t *testing.T, prID, authorID string) []string {
+	data, err := json.Marshal(map[string]any{
+		"pull_request_id":   prID,
+		"pull_request_name": prID,
+		"author_id":         authorID,
+	})
+	require.NoError(t, err)
+
+	resp := createPR(t, httpClient, baseURL, data)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var out CreatePRResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+
+	return out.PR.AssignedReviewers
+}
+
+func reviewersInDB(t *testing.T, prID string) []string {
+	rows, err := db.Query(`SELECT user_id FROM reviewers WHERE pull_request_id = $1 ORDER BY user_id`, prID)
+	require.NoError(t, err)
+	defer func() { _ = rows.Close() }()
+
+	var reviewers []string
+	for rows.Next() {
+		var id string
+		require.NoError(t, rows.Scan(&id))
+		reviewers = append(reviewers, id)
+	}
+	require.NoError(t, rows.Err())
+
+	return reviewers
+}